@@ -3,21 +3,26 @@ module recon
 go 1.24.6
 
 require (
+	github.com/go-sql-driver/mysql v1.9.3
+	github.com/lib/pq v1.12.3
+	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/onsi/gomega v1.38.2
-	github.com/samber/lo v1.51.0
 	github.com/xuri/excelize/v2 v2.9.1
+	go.etcd.io/bbolt v1.4.3
 	go.uber.org/mock v0.6.0
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
+	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/google/go-cmp v0.7.0 // indirect
 	github.com/richardlehane/mscfb v1.0.4 // indirect
 	github.com/richardlehane/msoleps v1.0.4 // indirect
 	github.com/tiendc/go-deepcopy v1.6.0 // indirect
 	github.com/xuri/efp v0.0.1 // indirect
 	github.com/xuri/nfp v0.0.1 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/crypto v0.41.0 // indirect
 	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 )
@@ -1,48 +1,351 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"recon/recon"
+	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 )
 
-const reconPath = "data/recon.xlsx"
+const (
+	reconPath      = "data/recon.xlsx"
+	htmlReportPath = "data/recon.html"
+	jsonReportPath = "data/recon.json"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serve(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		watch(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "schedule" {
+		schedule(os.Args[2:])
+		return
+	}
+
 	var transactionPath, bankStatementPaths string
-	var startDateStr, endDateStr string
+	var startDateStr, endDateStr, dateRange string
+	var reportFormats string
+	var ledgerPath string
+	var agingBuckets string
+	var escalateAfterDays int
+	var escalateAboveAmount float64
+	var overridesPath string
+	var balancesPath string
+	var bankAccounts string
+	var reportingCurrency string
+	var fxTolerance float64
+	var fxRatesPath string
+	var timezone string
+	var bankCutoffs string
+	var settlementDays int
+	var businessDays bool
+	var holidayPaths string
+	var duplicateKeys string
+	var duplicatePolicy string
+	var reversalWindow time.Duration
+	var loadWorkers int
+	var transactionDBDriver, transactionDBDSN string
+	var transactionQuery, transactionColumns string
+	var resultsDBDriver, resultsDBDSN string
 	flag.StringVar(&transactionPath, "transaction-path", "transaction.csv", "transactions CSV file path")
 	flag.StringVar(&bankStatementPaths, "bank-statement-paths", "bca.csv,bri.csv", "bank statements CSV file path")
-	flag.StringVar(&startDateStr, "start-date", time.Now().Format("2006-01-02"), "bank statements CSV file path")
-	flag.StringVar(&endDateStr, "end-date", time.Now().Format("2006-01-02"), "bank statements CSV file path")
+	flag.StringVar(&startDateStr, "start-date", "today", "first day of the recon, YYYY-MM-DD or relative, e.g. yesterday, 3 days ago, 2 business days ago, start of month")
+	flag.StringVar(&endDateStr, "end-date", "today", "last day of the recon, YYYY-MM-DD or relative like -start-date")
+	flag.StringVar(&dateRange, "range", "", "days of the recon instead of -start-date and -end-date, e.g. yesterday, month-to-date, last month, last 7 days, last 7 business days")
+	flag.StringVar(&reportFormats, "report-formats", "", "additional report formats besides xlsx, comma separated (html, json)")
+	flag.StringVar(&ledgerPath, "ledger-path", "", "ledger file carrying unmatched items across runs, disabled when empty")
+	flag.StringVar(&agingBuckets, "aging-buckets", "1,3,7,30", "upper bounds in days of the aging buckets, comma separated")
+	flag.IntVar(&escalateAfterDays, "escalate-after-days", 0, "flag unmatched items at least this many days old, disabled when 0")
+	flag.Float64Var(&escalateAboveAmount, "escalate-above-amount", 0, "flag unmatched items of at least this amount, in the reporting currency when one is set, disabled when 0")
+	flag.StringVar(&overridesPath, "overrides-path", "", "manual match, unmatch and exclude decisions (CSV or YAML), disabled when empty")
+	flag.StringVar(&balancesPath, "balances-path", "", "opening and closing balances declared per bank (CSV: bank,opening,closing), disabled when empty")
+	flag.StringVar(&bankAccounts, "bank-accounts", "", "bank and account of statement files, comma separated path=bank:account entries")
+	flag.StringVar(&reportingCurrency, "reporting-currency", "", "currency totals are reported in, required when inputs mix currencies")
+	flag.Float64Var(&fxTolerance, "fx-tolerance", 0, "largest relative difference still matched across currencies, disabled when 0")
+	flag.StringVar(&fxRatesPath, "fx-rates-path", "", "exchange rates (CSV: date,pair,rate), disabled when empty")
+	flag.StringVar(&timezone, "timezone", "UTC", "business time zone the start and end dates are days in, e.g. Asia/Jakarta")
+	flag.StringVar(&bankCutoffs, "bank-cutoffs", "", "time of day each bank books later lines on the next day, comma separated bank=HH:MM entries")
+	flag.IntVar(&settlementDays, "settlement-days", 0, "business days after a transaction within which its bank statement must be dated, disabled when 0")
+	flag.BoolVar(&businessDays, "business-days", false, "count settlement windows and aging in business days, skipping weekends and holidays")
+	flag.StringVar(&holidayPaths, "holiday-paths", "", "holidays (CSV: date,name[,bank]), comma separated, e.g. one file per year; implies -business-days")
+	flag.StringVar(&duplicateKeys, "duplicate-keys", "", "fields identifying repeated items, comma separated (id, amount-time, reference), disabled when empty")
+	flag.StringVar(&duplicatePolicy, "duplicate-policy", "flag", "what to do with repeated items: reject, keep-first or flag")
+	flag.DurationVar(&reversalWindow, "reversal-window", 0, "longest time from an item to its reversal on the same side, e.g. 72h, disabled when 0")
+	flag.IntVar(&loadWorkers, "load-workers", 4, "number of input files loaded at once")
+	flag.StringVar(&transactionDBDriver, "transaction-db-driver", "postgres", "database/sql driver of -transaction-db-dsn (postgres, mysql)")
+	flag.StringVar(&transactionDBDSN, "transaction-db-dsn", "", "database transactions are queried from instead of -transaction-path, disabled when empty")
+	flag.StringVar(&transactionQuery, "transaction-query", "", "query reading the transactions, taking the start and the end (excluded) of the period as parameters")
+	flag.StringVar(&transactionColumns, "transaction-columns", "", "result columns of -transaction-query holding each field, comma separated field=column entries (id, amount, type, time, currency)")
+	flag.StringVar(&resultsDBDriver, "results-db-driver", "postgres", "database/sql driver of -results-db-dsn (postgres, mysql)")
+	flag.StringVar(&resultsDBDSN, "results-db-dsn", "", "database every run is also stored in, tables created on first use, disabled when empty")
+	notifyConfig := notifyFlags(flag.CommandLine)
 	flag.Parse()
 
 	bankStatementPathArray := strings.Split(bankStatementPaths, ",")
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	var agingBucketArray []int
+	for _, bound := range strings.Split(agingBuckets, ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(bound))
+		if err != nil {
+			log.Panicf("invalid aging bucket %q: %v", bound, err)
+		}
+		agingBucketArray = append(agingBucketArray, days)
+	}
+
+	var duplicateKeyArray []recon.DuplicateKey
+	for _, key := range strings.Split(duplicateKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			duplicateKeyArray = append(duplicateKeyArray, recon.DuplicateKey(key))
+		}
+	}
+
+	fileAccounts := map[string]recon.BankAccount{}
+	for _, entry := range strings.Split(bankAccounts, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		path, account, ok := strings.Cut(entry, "=")
+		if !ok {
+			log.Panicf("invalid bank account %q, expected path=bank:account", entry)
+		}
+		bank, number, _ := strings.Cut(account, ":")
+		fileAccounts[strings.TrimSpace(path)] = recon.BankAccount{Bank: strings.TrimSpace(bank), Account: strings.TrimSpace(number)}
+	}
+
+	cutoffs := map[string]time.Duration{}
+	for _, entry := range strings.Split(bankCutoffs, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		bank, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			log.Panicf("invalid bank cutoff %q, expected bank=HH:MM", entry)
+		}
+		cutoff, err := recon.ParseCutoff(value)
+		if err != nil {
+			log.Panic(err)
+		}
+		cutoffs[strings.TrimSpace(bank)] = cutoff
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// the files configuring the run are recorded with it next to its inputs
+	var configInputs []recon.ConfigInput
+
+	var holidays []recon.Holiday
+	for _, path := range strings.Split(holidayPaths, ",") {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		loaded, report, err := recon.NewHolidayStorage(recon.CSVReaderFactory{}).GetHolidays(ctx, strings.TrimSpace(path))
+		if err != nil {
+			log.Panic(err)
+		}
+		holidays = append(holidays, loaded...)
+		configInputs = append(configInputs, recon.ConfigInput{Kind: recon.ConfigInputHolidays, LoadReport: report})
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Panic(err)
+	}
+	now, calendar := time.Now().In(location), recon.NewCalendar(holidays)
+	startDate, err := recon.ParseDate(startDateStr, now, calendar)
 	if err != nil {
 		log.Panic(err)
 	}
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := recon.ParseDate(endDateStr, now, calendar)
 	if err != nil {
 		log.Panic(err)
 	}
+	if dateRange != "" {
+		startDate, endDate, err = recon.ParseDateRange(dateRange, now, calendar)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
 
 	excelFactory := recon.ExcelFactory{}
 	csvReaderFactory := recon.CSVReaderFactory{}
+	fileFactory := recon.FileFactory{}
+
+	reportStorages := []recon.ReportStorageProvider{
+		recon.NewDashboardStorage(reconPath, "Dashboard", excelFactory),
+		recon.NewRunInfoStorage(reconPath, "Run Info", excelFactory),
+		recon.NewAgingStorage(reconPath, "Aging", excelFactory),
+		recon.NewManualStorage(reconPath, "Manual", excelFactory),
+		recon.NewDuplicatesStorage(reconPath, "Duplicates", excelFactory),
+		recon.NewReversalsStorage(reconPath, "Reversals", excelFactory),
+	}
+	for _, format := range strings.Split(reportFormats, ",") {
+		switch strings.TrimSpace(format) {
+		case "":
+		case "html":
+			reportStorages = append(reportStorages, recon.NewHTMLReportStorage(htmlReportPath, fileFactory))
+		case "json":
+			reportStorages = append(reportStorages, recon.NewJSONReportStorage(jsonReportPath, fileFactory))
+		default:
+			log.Panicf("unknown report format %q", format)
+		}
+	}
 
+	if resultsDBDSN != "" {
+		db, err := sql.Open(resultsDBDriver, resultsDBDSN)
+		if err != nil {
+			log.Panic(err)
+		}
+		defer db.Close()
+		resultStorage := recon.NewSQLResultStorage(db, resultsDBDriver)
+		err = resultStorage.Migrate(ctx)
+		if err != nil {
+			log.Panic(err)
+		}
+		reportStorages = append(reportStorages, resultStorage)
+	}
+
+	var transactionStorage recon.TransactionStorageProvider = recon.NewTransactionStorage(reconPath, "Transaction", excelFactory, csvReaderFactory)
+	if transactionDBDSN != "" {
+		if transactionQuery == "" {
+			log.Panic("-transaction-query is required with -transaction-db-dsn")
+		}
+		columns, err := parseTransactionColumns(transactionColumns)
+		if err != nil {
+			log.Panic(err)
+		}
+		db, err := sql.Open(transactionDBDriver, transactionDBDSN)
+		if err != nil {
+			log.Panic(err)
+		}
+		defer db.Close()
+		transactionStorage = recon.NewSQLTransactionStorage(db, transactionQuery, transactionStorage).WithColumns(columns)
+		transactionPath = recon.SQLTransactionSource(transactionDBDriver, transactionQuery)
+	}
+
+	bankStatementStorage := recon.NewBankStatementStorage(reconPath, excelFactory, csvReaderFactory).WithFileAccounts(fileAccounts).WithCutoffs(cutoffs)
 	reconExecutor := recon.NewReconExecutor(
-		recon.NewTransactionStorage(reconPath, "Transaction", excelFactory, csvReaderFactory),
-		recon.NewBankStatementStorage(reconPath, excelFactory, csvReaderFactory),
+		transactionStorage,
+		bankStatementStorage,
 		recon.NewSummaryStorage(reconPath, "Summary", excelFactory),
-	)
+		reportStorages...,
+	).WithOptions(recon.Options{
+		RunArguments:      os.Args[1:],
+		ReportingCurrency: strings.ToUpper(reportingCurrency),
+		LoadWorkers:       loadWorkers,
+		Match: recon.MatchConfig{
+			FXTolerance:    fxTolerance,
+			SettlementDays: settlementDays,
+		},
+		Aging: recon.AgingConfig{
+			Buckets:             agingBucketArray,
+			EscalateAfterDays:   escalateAfterDays,
+			EscalateAboveAmount: escalateAboveAmount,
+		},
+		Duplicates: recon.DuplicateConfig{
+			Keys:   duplicateKeyArray,
+			Policy: recon.DuplicatePolicy(duplicatePolicy),
+		},
+		Reversals: recon.ReversalConfig{Window: reversalWindow},
+	}).WithCutoffs(cutoffs)
+	if ledgerPath != "" {
+		reconExecutor = reconExecutor.WithLedger(recon.NewLedgerStorage(ledgerPath))
+	}
+	if overridesPath != "" {
+		overrides, report, err := recon.NewOverridesStorage(csvReaderFactory).GetOverrides(ctx, overridesPath)
+		if err != nil {
+			log.Panic(err)
+		}
+		reconExecutor = reconExecutor.WithOverrides(overrides)
+		configInputs = append(configInputs, recon.ConfigInput{Kind: recon.ConfigInputOverrides, LoadReport: report})
+	}
+	if balancesPath != "" {
+		balances, report, err := bankStatementStorage.GetDeclaredBalances(ctx, balancesPath)
+		if err != nil {
+			log.Panic(err)
+		}
+		reconExecutor = reconExecutor.WithDeclaredBalances(balances)
+		configInputs = append(configInputs, recon.ConfigInput{Kind: recon.ConfigInputDeclaredBalances, LoadReport: report})
+	}
+	if fxRatesPath != "" {
+		rates, report, err := recon.NewFXRateStorage(csvReaderFactory).GetRates(ctx, fxRatesPath)
+		if err != nil {
+			log.Panic(err)
+		}
+		reconExecutor = reconExecutor.WithFXRates(recon.NewFXRates(rates))
+		configInputs = append(configInputs, recon.ConfigInput{Kind: recon.ConfigInputFXRates, LoadReport: report})
+	}
+
+	if businessDays || holidayPaths != "" {
+		reconExecutor = reconExecutor.WithCalendar(calendar)
+	}
+	reconExecutor = reconExecutor.WithConfigInputs(configInputs...)
 
-	err = reconExecutor.Execute(transactionPath, bankStatementPathArray, startDate, endDate)
+	notify := notifyConfig()
+	if notifiers := notify.Notifiers(); len(notifiers) > 0 {
+		attachments := []string{reconPath}
+		for _, format := range strings.Split(reportFormats, ",") {
+			switch strings.TrimSpace(format) {
+			case "html":
+				attachments = append(attachments, htmlReportPath)
+			case "json":
+				attachments = append(attachments, jsonReportPath)
+			}
+		}
+		reconExecutor = reconExecutor.WithNotifiers(notify.NotifyRules, attachments, log.Default(), notifiers...)
+	}
+
+	err = reconExecutor.Execute(ctx, transactionPath, bankStatementPathArray, startDate, endDate)
 	if err != nil {
 		log.Panic(err)
 	}
 
 	log.Println("Recon completed successfully")
 }
+
+// parseTransactionColumns reads field=column entries naming the result columns
+// of the transaction query.
+func parseTransactionColumns(value string) (recon.SQLTransactionColumns, error) {
+	var columns recon.SQLTransactionColumns
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		field, column, ok := strings.Cut(entry, "=")
+		if !ok {
+			return columns, fmt.Errorf("invalid transaction column %q, expected field=column", entry)
+		}
+		column = strings.TrimSpace(column)
+		switch strings.TrimSpace(field) {
+		case "id":
+			columns.ID = column
+		case "amount":
+			columns.Amount = column
+		case "type":
+			columns.Type = column
+		case "time":
+			columns.Time = column
+		case "currency":
+			columns.Currency = column
+		default:
+			return columns, fmt.Errorf("unknown transaction field %q", field)
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"recon/recon"
+	"strings"
+)
+
+// notifyFlags defines the notification flags on flags. The returned function
+// reads them into a config once flags are parsed.
+func notifyFlags(flags *flag.FlagSet) func() recon.NotifyConfig {
+	var config recon.NotifyConfig
+	webhooks := flags.String("notify-webhooks", "", "URLs the outcome of a run is posted to as JSON, comma separated")
+	slackWebhooks := flags.String("notify-slack-webhooks", "", "Slack incoming webhook URLs the outcome of a run is posted to, comma separated")
+	flags.StringVar(&config.Email.Addr, "smtp-addr", "", "host:port of the mail server the outcome of a run is mailed through, with the reports attached")
+	flags.StringVar(&config.Email.Username, "smtp-username", "", "login of -smtp-addr, the password is read from RECON_SMTP_PASSWORD")
+	flags.StringVar(&config.Email.From, "smtp-from", "recon@localhost", "sender of the mails")
+	to := flags.String("smtp-to", "", "recipients of the mails, comma separated")
+	flags.BoolVar(&config.OnCompletion, "notify-on-completion", false, "notify about every completed run")
+	flags.BoolVar(&config.OnFailure, "notify-on-failure", false, "notify about failed runs")
+	flags.IntVar(&config.UnmatchedCountAbove, "notify-unmatched-count-above", 0, "notify about completed runs leaving more unmatched items, disabled when 0")
+	flags.Float64Var(&config.UnmatchedAmountAbove, "notify-unmatched-amount-above", 0, "notify about completed runs leaving a larger unmatched amount, disabled when 0")
+
+	return func() recon.NotifyConfig {
+		config.Webhooks = splitList(*webhooks)
+		config.SlackWebhooks = splitList(*slackWebhooks)
+		config.Email.To = splitList(*to)
+		config.Email.Password = os.Getenv("RECON_SMTP_PASSWORD")
+		return config
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) != "" {
+			items = append(items, strings.TrimSpace(item))
+		}
+	}
+	return items
+}
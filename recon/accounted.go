@@ -0,0 +1,110 @@
+package recon
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// relativeAmountEpsilon is the tolerance, relative to the larger amount, used
+// when comparing totals: the float error of a sum grows with its size, so a
+// fixed tolerance fails large totals such as IDR amounts.
+const relativeAmountEpsilon = 1e-9
+
+// totalsEqual reports whether two sums of amounts agree within amountEpsilon
+// or relativeAmountEpsilon, whichever is larger.
+func totalsEqual(a, b float64) bool {
+	return math.Abs(a-b) < math.Max(amountEpsilon, relativeAmountEpsilon*math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// itemTotal counts items and adds up their amounts.
+type itemTotal struct {
+	count  int
+	amount float64
+}
+
+// itemTotals are the totals of one side of a run per currency.
+type itemTotals map[string]itemTotal
+
+func (t itemTotals) add(currency string, amount float64) {
+	total := t[currency]
+	total.count++
+	total.amount += amount
+	t[currency] = total
+}
+
+// unaccounted compares the items that went into a run with the items its
+// result accounts for: matched, unmatched, excluded, netted out by a
+// reversal or left out as a duplicate. Amounts are compared in the currency
+// of the items, so the check does not depend on conversion. It describes each
+// side and currency that differs; a difference is a bug, not bad input.
+func unaccounted(in reconInput, result Result, converter currencyConverter) []string {
+	loadedTransactions, loadedStatements := itemTotals{}, itemTotals{}
+	for _, item := range in.carriedForward {
+		switch item.Kind {
+		case LedgerTransaction:
+			loadedTransactions.add(converter.currency(item.Transaction.Currency), item.Transaction.Amount)
+		case LedgerBankStatement:
+			loadedStatements.add(converter.currency(item.BankStatement.Currency), item.BankStatement.Amount)
+		}
+	}
+	for _, t := range in.transactions {
+		loadedTransactions.add(converter.currency(t.Currency), t.Amount)
+	}
+	for _, s := range in.statements {
+		loadedStatements.add(converter.currency(s.Currency), s.Amount)
+	}
+
+	accountedTransactions, accountedStatements := itemTotals{}, itemTotals{}
+	transactions, statements := result.Settled()
+	transactions = append(transactions, result.UnmatchedTransactions...)
+	for _, group := range result.UnmatchedBankStatements {
+		statements = append(statements, group.Statements...)
+	}
+	if result.Options.Duplicates.policy() == DuplicateKeepFirst {
+		for _, dup := range result.DuplicateTransactions {
+			transactions = append(transactions, dup.Transaction)
+		}
+		for _, dup := range result.DuplicateBankStatements {
+			statements = append(statements, dup.BankStatement)
+		}
+	}
+	for _, t := range transactions {
+		accountedTransactions.add(converter.currency(t.Currency), t.Amount)
+	}
+	for _, s := range statements {
+		accountedStatements.add(converter.currency(s.Currency), s.Amount)
+	}
+
+	differences := compareTotals("transactions", loadedTransactions, accountedTransactions)
+	return append(differences, compareTotals("bank statements", loadedStatements, accountedStatements)...)
+}
+
+func compareTotals(side string, loaded, accounted itemTotals) []string {
+	currencies := map[string]bool{}
+	for currency := range loaded {
+		currencies[currency] = true
+	}
+	for currency := range accounted {
+		currencies[currency] = true
+	}
+	names := make([]string, 0, len(currencies))
+	for currency := range currencies {
+		names = append(names, currency)
+	}
+	sort.Strings(names)
+
+	var differences []string
+	for _, currency := range names {
+		l, a := loaded[currency], accounted[currency]
+		if l.count == a.count && totalsEqual(l.amount, a.amount) {
+			continue
+		}
+		name := currency
+		if name == "" {
+			name = "unspecified currency"
+		}
+		differences = append(differences, fmt.Sprintf("%s in %s: %d loaded (%.2f), %d accounted for (%.2f)", side, name, l.count, l.amount, a.count, a.amount))
+	}
+	return differences
+}
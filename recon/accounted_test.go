@@ -0,0 +1,63 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUnaccounted(t *testing.T) {
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+	transactions := []Transaction{
+		{ID: "1", Amount: 1_250_000_000.10, Type: Debit, Time: day},
+		{ID: "1", Amount: 1_250_000_000.10, Type: Debit, Time: day},
+		{ID: "2", Amount: 50.0, Type: Debit, Time: day},
+		{ID: "3", Amount: 70.0, Type: Debit, Time: day},
+	}
+	statements := []BankStatement{
+		{Bank: "BCA", ID: "a", Amount: 1_250_000_000.10, Time: day},
+		{Bank: "BCA", ID: "b", Amount: 80.0, Time: day},
+		{Bank: "BCA", ID: "c", Amount: -80.0, Time: day.Add(time.Hour)},
+	}
+	options := Options{
+		Duplicates: DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateKeepFirst},
+		Reversals:  ReversalConfig{Window: 24 * time.Hour},
+	}
+	reconExecutor := NewReconExecutor(nil, nil, nil).
+		WithOptions(options).
+		WithOverrides([]Override{{Action: OverrideExclude, TransactionIDs: []string{"2"}}})
+
+	t.Run("accounts for matched, unmatched, excluded, reversed and duplicate items", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		result, err := reconExecutor.Reconcile(transactions, statements)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(result.Summary.Unaccounted).Should(BeEmpty())
+		g.Expect(result.Summary.Balanced()).Should(BeTrue())
+	})
+
+	t.Run("describes items the result leaves out", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		result, err := reconExecutor.Reconcile(transactions, statements)
+		g.Expect(err).Should(BeNil())
+		result.UnmatchedTransactions = nil
+
+		in := reconInput{transactions: transactions, statements: statements}
+		g.Expect(unaccounted(in, result, currencyConverter{})).Should(Equal([]string{
+			"transactions in unspecified currency: 4 loaded (2500000120.20), 3 accounted for (2500000050.20)",
+		}))
+	})
+}
+
+func TestTotalsEqual(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(totalsEqual(0.1+0.2, 0.3)).Should(BeTrue())
+	g.Expect(totalsEqual(100.0, 100.01)).Should(BeFalse())
+	// float drift in large IDR totals stays within the relative tolerance
+	g.Expect(totalsEqual(98_765_432_109_876.0, 98_765_432_109_876.03)).Should(BeTrue())
+	g.Expect(totalsEqual(98_765_432_109_876.0, 98_765_432_209_876.0)).Should(BeFalse())
+}
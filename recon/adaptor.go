@@ -1,7 +1,12 @@
 package recon
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
+	"hash"
+	"io"
 	"os"
 
 	"github.com/xuri/excelize/v2"
@@ -24,16 +29,75 @@ func (ExcelFactory) New(path string) (ExcelWriter, error) {
 type CSVReader struct {
 	*csv.Reader
 	*os.File
+	ctx  context.Context
+	hash hash.Hash
+}
+
+// ReadAll reads the remaining rows, checking for cancellation between rows.
+func (c *CSVReader) ReadAll() ([][]string, error) {
+	var records [][]string
+	for {
+		if err := c.ctx.Err(); err != nil {
+			return nil, err
+		}
+		record, err := c.Reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// Checksum is the hex encoded SHA-256 of the bytes read so far.
+func (c *CSVReader) Checksum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
 }
 
 type CSVReaderFactory struct{}
 
-func (CSVReaderFactory) NewReader(filename string) (Reader, error) {
+func (CSVReaderFactory) NewReader(ctx context.Context, filename string) (Reader, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	reader := csv.NewReader(file)
-	return &CSVReader{reader, file}, nil
+	h := sha256.New()
+	reader := csv.NewReader(io.TeeReader(file, h))
+	// rows with missing columns are rejected by the storages instead of failing the whole file
+	reader.FieldsPerRecord = -1
+	return &CSVReader{Reader: reader, File: file, ctx: ctx, hash: h}, nil
+}
+
+// Open opens filename, failing reads once ctx is done.
+func (CSVReaderFactory) Open(ctx context.Context, filename string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return contextReader{ReadCloser: file, ctx: ctx}, nil
+}
+
+// contextReader fails reads with the error of ctx once ctx is done.
+type contextReader struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.ReadCloser.Read(p)
+}
+
+type FileFactory struct{}
+
+func (FileFactory) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
 }
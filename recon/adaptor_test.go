@@ -0,0 +1,91 @@
+package recon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCSVReaderFactory_NewReader(t *testing.T) {
+	content := "id,amount\n1,100\n2\n"
+	filename := filepath.Join(t.TempDir(), "bca.csv")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("reads all rows and checksums the file", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		reader, err := CSVReaderFactory{}.NewReader(context.Background(), filename)
+		g.Expect(err).Should(BeNil())
+		defer reader.Close()
+
+		records, err := reader.ReadAll()
+		g.Expect(err).Should(BeNil())
+		g.Expect(records).Should(Equal([][]string{{"id", "amount"}, {"1", "100"}, {"2"}}))
+		sum := sha256.Sum256([]byte(content))
+		g.Expect(reader.Checksum()).Should(Equal(hex.EncodeToString(sum[:])))
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		reader, err := CSVReaderFactory{}.NewReader(ctx, filename)
+		g.Expect(err).Should(BeNil())
+		defer reader.Close()
+
+		cancel()
+		_, err = reader.ReadAll()
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		_, err := CSVReaderFactory{}.NewReader(context.Background(), filepath.Join(t.TempDir(), "missing.csv"))
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+func TestCSVReaderFactory_Open(t *testing.T) {
+	content := "- action: exclude\n"
+	filename := filepath.Join(t.TempDir(), "overrides.yaml")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("reads the file as it is", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		file, err := CSVReaderFactory{}.Open(context.Background(), filename)
+		g.Expect(err).Should(BeNil())
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		g.Expect(err).Should(BeNil())
+		g.Expect(string(data)).Should(Equal(content))
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		file, err := CSVReaderFactory{}.Open(ctx, filename)
+		g.Expect(err).Should(BeNil())
+		defer file.Close()
+
+		cancel()
+		_, err = io.ReadAll(file)
+		g.Expect(err).Should(MatchError(context.Canceled))
+
+		_, err = CSVReaderFactory{}.Open(ctx, filename)
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+}
@@ -0,0 +1,175 @@
+package recon
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultAgingBuckets are the upper bounds, in days, of the 0-1, 2-3, 4-7
+// and 8-30 buckets. Older items fall in a final >30 bucket.
+var DefaultAgingBuckets = []int{1, 3, 7, 30}
+
+// AgingConfig controls how unmatched items are aged.
+type AgingConfig struct {
+	// Buckets are ascending inclusive upper bounds in days. Empty means
+	// DefaultAgingBuckets.
+	Buckets []int `yaml:"buckets"`
+	// EscalateAfterDays flags items at least this old. Zero disables it.
+	EscalateAfterDays int `yaml:"escalate_after_days"`
+	// EscalateAboveAmount flags items whose absolute amount is at least
+	// this much, in the reporting currency when the run has one. Items in
+	// other currencies are converted at the rate of their date first. Zero
+	// disables it.
+	EscalateAboveAmount float64 `yaml:"escalate_above_amount"`
+}
+
+// Validate checks that the bucket bounds are non-negative and ascending.
+func (a AgingConfig) Validate() error {
+	previous := -1
+	for _, to := range a.Buckets {
+		if to <= previous {
+			return fmt.Errorf("aging buckets must be non-negative and ascending: %v", a.Buckets)
+		}
+		previous = to
+	}
+	return nil
+}
+
+func (a AgingConfig) buckets() []int {
+	if len(a.Buckets) == 0 {
+		return DefaultAgingBuckets
+	}
+	return a.Buckets
+}
+
+// BucketLabels names the buckets, e.g. "0-1", "2-3", ..., ">30".
+func (a AgingConfig) BucketLabels() []string {
+	var labels []string
+	from := 0
+	for _, to := range a.buckets() {
+		labels = append(labels, fmt.Sprintf("%d-%d", from, to))
+		from = to + 1
+	}
+	return append(labels, fmt.Sprintf(">%d", from-1))
+}
+
+func (a AgingConfig) bucket(ageDays int) int {
+	for i, to := range a.buckets() {
+		if ageDays <= to {
+			return i
+		}
+	}
+	return len(a.buckets())
+}
+
+func (a AgingConfig) escalate(ageDays int, amount float64) bool {
+	return (a.EscalateAfterDays > 0 && ageDays >= a.EscalateAfterDays) ||
+		(a.EscalateAboveAmount > 0 && math.Abs(amount) >= a.EscalateAboveAmount)
+}
+
+const agingSideTransactions = "transactions"
+
+// AgedItem is an unmatched transaction or bank statement with its age.
+type AgedItem struct {
+	// Side is "transactions" or the bank name.
+	Side      string
+	Direction TransactionType
+	ID        string
+	Amount    float64
+	// Currency is that of Amount, the reporting currency when the item has
+	// none.
+	Currency string
+	Time     time.Time
+	AgeDays  int
+	Bucket   int
+	Escalate bool
+}
+
+// AgingGroup totals the unmatched items of one side, direction and currency
+// per bucket, so amounts in different currencies are not added up.
+type AgingGroup struct {
+	Side      string
+	Direction TransactionType
+	Currency  string
+	Counts    []int
+	Amounts   []float64
+}
+
+type AgingReport struct {
+	Labels []string
+	Groups []AgingGroup
+	Items  []AgedItem
+}
+
+// Escalated returns the items flagged for escalation.
+func (a AgingReport) Escalated() []AgedItem {
+	var items []AgedItem
+	for _, item := range a.Items {
+		if item.Escalate {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Aging ages every unmatched item relative to EndDate and totals them per
+// side, direction and currency. Bank statements with a negative amount are
+// debits.
+func (r Result) Aging() AgingReport {
+	config := r.Options.Aging
+	report := AgingReport{Labels: config.BucketLabels()}
+
+	// the summary converted every unmatched item already, so converting
+	// them again does not fail
+	converter := currencyConverter{rates: r.FXRates, reporting: r.Summary.ReportingCurrency}
+	groupIndex := map[string]int{}
+	add := func(side, bank string, direction TransactionType, id string, amount float64, currency string, t time.Time) {
+		age := r.ageDays(t, bank)
+		reportingAmount, _ := converter.toReporting(amount, currency, t)
+		item := AgedItem{
+			Side:      side,
+			Direction: direction,
+			ID:        id,
+			Amount:    amount,
+			Currency:  cmp.Or(currency, r.Summary.ReportingCurrency),
+			Time:      t,
+			AgeDays:   age,
+			Bucket:    config.bucket(age),
+			Escalate:  config.escalate(age, reportingAmount),
+		}
+		report.Items = append(report.Items, item)
+
+		key := side + "/" + string(direction) + "/" + item.Currency
+		if _, ok := groupIndex[key]; !ok {
+			groupIndex[key] = len(report.Groups)
+			report.Groups = append(report.Groups, AgingGroup{
+				Side:      side,
+				Direction: direction,
+				Currency:  item.Currency,
+				Counts:    make([]int, len(report.Labels)),
+				Amounts:   make([]float64, len(report.Labels)),
+			})
+		}
+		group := &report.Groups[groupIndex[key]]
+		group.Counts[item.Bucket]++
+		group.Amounts[item.Bucket] += amount
+	}
+
+	for _, t := range r.UnmatchedTransactions {
+		add(agingSideTransactions, "", t.Type, t.ID, t.Amount, t.Currency, t.Time)
+	}
+	for _, group := range r.UnmatchedBankStatements {
+		for _, s := range group.Statements {
+			add(group.BankAccount().String(), group.Bank, s.Direction(), s.ID, s.Amount, s.Currency, s.Time)
+		}
+	}
+	return report
+}
+
+// ageDays counts the business days of bank from t to EndDate, in the time
+// zone of EndDate, never negative.
+func (r Result) ageDays(t time.Time, bank string) int {
+	return max(r.Calendar.BusinessDays(t.In(r.EndDate.Location()), r.EndDate, bank), 0)
+}
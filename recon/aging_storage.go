@@ -0,0 +1,84 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// AgingStorage writes the unmatched item counts and amounts per aging bucket,
+// followed by the items flagged for escalation.
+type AgingStorage struct {
+	destinationFileNamePath string
+	destinationSheetName    string
+	excelWriterFactory      ExcelWriterFactory
+}
+
+func NewAgingStorage(destinationFileNamePath string, destinationSheetName string, excelWriterFactory ExcelWriterFactory) AgingStorage {
+	return AgingStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		destinationSheetName:    destinationSheetName,
+		excelWriterFactory:      excelWriterFactory,
+	}
+}
+
+func (a AgingStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := a.excelWriterFactory.New(a.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	index, err := f.GetSheetIndex(a.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet index: %w", err)
+	}
+
+	// the sheet is recreated so the rows of an earlier run with more groups
+	// or escalated items are not left below the new ones
+	if index != -1 {
+		err = f.DeleteSheet(a.destinationSheetName)
+		if err != nil {
+			return fmt.Errorf("failed to delete sheet: %w", err)
+		}
+	}
+
+	_, err = f.NewSheet(a.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	aging := result.Aging()
+
+	header := []any{"Side", "Direction", "Currency"}
+	for _, label := range aging.Labels {
+		header = append(header, label+" Count", label+" Amount")
+	}
+	rows := [][]any{header}
+	for _, group := range aging.Groups {
+		row := []any{group.Side, string(group.Direction), group.Currency}
+		for i := range aging.Labels {
+			row = append(row, group.Counts[i], group.Amounts[i])
+		}
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, []any{}, []any{"Escalated Items"}, []any{"Side", "Direction", "ID", "Amount", "Currency", "Time", "Age (days)", "Bucket"})
+	for _, item := range aging.Escalated() {
+		rows = append(rows, []any{item.Side, string(item.Direction), item.ID, item.Amount, item.Currency, item.Time.Format(time.RFC3339), item.AgeDays, aging.Labels[item.Bucket]})
+	}
+
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			f.SetCellValue(a.destinationSheetName, cell, v)
+		}
+	}
+
+	err = f.SaveAs(a.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("save as error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAgingStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.xlsx"
+	destinationSheetName := "Aging"
+	endDate, _ := time.Parse(time.DateOnly, "2025-08-31")
+
+	result := Result{
+		EndDate: endDate,
+		Options: Options{Aging: AgingConfig{Buckets: []int{3}, EscalateAfterDays: 4}},
+		Summary: Summary{ReportingCurrency: "IDR"},
+		UnmatchedTransactions: []Transaction{
+			{ID: "t1", Amount: 10, Type: Debit, Time: endDate.AddDate(0, 0, -5)},
+			// amounts in another currency are totaled apart
+			{ID: "t2", Amount: 5, Currency: "USD", Type: Debit, Time: endDate.AddDate(0, 0, -1)},
+			{ID: "t3", Amount: 20000, Currency: "IDR", Type: Debit, Time: endDate.AddDate(0, 0, -1)},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		agingStorage := NewAgingStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		cells := map[string]any{
+			"A1": "Side", "B1": "Direction", "C1": "Currency", "D1": "0-3 Count", "E1": "0-3 Amount", "F1": ">3 Count", "G1": ">3 Amount",
+			"A2": "transactions", "B2": "debit", "C2": "IDR", "D2": 1, "E2": 20000.0, "F2": 1, "G2": 10.0,
+			"A3": "transactions", "B3": "debit", "C3": "USD", "D3": 1, "E3": 5.0, "F3": 0, "G3": 0.0,
+			"A5": "Escalated Items",
+			"A6": "Side", "B6": "Direction", "C6": "ID", "D6": "Amount", "E6": "Currency", "F6": "Time", "G6": "Age (days)", "H6": "Bucket",
+			"A7": "transactions", "B7": "debit", "C7": "t1", "D7": 10.0, "E7": "IDR", "F7": "2025-08-26T00:00:00Z", "G7": 5, "H7": ">3",
+		}
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(3, nil)
+		for cell, v := range cells {
+			mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := agingStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("replaces the rows of an earlier run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		path := filepath.Join(t.TempDir(), "recon.xlsx")
+		agingStorage := NewAgingStorage(path, destinationSheetName, ExcelFactory{})
+
+		longer := result
+		longer.UnmatchedTransactions = append(slices.Clone(result.UnmatchedTransactions), Transaction{ID: "t4", Amount: 30, Currency: "EUR", Type: Debit, Time: endDate})
+		g.Expect(agingStorage.StoreReport(context.Background(), longer)).Should(Succeed())
+		g.Expect(agingStorage.StoreReport(context.Background(), result)).Should(Succeed())
+
+		f, err := excelize.OpenFile(path)
+		g.Expect(err).Should(BeNil())
+		defer f.Close()
+		rows, err := f.GetRows(destinationSheetName)
+		g.Expect(err).Should(BeNil())
+		g.Expect(rows).Should(HaveLen(7))
+	})
+
+	t.Run("delete sheet error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		agingStorage := NewAgingStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(errors.New("delete sheet error"))
+
+		err := agingStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		agingStorage := NewAgingStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, errors.New("open file error"))
+
+		err := agingStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("save as error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		agingStorage := NewAgingStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(nil)
+		mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(1, nil)
+		mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
+
+		err := agingStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
@@ -0,0 +1,124 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAgingConfig_BucketLabels(t *testing.T) {
+	t.Run("default buckets", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(AgingConfig{}.BucketLabels()).Should(Equal([]string{"0-1", "2-3", "4-7", "8-30", ">30"}))
+	})
+
+	t.Run("custom buckets", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(AgingConfig{Buckets: []int{0, 14}}.BucketLabels()).Should(Equal([]string{"0-0", "1-14", ">14"}))
+	})
+}
+
+func TestResult_Aging(t *testing.T) {
+	endDate, _ := time.Parse(time.DateOnly, "2025-08-31")
+	daysAgo := func(days int) time.Time { return endDate.AddDate(0, 0, -days).Add(5 * time.Hour) }
+
+	result := Result{
+		EndDate: endDate,
+		Options: Options{Aging: AgingConfig{EscalateAfterDays: 8, EscalateAboveAmount: 1000}},
+		UnmatchedTransactions: []Transaction{
+			{ID: "t1", Amount: 10, Type: Debit, Time: daysAgo(0)},
+			{ID: "t2", Amount: 20, Type: Debit, Time: daysAgo(1)},
+			{ID: "t3", Amount: 30, Type: Debit, Time: daysAgo(2)},
+			{ID: "t4", Amount: 5000, Type: Credit, Time: daysAgo(4)},
+		},
+		UnmatchedBankStatements: []BankStatementDiscrepancy{
+			{Bank: "bca", Statements: []BankStatement{
+				{ID: "s1", Amount: 40, Time: daysAgo(8)},
+				{ID: "s2", Amount: -50, Time: daysAgo(31)},
+				{ID: "s3", Amount: 60, Time: endDate.AddDate(0, 0, 1)},
+			}},
+		},
+	}
+
+	t.Run("totals items per side, direction and bucket", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		aging := result.Aging()
+
+		g.Expect(aging.Labels).Should(Equal([]string{"0-1", "2-3", "4-7", "8-30", ">30"}))
+		g.Expect(aging.Groups).Should(Equal([]AgingGroup{
+			{Side: "transactions", Direction: Debit, Counts: []int{2, 1, 0, 0, 0}, Amounts: []float64{30, 30, 0, 0, 0}},
+			{Side: "transactions", Direction: Credit, Counts: []int{0, 0, 1, 0, 0}, Amounts: []float64{0, 0, 5000, 0, 0}},
+			{Side: "bca", Direction: Credit, Counts: []int{1, 0, 0, 1, 0}, Amounts: []float64{60, 0, 0, 40, 0}},
+			{Side: "bca", Direction: Debit, Counts: []int{0, 0, 0, 0, 1}, Amounts: []float64{0, 0, 0, 0, -50}},
+		}))
+	})
+
+	t.Run("flags old and large items for escalation", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		var escalated []string
+		for _, item := range result.Aging().Escalated() {
+			escalated = append(escalated, item.ID)
+		}
+
+		g.Expect(escalated).Should(Equal([]string{"t4", "s1", "s2"}))
+	})
+
+	t.Run("compares amounts with the escalation threshold in the reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		result := Result{
+			EndDate: endDate,
+			Options: Options{Aging: AgingConfig{EscalateAboveAmount: 1000}},
+			Summary: Summary{ReportingCurrency: "JPY"},
+			FXRates: NewFXRates([]FXRate{{Date: daysAgo(10), Base: "USD", Quote: "JPY", Rate: 150}}),
+			UnmatchedTransactions: []Transaction{
+				{ID: "usd", Amount: 10, Currency: "USD", Type: Debit, Time: daysAgo(0)},
+				{ID: "small-usd", Amount: 5, Currency: "USD", Type: Debit, Time: daysAgo(0)},
+				{ID: "jpy", Amount: 1000, Currency: "JPY", Type: Debit, Time: daysAgo(0)},
+				{ID: "reporting", Amount: 999, Type: Debit, Time: daysAgo(0)},
+			},
+		}
+
+		var escalated []string
+		for _, item := range result.Aging().Escalated() {
+			escalated = append(escalated, item.ID)
+		}
+
+		// 10 USD are 1500 JPY, 5 USD only 750 JPY
+		g.Expect(escalated).Should(Equal([]string{"usd", "jpy"}))
+		g.Expect(result.Aging().Items[0].Amount).Should(Equal(10.0))
+	})
+
+	t.Run("items after the end date are zero days old", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		items := result.Aging().Items
+
+		g.Expect(items[len(items)-1].AgeDays).Should(Equal(0))
+	})
+
+	t.Run("counts business days of the bank with a calendar", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		// 2025-08-31 is a Sunday, 2025-08-29 a Friday
+		calendar := NewCalendar([]Holiday{{Date: endDate.AddDate(0, 0, -5), Name: "Bank holiday", Bank: "bca"}})
+		result := Result{
+			EndDate:               endDate,
+			Calendar:              calendar,
+			UnmatchedTransactions: []Transaction{{ID: "t1", Type: Debit, Time: daysAgo(6)}},
+			UnmatchedBankStatements: []BankStatementDiscrepancy{
+				{Bank: "bca", Statements: []BankStatement{{ID: "s1", Time: daysAgo(6)}}},
+			},
+		}
+
+		items := result.Aging().Items
+
+		g.Expect(items[0].AgeDays).Should(Equal(4))
+		g.Expect(items[1].AgeDays).Should(Equal(3))
+	})
+}
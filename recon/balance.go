@@ -0,0 +1,121 @@
+package recon
+
+// RunningBalance is what the balance column of a bank statement file tells
+// about an account over the period.
+type RunningBalance struct {
+	Bank    string
+	Account string
+	// Opening is the balance before the first line in the period.
+	Opening float64
+	// Closing is the balance after the last line in the period.
+	Closing float64
+	// Gaps are lines whose balance does not follow from the previous line,
+	// usually because lines are missing in between.
+	Gaps []BalanceGap
+}
+
+// BalanceGap is a break in the running balance at a statement line.
+type BalanceGap struct {
+	Line int
+	ID   string
+	// Expected is the previous balance plus the line amount.
+	Expected float64
+	// Actual is the balance stated on the line.
+	Actual float64
+}
+
+// Missing is the amount of the lines that would close the gap.
+func (g BalanceGap) Missing() float64 {
+	return g.Actual - g.Expected
+}
+
+// DeclaredBalance is an opening and closing balance stated by the bank for
+// the period, e.g. from the statement cover page.
+type DeclaredBalance struct {
+	Bank    string
+	Account string
+	Opening float64
+	Closing float64
+}
+
+// BalanceCheck verifies opening + sum of lines = closing for one bank account.
+type BalanceCheck struct {
+	Bank    string
+	Account string
+	Opening float64
+	// Lines is the sum of the statement lines in the period.
+	Lines   float64
+	Closing float64
+	// Declared tells whether opening and closing were declared rather than
+	// taken from the running balance.
+	Declared bool
+	Gaps     []BalanceGap
+}
+
+func (c BalanceCheck) BankAccount() BankAccount {
+	return BankAccount{Bank: c.Bank, Account: c.Account}
+}
+
+// Difference is the part of the closing balance the statement lines do not explain.
+func (c BalanceCheck) Difference() float64 {
+	return c.Closing - c.Opening - c.Lines
+}
+
+// Broken reports whether the lines do not add up to the closing balance or
+// the running balance has gaps.
+func (c BalanceCheck) Broken() bool {
+	return !amountEqual(c.Difference(), 0) || len(c.Gaps) > 0
+}
+
+// checkBalances builds a balance check for every account that has a running
+// balance or a declared balance, in order of appearance. A declared balance
+// of an account without lines in the period is checked against no lines.
+func checkBalances(statements []BankStatement, running []RunningBalance, declared []DeclaredBalance) []BalanceCheck {
+	var accounts []BankAccount
+	seen := map[BankAccount]bool{}
+	see := func(account BankAccount) {
+		if !seen[account] {
+			seen[account] = true
+			accounts = append(accounts, account)
+		}
+	}
+
+	lines := map[BankAccount]float64{}
+	for _, s := range statements {
+		see(s.BankAccount())
+		lines[s.BankAccount()] += s.Amount
+	}
+	runningByAccount := map[BankAccount]RunningBalance{}
+	for _, r := range running {
+		account := BankAccount{Bank: r.Bank, Account: r.Account}
+		see(account)
+		runningByAccount[account] = r
+	}
+	declaredByAccount := map[BankAccount]DeclaredBalance{}
+	for _, d := range declared {
+		account := BankAccount{Bank: d.Bank, Account: d.Account}
+		see(account)
+		declaredByAccount[account] = d
+	}
+
+	var checks []BalanceCheck
+	for _, account := range accounts {
+		check := BalanceCheck{Bank: account.Bank, Account: account.Account, Lines: lines[account]}
+
+		r, hasRunning := runningByAccount[account]
+		d, isDeclared := declaredByAccount[account]
+		switch {
+		case isDeclared:
+			check.Opening, check.Closing, check.Declared = d.Opening, d.Closing, true
+		case hasRunning:
+			check.Opening, check.Closing = r.Opening, r.Closing
+		default:
+			continue
+		}
+		if hasRunning {
+			check.Gaps = r.Gaps
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,52 @@
+package recon
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckBalances(t *testing.T) {
+	t.Run("should check running and declared balances per account", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		statements := []BankStatement{
+			{Bank: "bca", Account: "111", Amount: 100},
+			{Bank: "bca", Account: "111", Amount: -30},
+			{Bank: "bca", Account: "222", Amount: 50},
+			{Bank: "mandiri", Amount: 20},
+			{Bank: "bni", Amount: 5},
+		}
+		running := []RunningBalance{
+			{Bank: "bca", Account: "111", Opening: 1000, Closing: 1070},
+			{Bank: "bca", Account: "222", Opening: 10, Closing: 60, Gaps: []BalanceGap{{Line: 2}}},
+		}
+		declared := []DeclaredBalance{
+			{Bank: "bca", Account: "222", Opening: 0, Closing: 60},
+			{Bank: "mandiri", Opening: 100, Closing: 125},
+			{Bank: "bri", Opening: 40, Closing: 40},
+		}
+
+		checks := checkBalances(statements, running, declared)
+
+		g.Expect(checks).Should(Equal([]BalanceCheck{
+			{Bank: "bca", Account: "111", Opening: 1000, Lines: 70, Closing: 1070},
+			{Bank: "bca", Account: "222", Opening: 0, Lines: 50, Closing: 60, Declared: true, Gaps: []BalanceGap{{Line: 2}}},
+			{Bank: "mandiri", Opening: 100, Lines: 20, Closing: 125, Declared: true},
+			{Bank: "bri", Opening: 40, Closing: 40, Declared: true},
+		}))
+		g.Expect(checks[0].Broken()).Should(BeFalse())
+		g.Expect(checks[1].Difference()).Should(Equal(10.0))
+		g.Expect(checks[1].Broken()).Should(BeTrue())
+		g.Expect(checks[2].Broken()).Should(BeTrue())
+		g.Expect(checks[3].Broken()).Should(BeFalse())
+	})
+
+	t.Run("should report the missing amount of a gap", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		gap := BalanceGap{Expected: 1070, Actual: 1050}
+
+		g.Expect(gap.Missing()).Should(Equal(-20.0))
+	})
+}
@@ -1,6 +1,7 @@
 package recon
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -11,10 +12,31 @@ import (
 )
 
 type BankStatement struct {
-	Bank   string
-	ID     string
-	Amount float64
-	Time   time.Time
+	Bank string
+	// Account is the account number within the bank, empty when the bank
+	// has a single account.
+	Account string `json:",omitempty"`
+	ID      string
+	Amount  float64
+	// Currency is the ISO 4217 code of Amount, empty when unspecified.
+	Currency string `json:",omitempty"`
+	Time     time.Time
+	// Reference is the reference the bank gave the line, empty when the
+	// statement file has none.
+	Reference string `json:",omitempty"`
+}
+
+// BankAccount identifies the account a statement line belongs to.
+func (b BankStatement) BankAccount() BankAccount {
+	return BankAccount{Bank: b.Bank, Account: b.Account}
+}
+
+// Direction is Credit for money coming in and Debit for a negative amount.
+func (b BankStatement) Direction() TransactionType {
+	if b.Amount < 0 {
+		return Debit
+	}
+	return Credit
 }
 
 type BankStatementGroup struct {
@@ -29,11 +51,28 @@ func (b *BankStatementGroup) Shift() {
 	b.BankStatements = b.BankStatements[1:]
 }
 
+// BankAccount is a bank together with an account number at that bank.
+type BankAccount struct {
+	Bank    string
+	Account string
+}
+
+// String names the account, e.g. "bca" or "bca 1234567". It is used as the
+// sheet name of the account.
+func (a BankAccount) String() string {
+	if a.Account == "" {
+		return a.Bank
+	}
+	return a.Bank + " " + a.Account
+}
+
 type BankStatementStorage struct {
 	destinationFileNamePath string
 
 	excelWriterFactory ExcelWriterFactory
 	readerFactory      ReaderFactory
+	fileAccounts       map[string]BankAccount
+	cutoffs            map[string]time.Duration
 }
 
 func NewBankStatementStorage(destinationFileNamePath string, excelWriterFactory ExcelWriterFactory, readerFactory ReaderFactory) BankStatementStorage {
@@ -44,57 +83,207 @@ func NewBankStatementStorage(destinationFileNamePath string, excelWriterFactory
 	}
 }
 
-func (b BankStatementStorage) GetBankStatements(filename string, startDate time.Time, endDate time.Time) ([]BankStatement, error) {
-	reader, err := b.readerFactory.NewReader(filename)
+// WithFileAccounts returns a copy of the storage that takes the bank and
+// account of the statement files listed in accounts from there, instead of
+// from the file name and the account column.
+func (b BankStatementStorage) WithFileAccounts(accounts map[string]BankAccount) BankStatementStorage {
+	b.fileAccounts = accounts
+	return b
+}
+
+// WithCutoffs returns a copy of the storage that ends the business day of the
+// banks in cutoffs at their cutoff time of day instead of at midnight.
+func (b BankStatementStorage) WithCutoffs(cutoffs map[string]time.Duration) BankStatementStorage {
+	b.cutoffs = cutoffs
+	return b
+}
+
+// fileAccount is the bank and default account of a statement file.
+func (b BankStatementStorage) fileAccount(filename string) (BankAccount, bool) {
+	if account, ok := b.fileAccounts[filename]; ok {
+		return account, true
+	}
+	return BankAccount{Bank: bankNameFromPath(filename)}, false
+}
+
+func (b BankStatementStorage) GetBankStatements(ctx context.Context, filename string, startDate time.Time, endDate time.Time) ([]BankStatement, LoadReport, error) {
+	report := LoadReport{Path: filename}
+
+	reader, err := b.readerFactory.NewReader(ctx, filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, report, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer reader.Close()
 
 	records, err := reader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, report, fmt.Errorf("failed to read file: %w", err)
 	}
+	report.SHA256 = reader.Checksum()
 
 	if len(records) < 2 {
-		return nil, fmt.Errorf("no data rows found in %s", filename)
+		return nil, report, fmt.Errorf("no data rows found in %s", filename)
+	}
+	report.RowsRead = len(records) - 1
+
+	defaultAccount, profiled := b.fileAccount(filename)
+	period := NewPeriod(startDate, endDate, b.cutoffs[defaultAccount.Bank])
+
+	// an optional balance column holds the running balance after each line,
+	// an optional account column the account number of the line
+	balanceColumn := columnIndex(records[0], "balance")
+	accountColumn := -1
+	if !profiled {
+		accountColumn = columnIndex(records[0], "account")
 	}
+	currencyColumn := columnIndex(records[0], "currency")
+	referenceColumn := columnIndex(records[0], "reference")
+	minColumns := max(3, balanceColumn+1, accountColumn+1, currencyColumn+1, referenceColumn+1)
 
-	bankName := filepath.Base(filename) // extract filename only, e.g. "bca.csv"
-	bankName = strings.TrimSuffix(bankName, filepath.Ext(bankName))
+	// running balances are followed per account, in order of appearance
+	var runningAccounts []BankAccount
+	running := map[BankAccount]*RunningBalance{}
+	previousBalances := map[BankAccount]float64{}
 
 	var statements []BankStatement
-	for _, row := range records[1:] { // skip header
-		if len(row) < 3 {
+	for i, row := range records[1:] { // skip header
+		if err := ctx.Err(); err != nil {
+			return nil, report, err
+		}
+		if len(row) < minColumns {
+			report.reject(i+2, row, "missing columns")
 			continue
 		}
 
+		account := defaultAccount
+		if accountColumn != -1 {
+			account.Account = strings.TrimSpace(row[accountColumn])
+		}
+
 		amount, err := strconv.ParseFloat(row[1], 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid amount in row: %v", row)
+			return nil, report, fmt.Errorf("invalid amount in row: %v", row)
 		}
 
 		t, err := time.Parse(time.RFC3339, row[2])
 		if err != nil {
-			return nil, fmt.Errorf("invalid time format in row: %v", row)
+			return nil, report, fmt.Errorf("invalid time format in row: %v", row)
+		}
+
+		inPeriod := period.Contains(t)
+
+		if balanceColumn != -1 {
+			balance, err := strconv.ParseFloat(row[balanceColumn], 64)
+			if err != nil {
+				return nil, report, fmt.Errorf("invalid balance in row: %v", row)
+			}
+			previousBalance, hasPreviousBalance := previousBalances[account]
+			if inPeriod {
+				r, ok := running[account]
+				if !ok {
+					r = &RunningBalance{Bank: account.Bank, Account: account.Account, Opening: balance - amount}
+					running[account] = r
+					runningAccounts = append(runningAccounts, account)
+				}
+				if hasPreviousBalance && !amountEqual(previousBalance+amount, balance) {
+					r.Gaps = append(r.Gaps, BalanceGap{Line: i + 2, ID: row[0], Expected: previousBalance + amount, Actual: balance})
+				}
+				r.Closing = balance
+			}
+			previousBalances[account] = balance
 		}
 
-		if t.Before(startDate) || t.After(endDate.Add(24*time.Hour)) {
+		if !inPeriod {
+			report.RowsFiltered++
 			continue
 		}
 
-		statements = append(statements, BankStatement{
-			Bank:   bankName,
-			ID:     row[0],
-			Amount: amount,
-			Time:   t,
-		})
+		statement := BankStatement{
+			Bank:    account.Bank,
+			Account: account.Account,
+			ID:      row[0],
+			Amount:  amount,
+			Time:    t,
+		}
+		if currencyColumn != -1 {
+			statement.Currency = strings.ToUpper(strings.TrimSpace(row[currencyColumn]))
+		}
+		if referenceColumn != -1 {
+			statement.Reference = strings.TrimSpace(row[referenceColumn])
+		}
+		statements = append(statements, statement)
 	}
 
-	return statements, nil
+	for _, account := range runningAccounts {
+		report.Balances = append(report.Balances, *running[account])
+	}
+	return statements, report, nil
+}
+
+// GetDeclaredBalances reads opening and closing balances declared per bank
+// from a file with the columns bank, opening and closing, and optionally
+// account for banks with several accounts. The LoadReport has the checksum
+// of the file.
+func (b BankStatementStorage) GetDeclaredBalances(ctx context.Context, filename string) ([]DeclaredBalance, LoadReport, error) {
+	report := LoadReport{Path: filename}
+
+	reader, err := b.readerFactory.NewReader(ctx, filename)
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer reader.Close()
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to read file: %w", err)
+	}
+	report.SHA256 = reader.Checksum()
+	report.RowsRead = max(len(records)-1, 0)
+
+	var balances []DeclaredBalance
+	for i, row := range records {
+		if i == 0 { // skip header
+			continue
+		}
+		if len(row) < 3 {
+			return nil, report, fmt.Errorf("missing columns in row: %v", row)
+		}
+
+		opening, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid opening balance in row: %v", row)
+		}
+		closing, err := strconv.ParseFloat(row[2], 64)
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid closing balance in row: %v", row)
+		}
+
+		balance := DeclaredBalance{Bank: strings.TrimSpace(row[0]), Opening: opening, Closing: closing}
+		if len(row) > 3 {
+			balance.Account = strings.TrimSpace(row[3])
+		}
+		balances = append(balances, balance)
+	}
+	return balances, report, nil
+}
+
+// columnIndex finds a column by its header name, -1 when there is none.
+func columnIndex(header []string, name string) int {
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// bankNameFromPath names a bank after its statement file, e.g. "bca" for "data/bca.csv".
+func bankNameFromPath(filename string) string {
+	bankName := filepath.Base(filename)
+	return strings.TrimSuffix(bankName, filepath.Ext(bankName))
 }
 
-func (b BankStatementStorage) StoreBankStatements(statements []BankStatement, bankName string) error {
+func (b BankStatementStorage) StoreBankStatements(ctx context.Context, statements []BankStatement, bankName string) error {
 	f, err := b.excelWriterFactory.New(b.destinationFileNamePath) // open existing file
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -113,7 +302,7 @@ func (b BankStatementStorage) StoreBankStatements(statements []BankStatement, ba
 	}
 
 	// Write header row
-	headers := []string{"Bank", "ID", "Amount", "Time"}
+	headers := []string{"Bank", "Account", "ID", "Amount", "Time", "Currency", "Reference"}
 	for i, h := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1) // row 1
 		f.SetCellValue(bankName, cell, h)
@@ -123,9 +312,12 @@ func (b BankStatementStorage) StoreBankStatements(statements []BankStatement, ba
 	for row, s := range statements {
 		values := []interface{}{
 			s.Bank,
+			s.Account,
 			s.ID,
 			s.Amount,
 			s.Time.Format(time.RFC3339), // store as formatted string
+			s.Currency,
+			s.Reference,
 		}
 		for col, v := range values {
 			cell, _ := excelize.CoordinatesToCellName(col+1, row+2) // data starts at row 2
@@ -1,6 +1,7 @@
 package recon
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -31,11 +32,12 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 			{"2", "200.0", endDate.Format(time.RFC3339)},
 		}
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(mockReader, nil)
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
 		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
 		mockReader.EXPECT().Close().Return(nil)
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).Should(BeNil())
 		g.Expect(statements).Should(HaveLen(2))
@@ -55,9 +57,9 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 
 		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(nil, fmt.Errorf("new reader error"))
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(nil, fmt.Errorf("new reader error"))
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(statements).Should(BeNil())
@@ -74,11 +76,11 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 
 		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(mockReader, nil)
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
 		mockReader.EXPECT().ReadAll().Return(nil, fmt.Errorf("read all error"))
 		mockReader.EXPECT().Close().Return(nil)
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(statements).Should(BeNil())
@@ -99,11 +101,12 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 			{"ID", "Amount", "Time"},
 		}
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(mockReader, nil)
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
 		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
 		mockReader.EXPECT().Close().Return(nil)
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(statements).Should(BeNil())
@@ -125,11 +128,12 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 			{"1", "invalid", startDate.Format(time.RFC3339)},
 		}
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(mockReader, nil)
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
 		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
 		mockReader.EXPECT().Close().Return(nil)
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(statements).Should(BeNil())
@@ -151,11 +155,12 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 			{"1", "100.0", "invalid"},
 		}
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(mockReader, nil)
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
 		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
 		mockReader.EXPECT().Close().Return(nil)
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(statements).Should(BeNil())
@@ -180,15 +185,321 @@ func TestBankStatementStorage_GetBankStatements(t *testing.T) {
 			{"2", "200.0", endDate.Format(time.RFC3339)},
 		}
 
-		mockReaderFactory.EXPECT().NewReader(filename).Return(mockReader, nil)
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
 		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
 		mockReader.EXPECT().Close().Return(nil)
 
-		statements, err := bankStatementStorage.GetBankStatements(filename, startDate, endDate)
+		statements, report, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).Should(BeNil())
 		g.Expect(statements).Should(HaveLen(1))
 		g.Expect(statements[0].ID).Should(Equal("2"))
+		g.Expect(report.RowsRead).Should(Equal(2))
+		g.Expect(report.RowsFiltered).Should(Equal(1))
+	})
+
+	t.Run("should end the business day at the bank cutoff", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory).
+			WithCutoffs(map[string]time.Duration{"test": 22 * time.Hour, "other": 20 * time.Hour})
+
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		start := time.Date(2025, 8, 28, 0, 0, 0, 0, jakarta)
+		end := time.Date(2025, 8, 29, 0, 0, 0, 0, jakarta)
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time"},
+			{"1", "100.0", "2025-08-27T21:59:59+07:00"},
+			{"2", "100.0", "2025-08-27T22:00:00+07:00"},
+			{"3", "100.0", "2025-08-29T21:59:59+07:00"},
+			{"4", "100.0", "2025-08-29T22:00:00+07:00"},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		statements, report, err := bankStatementStorage.GetBankStatements(context.Background(), filename, start, end)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(statements).Should(HaveLen(2))
+		g.Expect(statements[0].ID).Should(Equal("2"))
+		g.Expect(statements[1].ID).Should(Equal("3"))
+		g.Expect(report.RowsFiltered).Should(Equal(2))
+	})
+
+	t.Run("should reject rows with missing columns", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time"},
+			{"1", "100.0", startDate.Format(time.RFC3339)},
+			{"2"},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		statements, report, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(statements).Should(HaveLen(1))
+		g.Expect(report.RejectedRows).Should(Equal([]RejectedRow{
+			{Path: filename, Line: 3, Row: []string{"2"}, Reason: "missing columns"},
+		}))
+	})
+
+	t.Run("should read running balances and detect gaps", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time", "Balance"},
+			{"0", "50.0", startDate.Add(-time.Hour).Format(time.RFC3339), "1000.0"},
+			{"1", "100.0", startDate.Format(time.RFC3339), "1100.0"},
+			{"2", "-30.0", startDate.Add(time.Hour).Format(time.RFC3339), "1050.0"},
+			{"3", "200.0", endDate.Format(time.RFC3339), "1250.0"},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		statements, report, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(statements).Should(HaveLen(3))
+		g.Expect(report.Balances).Should(Equal([]RunningBalance{{
+			Bank:    "test",
+			Opening: 1000.0,
+			Closing: 1250.0,
+			Gaps:    []BalanceGap{{Line: 4, ID: "2", Expected: 1070.0, Actual: 1050.0}},
+		}}))
+	})
+
+	t.Run("should read accounts from the account column", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time", "Account", "Balance"},
+			{"1", "100.0", startDate.Format(time.RFC3339), "111", "1100.0"},
+			{"2", "50.0", startDate.Format(time.RFC3339), "222", "550.0"},
+			{"3", "-20.0", endDate.Format(time.RFC3339), "111", "1080.0"},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		statements, report, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(statements).Should(Equal([]BankStatement{
+			{Bank: "test", Account: "111", ID: "1", Amount: 100.0, Time: startDate},
+			{Bank: "test", Account: "222", ID: "2", Amount: 50.0, Time: startDate},
+			{Bank: "test", Account: "111", ID: "3", Amount: -20.0, Time: endDate},
+		}))
+		g.Expect(report.Balances).Should(Equal([]RunningBalance{
+			{Bank: "test", Account: "111", Opening: 1000.0, Closing: 1080.0},
+			{Bank: "test", Account: "222", Opening: 500.0, Closing: 550.0},
+		}))
+	})
+
+	t.Run("should read currencies and references from their columns", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time", "Currency", "Reference"},
+			{"1", "10.0", startDate.Format(time.RFC3339), "usd", " REF-1 "},
+			{"2", "50.0", startDate.Format(time.RFC3339), "IDR", ""},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(statements).Should(Equal([]BankStatement{
+			{Bank: "test", ID: "1", Amount: 10.0, Currency: "USD", Time: startDate, Reference: "REF-1"},
+			{Bank: "test", ID: "2", Amount: 50.0, Currency: "IDR", Time: startDate},
+		}))
+	})
+
+	t.Run("should take bank and account from the file accounts", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory).
+			WithFileAccounts(map[string]BankAccount{filename: {Bank: "bca", Account: "333"}})
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time", "Account"},
+			{"1", "100.0", startDate.Format(time.RFC3339), "ignored"},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		statements, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(statements).Should(Equal([]BankStatement{
+			{Bank: "bca", Account: "333", ID: "1", Amount: 100.0, Time: startDate},
+		}))
+	})
+
+	t.Run("should return error when invalid balance in row", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockRecords := [][]string{
+			{"ID", "Amount", "Time", "Balance"},
+			{"1", "100.0", startDate.Format(time.RFC3339), "n/a"},
+		}
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		_, _, err := bankStatementStorage.GetBankStatements(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+func TestBankStatementStorage_GetDeclaredBalances(t *testing.T) {
+	filename := "balances.csv"
+
+	t.Run("should get declared balances successfully", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"bank", "opening", "closing"},
+			{"bca", "1000", "1250.5"},
+			{"bri", "10", "20", "444"},
+		}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		balances, report, err := bankStatementStorage.GetDeclaredBalances(context.Background(), filename)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(report).Should(Equal(LoadReport{Path: filename, SHA256: "checksum", RowsRead: 2}))
+		g.Expect(balances).Should(Equal([]DeclaredBalance{
+			{Bank: "bca", Opening: 1000, Closing: 1250.5},
+			{Bank: "bri", Account: "444", Opening: 10, Closing: 20},
+		}))
+	})
+
+	t.Run("should return error when invalid balance in row", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"bank", "opening", "closing"},
+			{"bca", "1000", ""},
+		}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		_, _, err := bankStatementStorage.GetDeclaredBalances(context.Background(), filename)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when readerFactory.NewReader returns error", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		bankStatementStorage := NewBankStatementStorage("test.xlsx", nil, mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(nil, fmt.Errorf("open error"))
+
+		_, _, err := bankStatementStorage.GetDeclaredBalances(context.Background(), filename)
+
+		g.Expect(err).ShouldNot(BeNil())
 	})
 }
 
@@ -208,10 +519,11 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 
 		statements := []BankStatement{
 			{
-				Bank:   "BankA",
-				ID:     "1",
-				Amount: 100.0,
-				Time:   time.Now(),
+				Bank:    "BankA",
+				Account: "123",
+				ID:      "1",
+				Amount:  100.0,
+				Time:    time.Now(),
 			},
 			{
 				Bank:   "BankB",
@@ -226,23 +538,32 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 		mockExcelWriter.EXPECT().GetSheetIndex(bankName).Return(-1, nil)
 		mockExcelWriter.EXPECT().NewSheet(bankName).Return(1, nil)
 		mockExcelWriter.EXPECT().SetCellValue(bankName, "A1", "Bank").Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "B1", "ID").Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "C1", "Amount").Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "D1", "Time").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "B1", "Account").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "C1", "ID").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "D1", "Amount").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "E1", "Time").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "F1", "Currency").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "G1", "Reference").Return(nil)
 
 		mockExcelWriter.EXPECT().SetCellValue(bankName, "A2", statements[0].Bank).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "B2", statements[0].ID).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "C2", statements[0].Amount).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "D2", statements[0].Time.Format(time.RFC3339)).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "B2", statements[0].Account).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "C2", statements[0].ID).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "D2", statements[0].Amount).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "E2", statements[0].Time.Format(time.RFC3339)).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "F2", statements[0].Currency).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "G2", statements[0].Reference).Return(nil)
 
 		mockExcelWriter.EXPECT().SetCellValue(bankName, "A3", statements[1].Bank).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "B3", statements[1].ID).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "C3", statements[1].Amount).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "D3", statements[1].Time.Format(time.RFC3339)).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "B3", statements[1].Account).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "C3", statements[1].ID).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "D3", statements[1].Amount).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "E3", statements[1].Time.Format(time.RFC3339)).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "F3", statements[1].Currency).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "G3", statements[1].Reference).Return(nil)
 
 		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
 
-		err := bankStatementStorage.StoreBankStatements(statements, bankName)
+		err := bankStatementStorage.StoreBankStatements(context.Background(), statements, bankName)
 
 		g.Expect(err).Should(BeNil())
 	})
@@ -262,7 +583,7 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 
 		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, fmt.Errorf("new error"))
 
-		err := bankStatementStorage.StoreBankStatements(statements, bankName)
+		err := bankStatementStorage.StoreBankStatements(context.Background(), statements, bankName)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -284,7 +605,7 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
 		mockExcelWriter.EXPECT().GetSheetIndex(bankName).Return(-1, fmt.Errorf("get sheet index error"))
 
-		err := bankStatementStorage.StoreBankStatements(statements, bankName)
+		err := bankStatementStorage.StoreBankStatements(context.Background(), statements, bankName)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -307,7 +628,7 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 		mockExcelWriter.EXPECT().GetSheetIndex(bankName).Return(-1, nil)
 		mockExcelWriter.EXPECT().NewSheet(bankName).Return(1, fmt.Errorf("new sheet error"))
 
-		err := bankStatementStorage.StoreBankStatements(statements, bankName)
+		err := bankStatementStorage.StoreBankStatements(context.Background(), statements, bankName)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -325,10 +646,11 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 
 		statements := []BankStatement{
 			{
-				Bank:   "BankA",
-				ID:     "1",
-				Amount: 100.0,
-				Time:   time.Now(),
+				Bank:    "BankA",
+				Account: "123",
+				ID:      "1",
+				Amount:  100.0,
+				Time:    time.Now(),
 			},
 		}
 		bankName := "BankA"
@@ -337,18 +659,24 @@ func TestBankStatementStorage_StoreBankStatements(t *testing.T) {
 		mockExcelWriter.EXPECT().GetSheetIndex(bankName).Return(-1, nil)
 		mockExcelWriter.EXPECT().NewSheet(bankName).Return(1, nil)
 		mockExcelWriter.EXPECT().SetCellValue(bankName, "A1", "Bank").Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "B1", "ID").Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "C1", "Amount").Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "D1", "Time").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "B1", "Account").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "C1", "ID").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "D1", "Amount").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "E1", "Time").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "F1", "Currency").Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "G1", "Reference").Return(nil)
 
 		mockExcelWriter.EXPECT().SetCellValue(bankName, "A2", statements[0].Bank).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "B2", statements[0].ID).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "C2", statements[0].Amount).Return(nil)
-		mockExcelWriter.EXPECT().SetCellValue(bankName, "D2", statements[0].Time.Format(time.RFC3339)).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "B2", statements[0].Account).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "C2", statements[0].ID).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "D2", statements[0].Amount).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "E2", statements[0].Time.Format(time.RFC3339)).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "F2", statements[0].Currency).Return(nil)
+		mockExcelWriter.EXPECT().SetCellValue(bankName, "G2", statements[0].Reference).Return(nil)
 
 		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(fmt.Errorf("save error"))
 
-		err := bankStatementStorage.StoreBankStatements(statements, bankName)
+		err := bankStatementStorage.StoreBankStatements(context.Background(), statements, bankName)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
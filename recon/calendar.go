@@ -0,0 +1,90 @@
+package recon
+
+import (
+	"fmt"
+	"time"
+)
+
+// Holiday is a day without settlement, for every bank or for one bank.
+type Holiday struct {
+	Date time.Time
+	Name string
+	// Bank limits the holiday to one bank, empty for all banks.
+	Bank string
+}
+
+type holidayKey struct {
+	bank string
+	day  time.Time
+}
+
+// Calendar tells business days from weekends and holidays. The zero value
+// has no weekends and no holidays, so that every day is a business day.
+type Calendar struct {
+	weekend  map[time.Weekday]bool
+	holidays map[holidayKey]string
+}
+
+// NewCalendar returns a calendar with Saturday and Sunday as weekend and the
+// given holidays.
+func NewCalendar(holidays []Holiday) Calendar {
+	c := Calendar{
+		weekend:  map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		holidays: map[holidayKey]string{},
+	}
+	for _, holiday := range holidays {
+		c.holidays[holidayKey{bank: holiday.Bank, day: calendarDay(holiday.Date)}] = holiday.Name
+	}
+	return c
+}
+
+// describe tells how the calendar counts days, e.g. "business days, 12
+// holidays".
+func (c Calendar) describe() string {
+	if !c.businessDays() {
+		return "calendar days"
+	}
+	return fmt.Sprintf("business days, %d holidays", len(c.holidays))
+}
+
+// businessDays tells whether the calendar skips weekends and holidays, as
+// opposed to the zero Calendar counting every day.
+func (c Calendar) businessDays() bool {
+	return c.weekend != nil
+}
+
+// IsBusinessDay reports whether bank settles on the day of t.
+func (c Calendar) IsBusinessDay(t time.Time, bank string) bool {
+	if c.weekend[t.Weekday()] {
+		return false
+	}
+	day := calendarDay(t)
+	if _, ok := c.holidays[holidayKey{day: day}]; ok {
+		return false
+	}
+	if _, ok := c.holidays[holidayKey{bank: bank, day: day}]; ok && bank != "" {
+		return false
+	}
+	return true
+}
+
+// BusinessDays counts the business days of bank after the day of from up to
+// and including the day of to, in the time zone of from; negative when to is
+// on an earlier day. A Friday and the following Monday are one business day
+// apart.
+func (c Calendar) BusinessDays(from, to time.Time, bank string) int {
+	start := truncateToDay(from)
+	end := truncateToDay(to.In(from.Location()))
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+
+	days := 0
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(day, bank) {
+			days++
+		}
+	}
+	return sign * days
+}
@@ -0,0 +1,64 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCalendar_BusinessDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	// 2025-03-28 is a Friday, 2025-03-31 and 2025-04-01 are Idul Fitri
+	calendar := NewCalendar([]Holiday{
+		{Date: day(31), Name: "Idul Fitri"},
+		{Date: day(31).AddDate(0, 0, 1), Name: "Idul Fitri"},
+		{Date: day(27), Name: "Bank holiday", Bank: "bca"},
+	})
+
+	t.Run("skips weekends", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(calendar.IsBusinessDay(day(22), "")).Should(BeFalse())
+		g.Expect(calendar.BusinessDays(day(21), day(24), "")).Should(Equal(1))
+		g.Expect(calendar.BusinessDays(day(21), day(22), "")).Should(Equal(0))
+	})
+
+	t.Run("skips holidays", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(calendar.IsBusinessDay(day(31), "bri")).Should(BeFalse())
+		g.Expect(calendar.BusinessDays(day(28), day(31).AddDate(0, 0, 2), "")).Should(Equal(1))
+	})
+
+	t.Run("skips holidays of the bank only for that bank", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(calendar.IsBusinessDay(day(27), "bca")).Should(BeFalse())
+		g.Expect(calendar.IsBusinessDay(day(27), "bri")).Should(BeTrue())
+		g.Expect(calendar.IsBusinessDay(day(27), "")).Should(BeTrue())
+		g.Expect(calendar.BusinessDays(day(26), day(28), "bca")).Should(Equal(1))
+		g.Expect(calendar.BusinessDays(day(26), day(28), "bri")).Should(Equal(2))
+	})
+
+	t.Run("negative when to is on an earlier day", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(calendar.BusinessDays(day(24), day(21), "")).Should(Equal(-1))
+	})
+
+	t.Run("counts days in the time zone of from", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		friday := time.Date(2025, 3, 21, 10, 0, 0, 0, jakarta)
+		g.Expect(calendar.BusinessDays(friday, time.Date(2025, 3, 23, 17, 0, 0, 0, time.UTC), "")).Should(Equal(1))
+	})
+
+	t.Run("zero value counts calendar days", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Calendar{}.IsBusinessDay(day(22), "")).Should(BeTrue())
+		g.Expect(Calendar{}.BusinessDays(day(21), day(24), "")).Should(Equal(3))
+	})
+}
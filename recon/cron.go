@@ -0,0 +1,185 @@
+package recon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the shorthands ParseCron accepts for common schedules.
+var cronMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+var cronMonths = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+var cronWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// cronField is the allowed values of one field, bit n standing for value n.
+type cronField uint64
+
+func (f cronField) has(n int) bool {
+	return f&(1<<n) != 0
+}
+
+// Cron is a schedule in the five fields of crontab: minute, hour, day of
+// month, month and day of week. Like cron, a day matches when either day
+// field matches if both are restricted.
+type Cron struct {
+	expr    string
+	minute  cronField
+	hour    cronField
+	day     cronField
+	month   cronField
+	weekday cronField
+	// dayAny and weekdayAny are set for day fields written as *.
+	dayAny     bool
+	weekdayAny bool
+}
+
+// ParseCron reads a crontab schedule such as "0 6 * * 1-5", with lists,
+// ranges, steps, month and weekday names, or one of @hourly, @daily,
+// @weekly, @monthly and @yearly.
+func ParseCron(expr string) (Cron, error) {
+	c := Cron{expr: strings.TrimSpace(expr)}
+	fields := strings.Fields(c.expr)
+	if macro, ok := cronMacros[strings.ToLower(c.expr)]; ok {
+		fields = strings.Fields(macro)
+	}
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("invalid cron %q, expected 5 fields", expr)
+	}
+
+	var err error
+	parse := func(field string, min, max int, names []string) cronField {
+		if err != nil {
+			return 0
+		}
+		var f cronField
+		f, err = parseCronField(field, min, max, names)
+		if err != nil {
+			err = fmt.Errorf("invalid cron %q: %w", expr, err)
+		}
+		return f
+	}
+	c.minute = parse(fields[0], 0, 59, nil)
+	c.hour = parse(fields[1], 0, 23, nil)
+	c.day = parse(fields[2], 1, 31, nil)
+	c.month = parse(fields[3], 1, 12, cronMonths)
+	// 7 is Sunday as well
+	c.weekday = parse(fields[4], 0, 7, cronWeekdays)
+	if err != nil {
+		return Cron{}, err
+	}
+	if c.weekday.has(7) {
+		c.weekday |= 1
+	}
+	c.dayAny = fields[2] == "*"
+	c.weekdayAny = fields[4] == "*"
+	return c, nil
+}
+
+func parseCronField(field string, min, max int, names []string) (cronField, error) {
+	value := func(s string) (int, error) {
+		for i, name := range names {
+			if name != "" && strings.EqualFold(s, name) {
+				return i, nil
+			}
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%q is not between %d and %d", s, min, max)
+		}
+		return n, nil
+	}
+
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		spec, stepText, stepped := strings.Cut(part, "/")
+		step := 1
+		if stepped {
+			var err error
+			step, err = strconv.Atoi(stepText)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		first, last := min, max
+		switch {
+		case spec == "*":
+		case strings.Contains(spec, "-"):
+			from, to, _ := strings.Cut(spec, "-")
+			var err error
+			if first, err = value(from); err != nil {
+				return 0, err
+			}
+			if last, err = value(to); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range %q", spec)
+			}
+		default:
+			n, err := value(spec)
+			if err != nil {
+				return 0, err
+			}
+			first = n
+			if !stepped {
+				last = n
+			}
+		}
+		for n := first; n <= last; n += step {
+			f |= 1 << n
+		}
+	}
+	return f, nil
+}
+
+// Next returns the first time after after the schedule fires, in the time
+// zone of after. A time skipped by a daylight saving change is not fired.
+func (c Cron) Next(after time.Time) time.Time {
+	location := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// no schedule fires less than once in 5 years, February 29th included
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		if !c.month.has(int(m)) {
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !c.hour.has(t.Hour()) {
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+		if !c.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c Cron) dayMatches(t time.Time) bool {
+	day := c.day.has(t.Day())
+	weekday := c.weekday.has(int(t.Weekday()))
+	if c.dayAny || c.weekdayAny {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+func (c Cron) String() string {
+	return c.expr
+}
@@ -0,0 +1,74 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Run("reads lists, ranges, steps and names", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		cron, err := ParseCron("*/15 6-8,18 * jan-mar MON-FRI")
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(cron.minute).Should(Equal(cronField(1<<0 | 1<<15 | 1<<30 | 1<<45)))
+		g.Expect(cron.hour).Should(Equal(cronField(1<<6 | 1<<7 | 1<<8 | 1<<18)))
+		g.Expect(cron.month).Should(Equal(cronField(1<<1 | 1<<2 | 1<<3)))
+		g.Expect(cron.weekday).Should(Equal(cronField(0b111110)))
+		g.Expect(cron.String()).Should(Equal("*/15 6-8,18 * jan-mar MON-FRI"))
+	})
+
+	t.Run("reads 7 as Sunday and macros", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		cron, err := ParseCron("0 0 * * 7")
+		g.Expect(err).Should(BeNil())
+		g.Expect(cron.weekday.has(0)).Should(BeTrue())
+
+		cron, err = ParseCron("@daily")
+		g.Expect(err).Should(BeNil())
+		g.Expect(cron.Next(time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC))).Should(Equal(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("rejects invalid schedules", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+			_, err := ParseCron(expr)
+			g.Expect(err).ShouldNot(BeNil(), expr)
+		}
+	})
+}
+
+func TestCron_Next(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	// a Friday
+	friday := time.Date(2025, 8, 1, 10, 30, 0, 0, jakarta)
+
+	for _, tt := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 6 * * *", time.Date(2025, 8, 2, 6, 0, 0, 0, jakarta)},
+		{"45 10 * * *", time.Date(2025, 8, 1, 10, 45, 0, 0, jakarta)},
+		{"30 10 * * *", time.Date(2025, 8, 2, 10, 30, 0, 0, jakarta)},
+		{"0 6 * * 1-5", time.Date(2025, 8, 4, 6, 0, 0, 0, jakarta)},
+		{"0 0 1 * *", time.Date(2025, 9, 1, 0, 0, 0, 0, jakarta)},
+		{"0 0 31 * *", time.Date(2025, 8, 31, 0, 0, 0, 0, jakarta)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, jakarta)},
+		// either day field matches when both are restricted
+		{"0 8 15 * 1", time.Date(2025, 8, 4, 8, 0, 0, 0, jakarta)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		t.Run(tt.expr, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			cron, err := ParseCron(tt.expr)
+			g.Expect(err).Should(BeNil())
+			g.Expect(cron.Next(friday)).Should(Equal(tt.want))
+		})
+	}
+}
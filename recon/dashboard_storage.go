@@ -0,0 +1,161 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type DashboardStorage struct {
+	destinationFileNamePath string
+	destinationSheetName    string
+	excelWriterFactory      ExcelWriterFactory
+}
+
+func NewDashboardStorage(destinationFileNamePath string, destinationSheetName string, excelWriterFactory ExcelWriterFactory) DashboardStorage {
+	return DashboardStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		destinationSheetName:    destinationSheetName,
+		excelWriterFactory:      excelWriterFactory,
+	}
+}
+
+// StoreReport writes the chart source tables to the dashboard sheet and adds
+// a match rate pie, an unmatched amount per bank bar chart and a daily trend
+// line chart next to them.
+func (d DashboardStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := d.excelWriterFactory.New(d.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	index, err := f.GetSheetIndex(d.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet index: %w", err)
+	}
+
+	// the sheet is recreated so the charts and table rows of an earlier run
+	// are not kept next to the new ones
+	if index != -1 {
+		err = f.DeleteSheet(d.destinationSheetName)
+		if err != nil {
+			return fmt.Errorf("failed to delete sheet: %w", err)
+		}
+	}
+
+	_, err = f.NewSheet(d.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	summary := result.Summary
+	matchRate := [][]any{
+		{"Status", "Items"},
+		{"Matched", summary.MatchedTransactions + summary.MatchedBankStatements},
+		{"Unmatched", summary.UnmatchedTransactions + summary.UnmatchedBankStatements},
+	}
+	d.writeTable(f, 1, matchRate)
+
+	// amounts of the account summaries are in the reporting currency, so
+	// accounts holding statements in several currencies add up
+	amountHeader := "Unmatched Amount"
+	if summary.ReportingCurrency != "" {
+		amountHeader += " (" + summary.ReportingCurrency + ")"
+	}
+	perAccount := [][]any{{"Account", amountHeader}}
+	for _, account := range summary.Accounts {
+		if account.UnmatchedBankStatements > 0 {
+			perAccount = append(perAccount, []any{account.BankAccount().String(), account.UnmatchedAmountBankStatements})
+		}
+	}
+	d.writeTable(f, 4, perAccount)
+
+	trend := [][]any{{"Date", "Matched", "Unmatched"}}
+	for _, day := range result.DailyTrend() {
+		trend = append(trend, []any{day.Date.Format(time.DateOnly), day.Matched, day.Unmatched})
+	}
+	d.writeTable(f, 7, trend)
+
+	// charts are added top to bottom so the workbook is the same every run
+	charts := []dashboardChart{{
+		cell: "K1",
+		chart: &excelize.Chart{
+			Type:  excelize.Pie,
+			Title: []excelize.RichTextRun{{Text: "Match Rate"}},
+			Series: []excelize.ChartSeries{{
+				Name:       d.ref(2, 1, 2, 1),
+				Categories: d.ref(1, 2, 1, 3),
+				Values:     d.ref(2, 2, 2, 3),
+			}},
+		},
+	}}
+
+	// a chart over an empty table is rejected by excel
+	if len(perAccount) > 1 {
+		charts = append(charts, dashboardChart{cell: "K17", chart: &excelize.Chart{
+			Type:  excelize.Col,
+			Title: []excelize.RichTextRun{{Text: "Unmatched Amount per Account"}},
+			Series: []excelize.ChartSeries{{
+				Name:       d.ref(5, 1, 5, 1),
+				Categories: d.ref(4, 2, 4, len(perAccount)),
+				Values:     d.ref(5, 2, 5, len(perAccount)),
+			}},
+		}})
+	}
+
+	if len(trend) > 1 {
+		charts = append(charts, dashboardChart{cell: "K33", chart: &excelize.Chart{
+			Type:  excelize.Line,
+			Title: []excelize.RichTextRun{{Text: "Daily Matched vs Unmatched"}},
+			Series: []excelize.ChartSeries{
+				{
+					Name:       d.ref(8, 1, 8, 1),
+					Categories: d.ref(7, 2, 7, len(trend)),
+					Values:     d.ref(8, 2, 8, len(trend)),
+				},
+				{
+					Name:       d.ref(9, 1, 9, 1),
+					Categories: d.ref(7, 2, 7, len(trend)),
+					Values:     d.ref(9, 2, 9, len(trend)),
+				},
+			},
+		}})
+	}
+
+	for _, c := range charts {
+		err = f.AddChart(d.destinationSheetName, c.cell, c.chart)
+		if err != nil {
+			return fmt.Errorf("failed to add chart: %w", err)
+		}
+	}
+
+	err = f.SaveAs(d.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("save as error: %w", err)
+	}
+	return nil
+}
+
+// dashboardChart is a chart and the cell it is anchored at.
+type dashboardChart struct {
+	cell  string
+	chart *excelize.Chart
+}
+
+func (d DashboardStorage) writeTable(f ExcelWriter, firstCol int, rows [][]any) {
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(firstCol+j, i+1)
+			f.SetCellValue(d.destinationSheetName, cell, v)
+		}
+	}
+}
+
+// ref builds an absolute range reference on the dashboard sheet, e.g. 'Dashboard'!$A$2:$A$3.
+func (d DashboardStorage) ref(fromCol, fromRow, toCol, toRow int) string {
+	from, _ := excelize.CoordinatesToCellName(fromCol, fromRow, true)
+	to, _ := excelize.CoordinatesToCellName(toCol, toRow, true)
+	return fmt.Sprintf("'%s'!%s:%s", d.destinationSheetName, from, to)
+}
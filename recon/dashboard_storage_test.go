@@ -0,0 +1,315 @@
+package recon
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+type DashboardStorageSuite struct {
+	mockExcelWriter        *MockExcelWriter
+	mockExcelWriterFactory *MockExcelWriterFactory
+	dashboardStorage       DashboardStorage
+}
+
+func dashboardStorageSuite(ctrl *gomock.Controller) DashboardStorageSuite {
+	mockExcelWriter := NewMockExcelWriter(ctrl)
+	mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+
+	return DashboardStorageSuite{
+		mockExcelWriter:        mockExcelWriter,
+		mockExcelWriterFactory: mockExcelWriterFactory,
+		dashboardStorage:       NewDashboardStorage("test.xlsx", "Dashboard", mockExcelWriterFactory),
+	}
+}
+
+func TestDashboardStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.xlsx"
+	destinationSheetName := "Dashboard"
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	result := Result{
+		StartDate: day,
+		EndDate:   day,
+		Summary: Summary{
+			TotalTransactions:       2,
+			MatchedTransactions:     1,
+			UnmatchedTransactions:   1,
+			TotalBankStatements:     2,
+			MatchedBankStatements:   1,
+			UnmatchedBankStatements: 1,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 2, MatchedBankStatements: 1, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 300},
+				{Bank: "BRI", TotalBankStatements: 1, MatchedBankStatements: 1},
+			},
+		},
+		Matches:               []Match{{Transaction: Transaction{ID: "1", Amount: 100, Time: day}, BankStatement: BankStatement{Bank: "BCA", Amount: 100, Time: day}}},
+		UnmatchedTransactions: []Transaction{{ID: "2", Amount: 200, Time: day}},
+		UnmatchedBankStatements: []BankStatementDiscrepancy{
+			{Bank: "BCA", Statements: []BankStatement{{Bank: "BCA", Amount: 300, Time: day}}},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		cells := map[string]any{
+			"A1": "Status", "B1": "Items",
+			"A2": "Matched", "B2": 2,
+			"A3": "Unmatched", "B3": 2,
+			"D1": "Account", "E1": "Unmatched Amount",
+			"D2": "BCA", "E2": 300.0,
+			"G1": "Date", "H1": "Matched", "I1": "Unmatched",
+			"G2": "2025-08-01", "H2": 1, "I2": 2,
+		}
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(1, nil)
+		for cell, v := range cells {
+			suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
+		var chartTypes []excelize.ChartType
+		suite.mockExcelWriter.EXPECT().AddChart(destinationSheetName, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(sheet, cell string, chart *excelize.Chart, combo ...*excelize.Chart) error {
+				chartTypes = append(chartTypes, chart.Type)
+				return nil
+			}).Times(3)
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(chartTypes).Should(Equal([]excelize.ChartType{excelize.Pie, excelize.Col, excelize.Line}))
+	})
+
+	t.Run("skip the bank chart when every statement matched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		suite.mockExcelWriter.EXPECT().AddChart(destinationSheetName, "K1", gomock.Any()).Return(nil)
+		suite.mockExcelWriter.EXPECT().AddChart(destinationSheetName, "K33", gomock.Any()).Return(nil)
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		allMatched := result
+		allMatched.UnmatchedBankStatements = nil
+		allMatched.Summary.Accounts = []AccountSummary{{Bank: "BCA", TotalBankStatements: 1, MatchedBankStatements: 1}}
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), allMatched)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("charts account amounts in the reporting currency", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		cells := map[string]any{}
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(sheet, cell string, v any) error {
+				cells[cell] = v
+				return nil
+			}).AnyTimes()
+		suite.mockExcelWriter.EXPECT().AddChart(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).Times(3)
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		// 10 USD and 50000 IDR on one account at 15000 IDR per USD
+		mixed := result
+		mixed.Summary.ReportingCurrency = "IDR"
+		mixed.Summary.Accounts = []AccountSummary{{Bank: "BCA", TotalBankStatements: 2, UnmatchedBankStatements: 2, UnmatchedAmountBankStatements: 200000}}
+		mixed.UnmatchedBankStatements = []BankStatementDiscrepancy{{Bank: "BCA", Statements: []BankStatement{
+			{Bank: "BCA", Amount: 10, Currency: "USD", Time: day},
+			{Bank: "BCA", Amount: 50000, Currency: "IDR", Time: day},
+		}}}
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), mixed)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(cells).Should(HaveKeyWithValue("E1", "Unmatched Amount (IDR)"))
+		g.Expect(cells).Should(HaveKeyWithValue("D2", "BCA"))
+		g.Expect(cells).Should(HaveKeyWithValue("E2", 200000.0))
+		g.Expect(cells).ShouldNot(HaveKey("D3"))
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, errors.New("open file error"))
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("replaces the charts and rows of an earlier run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		path := filepath.Join(t.TempDir(), "recon.xlsx")
+		storage := NewDashboardStorage(path, destinationSheetName, ExcelFactory{})
+
+		// the first run covers a second day of trend rows the second run has not
+		longer := result
+		longer.EndDate = day.AddDate(0, 0, 1)
+		g.Expect(storage.StoreReport(context.Background(), longer)).Should(Succeed())
+		g.Expect(storage.StoreReport(context.Background(), result)).Should(Succeed())
+
+		f, err := excelize.OpenFile(path)
+		g.Expect(err).Should(BeNil())
+		defer f.Close()
+		trend, err := f.GetCellValue(destinationSheetName, "G3")
+		g.Expect(err).Should(BeNil())
+		g.Expect(trend).Should(BeEmpty())
+		g.Expect(sheetCharts(g, path, destinationSheetName)).Should(Equal(3))
+	})
+
+	t.Run("delete sheet error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(errors.New("delete sheet error"))
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("add chart error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		suite.mockExcelWriter.EXPECT().AddChart(destinationSheetName, gomock.Any(), gomock.Any()).Return(errors.New("add chart error"))
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("save as error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := dashboardStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().DeleteSheet(destinationSheetName).Return(nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		suite.mockExcelWriter.EXPECT().AddChart(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).Times(3)
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
+
+		err := suite.dashboardStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+// sheetCharts counts the charts drawn on sheet of the workbook at filename by
+// following the sheet to its drawing, since excelize has no chart getter.
+func sheetCharts(g *WithT, filename, sheet string) int {
+	r, err := zip.OpenReader(filename)
+	g.Expect(err).Should(BeNil())
+	defer r.Close()
+
+	read := func(name string, v any) {
+		f, err := r.Open(strings.TrimPrefix(name, "/"))
+		g.Expect(err).Should(BeNil())
+		defer f.Close()
+		g.Expect(xml.NewDecoder(f).Decode(v)).Should(Succeed())
+	}
+	type relationships struct {
+		Relationship []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		}
+	}
+	target := func(rels relationships, id string) string {
+		for _, rel := range rels.Relationship {
+			if id == "" || rel.ID == id {
+				return rel.Target
+			}
+		}
+		return ""
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	read("xl/workbook.xml", &workbook)
+	var workbookRels relationships
+	read("xl/_rels/workbook.xml.rels", &workbookRels)
+
+	var sheetPath string
+	for _, s := range workbook.Sheets {
+		if s.Name == sheet {
+			sheetPath = path.Join("xl", strings.TrimPrefix(target(workbookRels, s.ID), "/xl/"))
+		}
+	}
+	g.Expect(sheetPath).ShouldNot(BeEmpty())
+
+	var sheetRels relationships
+	read(path.Join(path.Dir(sheetPath), "_rels", path.Base(sheetPath)+".rels"), &sheetRels)
+	var drawing struct {
+		Anchors []struct {
+			Chart *struct{} `xml:"graphicFrame>graphic>graphicData>chart"`
+		} `xml:"twoCellAnchor"`
+	}
+	read(path.Join(path.Dir(sheetPath), target(sheetRels, "")), &drawing)
+
+	charts := 0
+	for _, anchor := range drawing.Anchors {
+		if anchor.Chart != nil {
+			charts++
+		}
+	}
+	return charts
+}
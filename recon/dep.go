@@ -2,6 +2,8 @@
 package recon
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"github.com/xuri/excelize/v2"
@@ -15,28 +17,53 @@ type ExcelWriter interface {
 	SetCellValue(sheet, axis string, value interface{}) error
 	GetSheetIndex(name string) (int, error)
 	NewSheet(name string) (int, error)
+	DeleteSheet(name string) error
 	SaveAs(name string, options ...excelize.Options) error
+	AddChart(sheet, cell string, chart *excelize.Chart, combo ...*excelize.Chart) error
 }
 
 type ReaderFactory interface {
-	NewReader(filename string) (Reader, error)
+	// NewReader opens filename. The reader stops between rows with the error
+	// of ctx once ctx is done.
+	NewReader(ctx context.Context, filename string) (Reader, error)
+	// Open opens filename as it is, e.g. a YAML file. Reads fail with the
+	// error of ctx once ctx is done.
+	Open(ctx context.Context, filename string) (io.ReadCloser, error)
 }
 
 type Reader interface {
 	ReadAll() ([][]string, error)
+	Checksum() string
 	Close() error
 }
 
+type FileWriterFactory interface {
+	Create(path string) (io.WriteCloser, error)
+}
+
 type TransactionStorageProvider interface {
-	StoreTransactions(transactions []Transaction) error
-	GetTransactions(filename string, startDate time.Time, endDate time.Time) ([]Transaction, error)
+	StoreTransactions(ctx context.Context, transactions []Transaction) error
+	GetTransactions(ctx context.Context, filename string, startDate time.Time, endDate time.Time) ([]Transaction, LoadReport, error)
 }
 
 type BankStatementStorageProvider interface {
-	StoreBankStatements(statements []BankStatement, bankName string) error
-	GetBankStatements(filename string, startDate time.Time, endDate time.Time) ([]BankStatement, error)
+	StoreBankStatements(ctx context.Context, statements []BankStatement, bankName string) error
+	GetBankStatements(ctx context.Context, filename string, startDate time.Time, endDate time.Time) ([]BankStatement, LoadReport, error)
 }
 
 type SummaryStorageProvider interface {
-	StoreSummary(total Summary) error
+	StoreSummary(ctx context.Context, total Summary) error
+}
+
+type ReportStorageProvider interface {
+	StoreReport(ctx context.Context, result Result) error
+}
+
+type LedgerProvider interface {
+	GetOpenItems(ctx context.Context, before time.Time) ([]LedgerItem, error)
+	UpdateLedger(ctx context.Context, result Result) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
 }
@@ -10,6 +10,8 @@
 package recon
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
@@ -104,6 +106,87 @@ func (m *MockExcelWriter) EXPECT() *MockExcelWriterMockRecorder {
 	return m.recorder
 }
 
+// AddChart mocks base method.
+func (m *MockExcelWriter) AddChart(sheet, cell string, chart *excelize.Chart, combo ...*excelize.Chart) error {
+	m.ctrl.T.Helper()
+	varargs := []any{sheet, cell, chart}
+	for _, a := range combo {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddChart", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddChart indicates an expected call of AddChart.
+func (mr *MockExcelWriterMockRecorder) AddChart(sheet, cell, chart any, combo ...any) *MockExcelWriterAddChartCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{sheet, cell, chart}, combo...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChart", reflect.TypeOf((*MockExcelWriter)(nil).AddChart), varargs...)
+	return &MockExcelWriterAddChartCall{Call: call}
+}
+
+// MockExcelWriterAddChartCall wrap *gomock.Call
+type MockExcelWriterAddChartCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockExcelWriterAddChartCall) Return(arg0 error) *MockExcelWriterAddChartCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockExcelWriterAddChartCall) Do(f func(string, string, *excelize.Chart, ...*excelize.Chart) error) *MockExcelWriterAddChartCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockExcelWriterAddChartCall) DoAndReturn(f func(string, string, *excelize.Chart, ...*excelize.Chart) error) *MockExcelWriterAddChartCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteSheet mocks base method.
+func (m *MockExcelWriter) DeleteSheet(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSheet", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSheet indicates an expected call of DeleteSheet.
+func (mr *MockExcelWriterMockRecorder) DeleteSheet(name any) *MockExcelWriterDeleteSheetCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSheet", reflect.TypeOf((*MockExcelWriter)(nil).DeleteSheet), name)
+	return &MockExcelWriterDeleteSheetCall{Call: call}
+}
+
+// MockExcelWriterDeleteSheetCall wrap *gomock.Call
+type MockExcelWriterDeleteSheetCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockExcelWriterDeleteSheetCall) Return(arg0 error) *MockExcelWriterDeleteSheetCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockExcelWriterDeleteSheetCall) Do(f func(string) error) *MockExcelWriterDeleteSheetCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockExcelWriterDeleteSheetCall) DoAndReturn(f func(string) error) *MockExcelWriterDeleteSheetCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetSheetIndex mocks base method.
 func (m *MockExcelWriter) GetSheetIndex(name string) (int, error) {
 	m.ctrl.T.Helper()
@@ -288,18 +371,18 @@ func (m *MockReaderFactory) EXPECT() *MockReaderFactoryMockRecorder {
 }
 
 // NewReader mocks base method.
-func (m *MockReaderFactory) NewReader(filename string) (Reader, error) {
+func (m *MockReaderFactory) NewReader(ctx context.Context, filename string) (Reader, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewReader", filename)
+	ret := m.ctrl.Call(m, "NewReader", ctx, filename)
 	ret0, _ := ret[0].(Reader)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // NewReader indicates an expected call of NewReader.
-func (mr *MockReaderFactoryMockRecorder) NewReader(filename any) *MockReaderFactoryNewReaderCall {
+func (mr *MockReaderFactoryMockRecorder) NewReader(ctx, filename any) *MockReaderFactoryNewReaderCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewReader", reflect.TypeOf((*MockReaderFactory)(nil).NewReader), filename)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewReader", reflect.TypeOf((*MockReaderFactory)(nil).NewReader), ctx, filename)
 	return &MockReaderFactoryNewReaderCall{Call: call}
 }
 
@@ -315,13 +398,52 @@ func (c *MockReaderFactoryNewReaderCall) Return(arg0 Reader, arg1 error) *MockRe
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockReaderFactoryNewReaderCall) Do(f func(string) (Reader, error)) *MockReaderFactoryNewReaderCall {
+func (c *MockReaderFactoryNewReaderCall) Do(f func(context.Context, string) (Reader, error)) *MockReaderFactoryNewReaderCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockReaderFactoryNewReaderCall) DoAndReturn(f func(string) (Reader, error)) *MockReaderFactoryNewReaderCall {
+func (c *MockReaderFactoryNewReaderCall) DoAndReturn(f func(context.Context, string) (Reader, error)) *MockReaderFactoryNewReaderCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Open mocks base method.
+func (m *MockReaderFactory) Open(ctx context.Context, filename string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, filename)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockReaderFactoryMockRecorder) Open(ctx, filename any) *MockReaderFactoryOpenCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockReaderFactory)(nil).Open), ctx, filename)
+	return &MockReaderFactoryOpenCall{Call: call}
+}
+
+// MockReaderFactoryOpenCall wrap *gomock.Call
+type MockReaderFactoryOpenCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockReaderFactoryOpenCall) Return(arg0 io.ReadCloser, arg1 error) *MockReaderFactoryOpenCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockReaderFactoryOpenCall) Do(f func(context.Context, string) (io.ReadCloser, error)) *MockReaderFactoryOpenCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockReaderFactoryOpenCall) DoAndReturn(f func(context.Context, string) (io.ReadCloser, error)) *MockReaderFactoryOpenCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -350,6 +472,44 @@ func (m *MockReader) EXPECT() *MockReaderMockRecorder {
 	return m.recorder
 }
 
+// Checksum mocks base method.
+func (m *MockReader) Checksum() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checksum")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Checksum indicates an expected call of Checksum.
+func (mr *MockReaderMockRecorder) Checksum() *MockReaderChecksumCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checksum", reflect.TypeOf((*MockReader)(nil).Checksum))
+	return &MockReaderChecksumCall{Call: call}
+}
+
+// MockReaderChecksumCall wrap *gomock.Call
+type MockReaderChecksumCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockReaderChecksumCall) Return(arg0 string) *MockReaderChecksumCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockReaderChecksumCall) Do(f func() string) *MockReaderChecksumCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockReaderChecksumCall) DoAndReturn(f func() string) *MockReaderChecksumCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Close mocks base method.
 func (m *MockReader) Close() error {
 	m.ctrl.T.Helper()
@@ -427,6 +587,69 @@ func (c *MockReaderReadAllCall) DoAndReturn(f func() ([][]string, error)) *MockR
 	return c
 }
 
+// MockFileWriterFactory is a mock of FileWriterFactory interface.
+type MockFileWriterFactory struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileWriterFactoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFileWriterFactoryMockRecorder is the mock recorder for MockFileWriterFactory.
+type MockFileWriterFactoryMockRecorder struct {
+	mock *MockFileWriterFactory
+}
+
+// NewMockFileWriterFactory creates a new mock instance.
+func NewMockFileWriterFactory(ctrl *gomock.Controller) *MockFileWriterFactory {
+	mock := &MockFileWriterFactory{ctrl: ctrl}
+	mock.recorder = &MockFileWriterFactoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileWriterFactory) EXPECT() *MockFileWriterFactoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFileWriterFactory) Create(path string) (io.WriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", path)
+	ret0, _ := ret[0].(io.WriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFileWriterFactoryMockRecorder) Create(path any) *MockFileWriterFactoryCreateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFileWriterFactory)(nil).Create), path)
+	return &MockFileWriterFactoryCreateCall{Call: call}
+}
+
+// MockFileWriterFactoryCreateCall wrap *gomock.Call
+type MockFileWriterFactoryCreateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockFileWriterFactoryCreateCall) Return(arg0 io.WriteCloser, arg1 error) *MockFileWriterFactoryCreateCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockFileWriterFactoryCreateCall) Do(f func(string) (io.WriteCloser, error)) *MockFileWriterFactoryCreateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockFileWriterFactoryCreateCall) DoAndReturn(f func(string) (io.WriteCloser, error)) *MockFileWriterFactoryCreateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // MockTransactionStorageProvider is a mock of TransactionStorageProvider interface.
 type MockTransactionStorageProvider struct {
 	ctrl     *gomock.Controller
@@ -452,18 +675,19 @@ func (m *MockTransactionStorageProvider) EXPECT() *MockTransactionStorageProvide
 }
 
 // GetTransactions mocks base method.
-func (m *MockTransactionStorageProvider) GetTransactions(filename string, startDate, endDate time.Time) ([]Transaction, error) {
+func (m *MockTransactionStorageProvider) GetTransactions(ctx context.Context, filename string, startDate, endDate time.Time) ([]Transaction, LoadReport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactions", filename, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, filename, startDate, endDate)
 	ret0, _ := ret[0].([]Transaction)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(LoadReport)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetTransactions indicates an expected call of GetTransactions.
-func (mr *MockTransactionStorageProviderMockRecorder) GetTransactions(filename, startDate, endDate any) *MockTransactionStorageProviderGetTransactionsCall {
+func (mr *MockTransactionStorageProviderMockRecorder) GetTransactions(ctx, filename, startDate, endDate any) *MockTransactionStorageProviderGetTransactionsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockTransactionStorageProvider)(nil).GetTransactions), filename, startDate, endDate)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockTransactionStorageProvider)(nil).GetTransactions), ctx, filename, startDate, endDate)
 	return &MockTransactionStorageProviderGetTransactionsCall{Call: call}
 }
 
@@ -473,35 +697,35 @@ type MockTransactionStorageProviderGetTransactionsCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockTransactionStorageProviderGetTransactionsCall) Return(arg0 []Transaction, arg1 error) *MockTransactionStorageProviderGetTransactionsCall {
-	c.Call = c.Call.Return(arg0, arg1)
+func (c *MockTransactionStorageProviderGetTransactionsCall) Return(arg0 []Transaction, arg1 LoadReport, arg2 error) *MockTransactionStorageProviderGetTransactionsCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockTransactionStorageProviderGetTransactionsCall) Do(f func(string, time.Time, time.Time) ([]Transaction, error)) *MockTransactionStorageProviderGetTransactionsCall {
+func (c *MockTransactionStorageProviderGetTransactionsCall) Do(f func(context.Context, string, time.Time, time.Time) ([]Transaction, LoadReport, error)) *MockTransactionStorageProviderGetTransactionsCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockTransactionStorageProviderGetTransactionsCall) DoAndReturn(f func(string, time.Time, time.Time) ([]Transaction, error)) *MockTransactionStorageProviderGetTransactionsCall {
+func (c *MockTransactionStorageProviderGetTransactionsCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time) ([]Transaction, LoadReport, error)) *MockTransactionStorageProviderGetTransactionsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // StoreTransactions mocks base method.
-func (m *MockTransactionStorageProvider) StoreTransactions(transactions []Transaction) error {
+func (m *MockTransactionStorageProvider) StoreTransactions(ctx context.Context, transactions []Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreTransactions", transactions)
+	ret := m.ctrl.Call(m, "StoreTransactions", ctx, transactions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreTransactions indicates an expected call of StoreTransactions.
-func (mr *MockTransactionStorageProviderMockRecorder) StoreTransactions(transactions any) *MockTransactionStorageProviderStoreTransactionsCall {
+func (mr *MockTransactionStorageProviderMockRecorder) StoreTransactions(ctx, transactions any) *MockTransactionStorageProviderStoreTransactionsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTransactions", reflect.TypeOf((*MockTransactionStorageProvider)(nil).StoreTransactions), transactions)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTransactions", reflect.TypeOf((*MockTransactionStorageProvider)(nil).StoreTransactions), ctx, transactions)
 	return &MockTransactionStorageProviderStoreTransactionsCall{Call: call}
 }
 
@@ -517,13 +741,13 @@ func (c *MockTransactionStorageProviderStoreTransactionsCall) Return(arg0 error)
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockTransactionStorageProviderStoreTransactionsCall) Do(f func([]Transaction) error) *MockTransactionStorageProviderStoreTransactionsCall {
+func (c *MockTransactionStorageProviderStoreTransactionsCall) Do(f func(context.Context, []Transaction) error) *MockTransactionStorageProviderStoreTransactionsCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockTransactionStorageProviderStoreTransactionsCall) DoAndReturn(f func([]Transaction) error) *MockTransactionStorageProviderStoreTransactionsCall {
+func (c *MockTransactionStorageProviderStoreTransactionsCall) DoAndReturn(f func(context.Context, []Transaction) error) *MockTransactionStorageProviderStoreTransactionsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -553,18 +777,19 @@ func (m *MockBankStatementStorageProvider) EXPECT() *MockBankStatementStoragePro
 }
 
 // GetBankStatements mocks base method.
-func (m *MockBankStatementStorageProvider) GetBankStatements(filename string, startDate, endDate time.Time) ([]BankStatement, error) {
+func (m *MockBankStatementStorageProvider) GetBankStatements(ctx context.Context, filename string, startDate, endDate time.Time) ([]BankStatement, LoadReport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBankStatements", filename, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetBankStatements", ctx, filename, startDate, endDate)
 	ret0, _ := ret[0].([]BankStatement)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(LoadReport)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetBankStatements indicates an expected call of GetBankStatements.
-func (mr *MockBankStatementStorageProviderMockRecorder) GetBankStatements(filename, startDate, endDate any) *MockBankStatementStorageProviderGetBankStatementsCall {
+func (mr *MockBankStatementStorageProviderMockRecorder) GetBankStatements(ctx, filename, startDate, endDate any) *MockBankStatementStorageProviderGetBankStatementsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBankStatements", reflect.TypeOf((*MockBankStatementStorageProvider)(nil).GetBankStatements), filename, startDate, endDate)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBankStatements", reflect.TypeOf((*MockBankStatementStorageProvider)(nil).GetBankStatements), ctx, filename, startDate, endDate)
 	return &MockBankStatementStorageProviderGetBankStatementsCall{Call: call}
 }
 
@@ -574,35 +799,35 @@ type MockBankStatementStorageProviderGetBankStatementsCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockBankStatementStorageProviderGetBankStatementsCall) Return(arg0 []BankStatement, arg1 error) *MockBankStatementStorageProviderGetBankStatementsCall {
-	c.Call = c.Call.Return(arg0, arg1)
+func (c *MockBankStatementStorageProviderGetBankStatementsCall) Return(arg0 []BankStatement, arg1 LoadReport, arg2 error) *MockBankStatementStorageProviderGetBankStatementsCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockBankStatementStorageProviderGetBankStatementsCall) Do(f func(string, time.Time, time.Time) ([]BankStatement, error)) *MockBankStatementStorageProviderGetBankStatementsCall {
+func (c *MockBankStatementStorageProviderGetBankStatementsCall) Do(f func(context.Context, string, time.Time, time.Time) ([]BankStatement, LoadReport, error)) *MockBankStatementStorageProviderGetBankStatementsCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockBankStatementStorageProviderGetBankStatementsCall) DoAndReturn(f func(string, time.Time, time.Time) ([]BankStatement, error)) *MockBankStatementStorageProviderGetBankStatementsCall {
+func (c *MockBankStatementStorageProviderGetBankStatementsCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time) ([]BankStatement, LoadReport, error)) *MockBankStatementStorageProviderGetBankStatementsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // StoreBankStatements mocks base method.
-func (m *MockBankStatementStorageProvider) StoreBankStatements(statements []BankStatement, bankName string) error {
+func (m *MockBankStatementStorageProvider) StoreBankStatements(ctx context.Context, statements []BankStatement, bankName string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreBankStatements", statements, bankName)
+	ret := m.ctrl.Call(m, "StoreBankStatements", ctx, statements, bankName)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreBankStatements indicates an expected call of StoreBankStatements.
-func (mr *MockBankStatementStorageProviderMockRecorder) StoreBankStatements(statements, bankName any) *MockBankStatementStorageProviderStoreBankStatementsCall {
+func (mr *MockBankStatementStorageProviderMockRecorder) StoreBankStatements(ctx, statements, bankName any) *MockBankStatementStorageProviderStoreBankStatementsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBankStatements", reflect.TypeOf((*MockBankStatementStorageProvider)(nil).StoreBankStatements), statements, bankName)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBankStatements", reflect.TypeOf((*MockBankStatementStorageProvider)(nil).StoreBankStatements), ctx, statements, bankName)
 	return &MockBankStatementStorageProviderStoreBankStatementsCall{Call: call}
 }
 
@@ -618,13 +843,13 @@ func (c *MockBankStatementStorageProviderStoreBankStatementsCall) Return(arg0 er
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockBankStatementStorageProviderStoreBankStatementsCall) Do(f func([]BankStatement, string) error) *MockBankStatementStorageProviderStoreBankStatementsCall {
+func (c *MockBankStatementStorageProviderStoreBankStatementsCall) Do(f func(context.Context, []BankStatement, string) error) *MockBankStatementStorageProviderStoreBankStatementsCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockBankStatementStorageProviderStoreBankStatementsCall) DoAndReturn(f func([]BankStatement, string) error) *MockBankStatementStorageProviderStoreBankStatementsCall {
+func (c *MockBankStatementStorageProviderStoreBankStatementsCall) DoAndReturn(f func(context.Context, []BankStatement, string) error) *MockBankStatementStorageProviderStoreBankStatementsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -654,17 +879,17 @@ func (m *MockSummaryStorageProvider) EXPECT() *MockSummaryStorageProviderMockRec
 }
 
 // StoreSummary mocks base method.
-func (m *MockSummaryStorageProvider) StoreSummary(total Summary) error {
+func (m *MockSummaryStorageProvider) StoreSummary(ctx context.Context, total Summary) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreSummary", total)
+	ret := m.ctrl.Call(m, "StoreSummary", ctx, total)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreSummary indicates an expected call of StoreSummary.
-func (mr *MockSummaryStorageProviderMockRecorder) StoreSummary(total any) *MockSummaryStorageProviderStoreSummaryCall {
+func (mr *MockSummaryStorageProviderMockRecorder) StoreSummary(ctx, total any) *MockSummaryStorageProviderStoreSummaryCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreSummary", reflect.TypeOf((*MockSummaryStorageProvider)(nil).StoreSummary), total)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreSummary", reflect.TypeOf((*MockSummaryStorageProvider)(nil).StoreSummary), ctx, total)
 	return &MockSummaryStorageProviderStoreSummaryCall{Call: call}
 }
 
@@ -680,13 +905,238 @@ func (c *MockSummaryStorageProviderStoreSummaryCall) Return(arg0 error) *MockSum
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSummaryStorageProviderStoreSummaryCall) Do(f func(Summary) error) *MockSummaryStorageProviderStoreSummaryCall {
+func (c *MockSummaryStorageProviderStoreSummaryCall) Do(f func(context.Context, Summary) error) *MockSummaryStorageProviderStoreSummaryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSummaryStorageProviderStoreSummaryCall) DoAndReturn(f func(context.Context, Summary) error) *MockSummaryStorageProviderStoreSummaryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockReportStorageProvider is a mock of ReportStorageProvider interface.
+type MockReportStorageProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportStorageProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockReportStorageProviderMockRecorder is the mock recorder for MockReportStorageProvider.
+type MockReportStorageProviderMockRecorder struct {
+	mock *MockReportStorageProvider
+}
+
+// NewMockReportStorageProvider creates a new mock instance.
+func NewMockReportStorageProvider(ctrl *gomock.Controller) *MockReportStorageProvider {
+	mock := &MockReportStorageProvider{ctrl: ctrl}
+	mock.recorder = &MockReportStorageProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportStorageProvider) EXPECT() *MockReportStorageProviderMockRecorder {
+	return m.recorder
+}
+
+// StoreReport mocks base method.
+func (m *MockReportStorageProvider) StoreReport(ctx context.Context, result Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreReport", ctx, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreReport indicates an expected call of StoreReport.
+func (mr *MockReportStorageProviderMockRecorder) StoreReport(ctx, result any) *MockReportStorageProviderStoreReportCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreReport", reflect.TypeOf((*MockReportStorageProvider)(nil).StoreReport), ctx, result)
+	return &MockReportStorageProviderStoreReportCall{Call: call}
+}
+
+// MockReportStorageProviderStoreReportCall wrap *gomock.Call
+type MockReportStorageProviderStoreReportCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockReportStorageProviderStoreReportCall) Return(arg0 error) *MockReportStorageProviderStoreReportCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockReportStorageProviderStoreReportCall) Do(f func(context.Context, Result) error) *MockReportStorageProviderStoreReportCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockReportStorageProviderStoreReportCall) DoAndReturn(f func(context.Context, Result) error) *MockReportStorageProviderStoreReportCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockLedgerProvider is a mock of LedgerProvider interface.
+type MockLedgerProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockLedgerProviderMockRecorder is the mock recorder for MockLedgerProvider.
+type MockLedgerProviderMockRecorder struct {
+	mock *MockLedgerProvider
+}
+
+// NewMockLedgerProvider creates a new mock instance.
+func NewMockLedgerProvider(ctrl *gomock.Controller) *MockLedgerProvider {
+	mock := &MockLedgerProvider{ctrl: ctrl}
+	mock.recorder = &MockLedgerProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerProvider) EXPECT() *MockLedgerProviderMockRecorder {
+	return m.recorder
+}
+
+// GetOpenItems mocks base method.
+func (m *MockLedgerProvider) GetOpenItems(ctx context.Context, before time.Time) ([]LedgerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenItems", ctx, before)
+	ret0, _ := ret[0].([]LedgerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenItems indicates an expected call of GetOpenItems.
+func (mr *MockLedgerProviderMockRecorder) GetOpenItems(ctx, before any) *MockLedgerProviderGetOpenItemsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenItems", reflect.TypeOf((*MockLedgerProvider)(nil).GetOpenItems), ctx, before)
+	return &MockLedgerProviderGetOpenItemsCall{Call: call}
+}
+
+// MockLedgerProviderGetOpenItemsCall wrap *gomock.Call
+type MockLedgerProviderGetOpenItemsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLedgerProviderGetOpenItemsCall) Return(arg0 []LedgerItem, arg1 error) *MockLedgerProviderGetOpenItemsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLedgerProviderGetOpenItemsCall) Do(f func(context.Context, time.Time) ([]LedgerItem, error)) *MockLedgerProviderGetOpenItemsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLedgerProviderGetOpenItemsCall) DoAndReturn(f func(context.Context, time.Time) ([]LedgerItem, error)) *MockLedgerProviderGetOpenItemsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateLedger mocks base method.
+func (m *MockLedgerProvider) UpdateLedger(ctx context.Context, result Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLedger", ctx, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLedger indicates an expected call of UpdateLedger.
+func (mr *MockLedgerProviderMockRecorder) UpdateLedger(ctx, result any) *MockLedgerProviderUpdateLedgerCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLedger", reflect.TypeOf((*MockLedgerProvider)(nil).UpdateLedger), ctx, result)
+	return &MockLedgerProviderUpdateLedgerCall{Call: call}
+}
+
+// MockLedgerProviderUpdateLedgerCall wrap *gomock.Call
+type MockLedgerProviderUpdateLedgerCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLedgerProviderUpdateLedgerCall) Return(arg0 error) *MockLedgerProviderUpdateLedgerCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLedgerProviderUpdateLedgerCall) Do(f func(context.Context, Result) error) *MockLedgerProviderUpdateLedgerCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLedgerProviderUpdateLedgerCall) DoAndReturn(f func(context.Context, Result) error) *MockLedgerProviderUpdateLedgerCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, notification Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, notification any) *MockNotifierNotifyCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, notification)
+	return &MockNotifierNotifyCall{Call: call}
+}
+
+// MockNotifierNotifyCall wrap *gomock.Call
+type MockNotifierNotifyCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockNotifierNotifyCall) Return(arg0 error) *MockNotifierNotifyCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockNotifierNotifyCall) Do(f func(context.Context, Notification) error) *MockNotifierNotifyCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSummaryStorageProviderStoreSummaryCall) DoAndReturn(f func(Summary) error) *MockSummaryStorageProviderStoreSummaryCall {
+func (c *MockNotifierNotifyCall) DoAndReturn(f func(context.Context, Notification) error) *MockNotifierNotifyCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -0,0 +1,211 @@
+package recon
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DuplicateKey names the fields two items must agree on to be duplicates.
+type DuplicateKey string
+
+const (
+	// DuplicateKeyID compares IDs.
+	DuplicateKeyID DuplicateKey = "id"
+	// DuplicateKeyAmountTime compares amounts and times together.
+	DuplicateKeyAmountTime DuplicateKey = "amount-time"
+	// DuplicateKeyReference compares bank references. It only applies to
+	// bank statements with a reference.
+	DuplicateKeyReference DuplicateKey = "reference"
+)
+
+// DuplicatePolicy says what happens to duplicates once detected.
+type DuplicatePolicy string
+
+const (
+	// DuplicateReject fails the run.
+	DuplicateReject DuplicatePolicy = "reject"
+	// DuplicateKeepFirst leaves later copies out of the recon.
+	DuplicateKeepFirst DuplicatePolicy = "keep-first"
+	// DuplicateFlag keeps every copy in the recon and only reports them.
+	DuplicateFlag DuplicatePolicy = "flag"
+)
+
+// DuplicateConfig controls duplicate detection. The zero value detects none.
+type DuplicateConfig struct {
+	// Keys identify an item: an item duplicates an earlier item of the same
+	// side when they agree on any of the keys. Bank statements are only
+	// compared within their account. Empty disables detection.
+	Keys []DuplicateKey `yaml:"keys"`
+	// Policy applies to detected duplicates. Empty means DuplicateFlag.
+	Policy DuplicatePolicy `yaml:"policy"`
+}
+
+// Validate checks the keys and the policy.
+func (d DuplicateConfig) Validate() error {
+	for _, key := range d.Keys {
+		switch key {
+		case DuplicateKeyID, DuplicateKeyAmountTime, DuplicateKeyReference:
+		default:
+			return fmt.Errorf("unknown duplicate key %q", key)
+		}
+	}
+	switch d.Policy {
+	case "", DuplicateReject, DuplicateKeepFirst, DuplicateFlag:
+	default:
+		return fmt.Errorf("unknown duplicate policy %q", d.Policy)
+	}
+	return nil
+}
+
+func (d DuplicateConfig) policy() DuplicatePolicy {
+	if d.Policy == "" {
+		return DuplicateFlag
+	}
+	return d.Policy
+}
+
+// DuplicateTransaction is a transaction repeating Original on Key.
+type DuplicateTransaction struct {
+	Key         DuplicateKey
+	Transaction Transaction
+	Original    Transaction
+}
+
+// DuplicateBankStatement is a bank statement repeating Original on Key.
+type DuplicateBankStatement struct {
+	Key           DuplicateKey
+	BankStatement BankStatement
+	Original      BankStatement
+}
+
+// duplicateIndex is an item at index repeating the item at original on key.
+type duplicateIndex struct {
+	index    int
+	original int
+	key      DuplicateKey
+}
+
+// findDuplicates compares n items on keys, where identity gives the value of
+// item i for a key, false when the key does not apply to it. Each duplicate
+// is reported once, against the first item with the same value, on the first
+// key they agree on.
+func findDuplicates(n int, keys []DuplicateKey, identity func(i int, key DuplicateKey) (string, bool)) []duplicateIndex {
+	var duplicates []duplicateIndex
+	seen := map[DuplicateKey]map[string]int{}
+	for _, key := range keys {
+		seen[key] = map[string]int{}
+	}
+items:
+	for i := 0; i < n; i++ {
+		values := map[DuplicateKey]string{}
+		for _, key := range keys {
+			value, ok := identity(i, key)
+			if !ok {
+				continue
+			}
+			if original, ok := seen[key][value]; ok {
+				duplicates = append(duplicates, duplicateIndex{index: i, original: original, key: key})
+				continue items
+			}
+			values[key] = value
+		}
+		// only items that are no duplicates are originals
+		for key, value := range values {
+			seen[key][value] = i
+		}
+	}
+	return duplicates
+}
+
+func amountTimeIdentity(amount float64, t time.Time) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64) + "|" + t.UTC().Format(time.RFC3339Nano)
+}
+
+func transactionIdentity(t Transaction, key DuplicateKey) (string, bool) {
+	switch key {
+	case DuplicateKeyID:
+		return t.ID, t.ID != ""
+	case DuplicateKeyAmountTime:
+		return amountTimeIdentity(t.Amount, t.Time), true
+	}
+	return "", false
+}
+
+func bankStatementIdentity(s BankStatement, key DuplicateKey) (string, bool) {
+	account := s.BankAccount().String() + "|"
+	switch key {
+	case DuplicateKeyID:
+		return account + s.ID, s.ID != ""
+	case DuplicateKeyAmountTime:
+		return account + amountTimeIdentity(s.Amount, s.Time), true
+	case DuplicateKeyReference:
+		return account + s.Reference, s.Reference != ""
+	}
+	return "", false
+}
+
+// duplicates are the duplicates detected in a run.
+type duplicates struct {
+	transactions []DuplicateTransaction
+	statements   []DuplicateBankStatement
+}
+
+// apply detects duplicates among transactions and statements and applies the
+// policy, returning the items left to reconcile.
+func (d DuplicateConfig) apply(transactions []Transaction, statements []BankStatement) (duplicates, []Transaction, []BankStatement, error) {
+	var found duplicates
+	if len(d.Keys) == 0 {
+		return found, transactions, statements, nil
+	}
+
+	dropTransactions := map[int]bool{}
+	for _, dup := range findDuplicates(len(transactions), d.Keys, func(i int, key DuplicateKey) (string, bool) {
+		return transactionIdentity(transactions[i], key)
+	}) {
+		found.transactions = append(found.transactions, DuplicateTransaction{Key: dup.key, Transaction: transactions[dup.index], Original: transactions[dup.original]})
+		dropTransactions[dup.index] = true
+	}
+	dropStatements := map[int]bool{}
+	for _, dup := range findDuplicates(len(statements), d.Keys, func(i int, key DuplicateKey) (string, bool) {
+		return bankStatementIdentity(statements[i], key)
+	}) {
+		found.statements = append(found.statements, DuplicateBankStatement{Key: dup.key, BankStatement: statements[dup.index], Original: statements[dup.original]})
+		dropStatements[dup.index] = true
+	}
+
+	switch d.policy() {
+	case DuplicateReject:
+		if len(found.transactions) > 0 {
+			dup := found.transactions[0]
+			return found, nil, nil, fmt.Errorf("%d duplicate transactions, first %s repeating %s on %s", len(found.transactions), dup.Transaction.ID, dup.Original.ID, dup.Key)
+		}
+		if len(found.statements) > 0 {
+			dup := found.statements[0]
+			return found, nil, nil, fmt.Errorf("%d duplicate bank statements, first %s %s repeating %s on %s", len(found.statements), dup.BankStatement.BankAccount(), dup.BankStatement.ID, dup.Original.ID, dup.Key)
+		}
+	case DuplicateKeepFirst:
+		var keptTransactions []Transaction
+		for i, t := range transactions {
+			if !dropTransactions[i] {
+				keptTransactions = append(keptTransactions, t)
+			}
+		}
+		var keptStatements []BankStatement
+		for i, s := range statements {
+			if !dropStatements[i] {
+				keptStatements = append(keptStatements, s)
+			}
+		}
+		return found, keptTransactions, keptStatements, nil
+	}
+	return found, transactions, statements, nil
+}
+
+// DuplicateAction describes what the policy did to the later copies.
+func (d DuplicateConfig) DuplicateAction() string {
+	if d.policy() == DuplicateKeepFirst {
+		return "dropped"
+	}
+	return "flagged"
+}
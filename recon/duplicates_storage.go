@@ -0,0 +1,68 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DuplicatesStorage lists the items repeating an earlier item of their side,
+// next to the item they repeat.
+type DuplicatesStorage struct {
+	destinationFileNamePath string
+	destinationSheetName    string
+	excelWriterFactory      ExcelWriterFactory
+}
+
+func NewDuplicatesStorage(destinationFileNamePath string, destinationSheetName string, excelWriterFactory ExcelWriterFactory) DuplicatesStorage {
+	return DuplicatesStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		destinationSheetName:    destinationSheetName,
+		excelWriterFactory:      excelWriterFactory,
+	}
+}
+
+func (d DuplicatesStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := d.excelWriterFactory.New(d.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	index, err := f.GetSheetIndex(d.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet index: %w", err)
+	}
+
+	if index == -1 {
+		_, err = f.NewSheet(d.destinationSheetName)
+		if err != nil {
+			return fmt.Errorf("failed to create sheet: %w", err)
+		}
+	}
+
+	action := result.Options.Duplicates.DuplicateAction()
+	rows := [][]any{{"Side", "Key", "ID", "Amount", "Time", "Original ID", "Original Time", "Action"}}
+	for _, dup := range result.DuplicateTransactions {
+		t, original := dup.Transaction, dup.Original
+		rows = append(rows, []any{agingSideTransactions, string(dup.Key), t.ID, t.Amount, t.Time.Format(time.RFC3339), original.ID, original.Time.Format(time.RFC3339), action})
+	}
+	for _, dup := range result.DuplicateBankStatements {
+		s, original := dup.BankStatement, dup.Original
+		rows = append(rows, []any{s.BankAccount().String(), string(dup.Key), s.ID, s.Amount, s.Time.Format(time.RFC3339), original.ID, original.Time.Format(time.RFC3339), action})
+	}
+
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			f.SetCellValue(d.destinationSheetName, cell, v)
+		}
+	}
+
+	err = f.SaveAs(d.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("save as error: %w", err)
+	}
+	return nil
+}
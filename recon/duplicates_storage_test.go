@@ -0,0 +1,93 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDuplicatesStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.xlsx"
+	destinationSheetName := "Duplicates"
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	result := Result{
+		Options: Options{Duplicates: DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateKeepFirst}},
+		DuplicateTransactions: []DuplicateTransaction{{
+			Key:         DuplicateKeyID,
+			Transaction: Transaction{ID: "1", Amount: 100, Time: day.Add(time.Hour)},
+			Original:    Transaction{ID: "1", Amount: 100, Time: day},
+		}},
+		DuplicateBankStatements: []DuplicateBankStatement{{
+			Key:           DuplicateKeyReference,
+			BankStatement: BankStatement{Bank: "bca", Account: "111", ID: "b", Amount: 50, Time: day, Reference: "R1"},
+			Original:      BankStatement{Bank: "bca", Account: "111", ID: "a", Amount: 50, Time: day, Reference: "R1"},
+		}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		duplicatesStorage := NewDuplicatesStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		cells := map[string]any{
+			"A1": "Side", "B1": "Key", "C1": "ID", "D1": "Amount", "E1": "Time", "F1": "Original ID", "G1": "Original Time", "H1": "Action",
+			"A2": "transactions", "B2": "id", "C2": "1", "D2": 100.0, "E2": "2025-08-01T01:00:00Z", "F2": "1", "G2": "2025-08-01T00:00:00Z", "H2": "dropped",
+			"A3": "bca 111", "B3": "reference", "C3": "b", "D3": 50.0, "E3": "2025-08-01T00:00:00Z", "F3": "a", "G3": "2025-08-01T00:00:00Z", "H3": "dropped",
+		}
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(4, nil)
+		for cell, v := range cells {
+			mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := duplicatesStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		duplicatesStorage := NewDuplicatesStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, errors.New("open file error"))
+
+		err := duplicatesStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("save as error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		duplicatesStorage := NewDuplicatesStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
+
+		err := duplicatesStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
@@ -0,0 +1,101 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDuplicateConfig_Apply(t *testing.T) {
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+	transactions := []Transaction{
+		{ID: "1", Amount: 100, Time: day},
+		{ID: "2", Amount: 50, Time: day},
+		{ID: "1", Amount: 100, Time: day},
+		{ID: "3", Amount: 50, Time: day},
+	}
+	statements := []BankStatement{
+		{Bank: "bca", ID: "a", Amount: 100, Time: day, Reference: "R1"},
+		{Bank: "bca", ID: "b", Amount: 100, Time: day.Add(time.Hour), Reference: "R1"},
+		{Bank: "bca", Account: "222", ID: "a", Amount: 100, Time: day, Reference: "R1"},
+		{Bank: "bri", ID: "c", Amount: 70, Time: day},
+		{Bank: "bri", ID: "d", Amount: 80, Time: day},
+	}
+
+	t.Run("detects nothing without keys", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		found, keptTransactions, keptStatements, err := DuplicateConfig{}.apply(transactions, statements)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(found).Should(Equal(duplicates{}))
+		g.Expect(keptTransactions).Should(Equal(transactions))
+		g.Expect(keptStatements).Should(Equal(statements))
+	})
+
+	t.Run("flags duplicates on any key within the account", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		config := DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID, DuplicateKeyAmountTime, DuplicateKeyReference}}
+		found, keptTransactions, keptStatements, err := config.apply(transactions, statements)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(found.transactions).Should(Equal([]DuplicateTransaction{
+			{Key: DuplicateKeyID, Transaction: transactions[2], Original: transactions[0]},
+			{Key: DuplicateKeyAmountTime, Transaction: transactions[3], Original: transactions[1]},
+		}))
+		g.Expect(found.statements).Should(Equal([]DuplicateBankStatement{
+			{Key: DuplicateKeyReference, BankStatement: statements[1], Original: statements[0]},
+		}))
+		g.Expect(keptTransactions).Should(Equal(transactions))
+		g.Expect(keptStatements).Should(Equal(statements))
+	})
+
+	t.Run("keeps the first copy", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		config := DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateKeepFirst}
+		found, keptTransactions, keptStatements, err := config.apply(transactions, statements)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(found.transactions).Should(HaveLen(1))
+		g.Expect(keptTransactions).Should(Equal([]Transaction{transactions[0], transactions[1], transactions[3]}))
+		g.Expect(keptStatements).Should(Equal(statements))
+		g.Expect(config.DuplicateAction()).Should(Equal("dropped"))
+	})
+
+	t.Run("rejects duplicates", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		config := DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyReference}, Policy: DuplicateReject}
+		_, _, _, err := config.apply(transactions, statements)
+
+		g.Expect(err).Should(MatchError("1 duplicate bank statements, first bca b repeating a on reference"))
+	})
+
+	t.Run("compares only with items that are no duplicates", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		chain := []Transaction{
+			{ID: "1", Amount: 10, Time: day},
+			{ID: "1", Amount: 20, Time: day},
+			{ID: "2", Amount: 20, Time: day},
+		}
+		config := DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID, DuplicateKeyAmountTime}}
+		found, _, _, err := config.apply(chain, nil)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(found.transactions).Should(Equal([]DuplicateTransaction{
+			{Key: DuplicateKeyID, Transaction: chain[1], Original: chain[0]},
+		}))
+	})
+}
+
+func TestDuplicateConfig_Validate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateReject}.Validate()).Should(Succeed())
+	g.Expect(DuplicateConfig{Keys: []DuplicateKey{"name"}}.Validate()).ShouldNot(Succeed())
+	g.Expect(DuplicateConfig{Policy: "drop"}.Validate()).ShouldNot(Succeed())
+}
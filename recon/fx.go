@@ -0,0 +1,156 @@
+package recon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FXRate is the value of one unit of Base in Quote on a day, e.g. the pair
+// USD/IDR at 16250.
+type FXRate struct {
+	Date  time.Time
+	Base  string
+	Quote string
+	Rate  float64
+}
+
+// FXRates is a table of daily exchange rates. A conversion uses the latest
+// rate dated on or before the day of the amount, of the pair or its inverse.
+type FXRates struct {
+	rates map[[2]string][]FXRate
+}
+
+func NewFXRates(rates []FXRate) FXRates {
+	table := FXRates{rates: map[[2]string][]FXRate{}}
+	for _, rate := range rates {
+		pair := [2]string{rate.Base, rate.Quote}
+		table.rates[pair] = append(table.rates[pair], rate)
+	}
+	for _, pairRates := range table.rates {
+		sort.SliceStable(pairRates, func(i, j int) bool {
+			return pairRates[i].Date.Before(pairRates[j].Date)
+		})
+	}
+	return table
+}
+
+// Convert converts amount from one currency to another at time at.
+func (f FXRates) Convert(amount float64, from, to string, at time.Time) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	if rate, ok := f.latest(from, to, at); ok {
+		return amount * rate.Rate, nil
+	}
+	if rate, ok := f.latest(to, from, at); ok && rate.Rate != 0 {
+		return amount / rate.Rate, nil
+	}
+	return 0, fmt.Errorf("no %s/%s rate on or before %s", from, to, at.Format(time.DateOnly))
+}
+
+func (f FXRates) latest(base, quote string, at time.Time) (FXRate, bool) {
+	pairRates := f.rates[[2]string{base, quote}]
+	day := calendarDay(at)
+	i := sort.Search(len(pairRates), func(i int) bool {
+		return calendarDay(pairRates[i].Date).After(day)
+	})
+	if i == 0 {
+		return FXRate{}, false
+	}
+	return pairRates[i-1], true
+}
+
+// calendarDay is the date of t, whatever its time zone, so that a rate dated
+// 2024-01-02 applies to amounts of 2024-01-02 in any zone.
+func calendarDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// currencyConverter converts item amounts to the reporting currency. Items
+// without a currency are taken to be in the reporting currency.
+type currencyConverter struct {
+	rates     FXRates
+	reporting string
+}
+
+func (c currencyConverter) currency(currency string) string {
+	if currency == "" {
+		return c.reporting
+	}
+	return currency
+}
+
+// toReporting converts amount to the reporting currency. Without a
+// reporting currency amounts are taken as they are.
+func (c currencyConverter) toReporting(amount float64, currency string, at time.Time) (float64, error) {
+	if c.reporting == "" {
+		return amount, nil
+	}
+	return c.rates.Convert(amount, c.currency(currency), c.reporting, at)
+}
+
+// convert converts amount between two item currencies.
+func (c currencyConverter) convert(amount float64, from, to string, at time.Time) (float64, error) {
+	return c.rates.Convert(amount, c.currency(from), c.currency(to), at)
+}
+
+// fxDifference is the part of the difference between a matched transaction
+// and bank statement, in the reporting currency, that comes from exchange
+// rates: all of it across currencies, and the revaluation between the two
+// dates within a foreign currency.
+func (c currencyConverter) fxDifference(t Transaction, s BankStatement) (float64, error) {
+	if c.reporting == "" {
+		return 0, nil
+	}
+	transactionAmount, err := c.toReporting(t.Amount, t.Currency, t.Time)
+	if err != nil {
+		return 0, err
+	}
+	statementAmount, err := c.toReporting(s.Amount, s.Currency, s.Time)
+	if err != nil {
+		return 0, err
+	}
+
+	difference := transactionAmount - statementAmount
+	if c.currency(t.Currency) == c.currency(s.Currency) {
+		amountDifference, err := c.toReporting(t.Amount-s.Amount, t.Currency, t.Time)
+		if err != nil {
+			return 0, err
+		}
+		difference -= amountDifference
+	}
+	if amountEqual(difference, 0) {
+		return 0, nil
+	}
+	return difference, nil
+}
+
+// checkCurrencies refuses to add up amounts of different currencies when
+// there is no reporting currency to convert them to.
+func (c currencyConverter) checkCurrencies(transactions []Transaction, statements []BankStatement) error {
+	if c.reporting != "" {
+		return nil
+	}
+	currencies := map[string]bool{}
+	for _, t := range transactions {
+		currencies[t.Currency] = true
+	}
+	for _, s := range statements {
+		currencies[s.Currency] = true
+	}
+	if len(currencies) > 1 {
+		var names []string
+		for currency := range currencies {
+			if currency == "" {
+				currency = "unspecified"
+			}
+			names = append(names, currency)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("mixed currencies %s need a reporting currency", strings.Join(names, ", "))
+	}
+	return nil
+}
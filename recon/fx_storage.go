@@ -0,0 +1,69 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FXRateStorage reads exchange rates from a file with the columns date
+// (YYYY-MM-DD), pair (e.g. USD/IDR) and rate.
+type FXRateStorage struct {
+	readerFactory ReaderFactory
+}
+
+func NewFXRateStorage(readerFactory ReaderFactory) FXRateStorage {
+	return FXRateStorage{readerFactory: readerFactory}
+}
+
+// GetRates reads the rates of filename. The LoadReport has the checksum of
+// the file so runs can tell which rates they used.
+func (f FXRateStorage) GetRates(ctx context.Context, filename string) ([]FXRate, LoadReport, error) {
+	report := LoadReport{Path: filename}
+
+	reader, err := f.readerFactory.NewReader(ctx, filename)
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer reader.Close()
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to read file: %w", err)
+	}
+	report.SHA256 = reader.Checksum()
+	report.RowsRead = max(len(records)-1, 0)
+
+	var rates []FXRate
+	for i, row := range records {
+		if i == 0 { // skip header
+			continue
+		}
+		if len(row) < 3 {
+			return nil, report, fmt.Errorf("missing columns in row: %v", row)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(row[0]))
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid date in row: %v", row)
+		}
+		base, quote, ok := strings.Cut(strings.TrimSpace(row[1]), "/")
+		if !ok || base == "" || quote == "" {
+			return nil, report, fmt.Errorf("invalid pair in row: %v", row)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil || rate <= 0 {
+			return nil, report, fmt.Errorf("invalid rate in row: %v", row)
+		}
+
+		rates = append(rates, FXRate{
+			Date:  date,
+			Base:  strings.ToUpper(base),
+			Quote: strings.ToUpper(quote),
+			Rate:  rate,
+		})
+	}
+	return rates, report, nil
+}
@@ -0,0 +1,84 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestFXRateStorage_GetRates(t *testing.T) {
+	t.Run("should read rates", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+		storage := NewFXRateStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "rates.csv").Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"date", "pair", "rate"},
+			{"2024-01-01", "usd/idr", "15500"},
+			{"2024-01-02", "SGD/IDR", " 11600.5 "},
+		}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		rates, report, err := storage.GetRates(context.Background(), "rates.csv")
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(report).Should(Equal(LoadReport{Path: "rates.csv", SHA256: "checksum", RowsRead: 2}))
+		g.Expect(rates).Should(Equal([]FXRate{
+			{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "IDR", Rate: 15500},
+			{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Base: "SGD", Quote: "IDR", Rate: 11600.5},
+		}))
+	})
+
+	t.Run("should return error when a row is invalid", func(t *testing.T) {
+		for name, row := range map[string][]string{
+			"missing columns": {"2024-01-01", "USD/IDR"},
+			"invalid date":    {"01/01/2024", "USD/IDR", "15500"},
+			"invalid pair":    {"2024-01-01", "USDIDR", "15500"},
+			"invalid rate":    {"2024-01-01", "USD/IDR", "0"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				g := NewGomegaWithT(t)
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				mockReaderFactory := NewMockReaderFactory(ctrl)
+				mockReader := NewMockReader(ctrl)
+				storage := NewFXRateStorage(mockReaderFactory)
+
+				mockReaderFactory.EXPECT().NewReader(gomock.Any(), "rates.csv").Return(mockReader, nil)
+				mockReader.EXPECT().ReadAll().Return([][]string{{"date", "pair", "rate"}, row}, nil)
+				mockReader.EXPECT().Checksum().Return("checksum")
+				mockReader.EXPECT().Close().Return(nil)
+
+				_, _, err := storage.GetRates(context.Background(), "rates.csv")
+
+				g.Expect(err).ShouldNot(BeNil())
+			})
+		}
+	})
+
+	t.Run("should return error when file cannot be opened", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		storage := NewFXRateStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "rates.csv").Return(nil, fmt.Errorf("not found"))
+
+		_, _, err := storage.GetRates(context.Background(), "rates.csv")
+
+		g.Expect(err).Should(MatchError("failed to open file: not found"))
+	})
+}
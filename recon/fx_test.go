@@ -0,0 +1,149 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFXRates_Convert(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	rates := NewFXRates([]FXRate{
+		{Date: day(3), Base: "USD", Quote: "IDR", Rate: 16000},
+		{Date: day(1), Base: "USD", Quote: "IDR", Rate: 15500},
+	})
+
+	t.Run("uses the latest rate on or before the day", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		amount, err := rates.Convert(2, "USD", "IDR", day(2).Add(20*time.Hour))
+		g.Expect(err).Should(BeNil())
+		g.Expect(amount).Should(Equal(31000.0))
+
+		amount, err = rates.Convert(2, "USD", "IDR", day(3))
+		g.Expect(err).Should(BeNil())
+		g.Expect(amount).Should(Equal(32000.0))
+	})
+
+	t.Run("uses the day of the amount in its own time zone", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		amount, err := rates.Convert(1, "USD", "IDR", time.Date(2024, 1, 3, 1, 0, 0, 0, jakarta))
+		g.Expect(err).Should(BeNil())
+		g.Expect(amount).Should(Equal(16000.0))
+	})
+
+	t.Run("uses the inverse pair", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		amount, err := rates.Convert(32000, "IDR", "USD", day(4))
+		g.Expect(err).Should(BeNil())
+		g.Expect(amount).Should(Equal(2.0))
+	})
+
+	t.Run("same currency needs no rate", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		amount, err := NewFXRates(nil).Convert(5, "EUR", "EUR", day(1))
+		g.Expect(err).Should(BeNil())
+		g.Expect(amount).Should(Equal(5.0))
+	})
+
+	t.Run("no rate before the day", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		_, err := rates.Convert(1, "USD", "IDR", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))
+		g.Expect(err).ShouldNot(BeNil())
+
+		_, err = rates.Convert(1, "EUR", "IDR", day(3))
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+func TestCurrencyConverter_FXDifference(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	converter := currencyConverter{
+		rates: NewFXRates([]FXRate{
+			{Date: day(1), Base: "USD", Quote: "IDR", Rate: 15500},
+			{Date: day(2), Base: "USD", Quote: "IDR", Rate: 15600},
+		}),
+		reporting: "IDR",
+	}
+
+	t.Run("across currencies", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		difference, err := converter.fxDifference(
+			Transaction{Amount: 10, Currency: "USD", Time: day(1)},
+			BankStatement{Amount: 154000, Currency: "IDR", Time: day(1)},
+		)
+		g.Expect(err).Should(BeNil())
+		g.Expect(difference).Should(Equal(1000.0))
+	})
+
+	t.Run("revaluation within a foreign currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		difference, err := converter.fxDifference(
+			Transaction{Amount: 10, Currency: "USD", Time: day(1)},
+			BankStatement{Amount: 10, Currency: "USD", Time: day(2)},
+		)
+		g.Expect(err).Should(BeNil())
+		g.Expect(difference).Should(Equal(-1000.0))
+	})
+
+	t.Run("none in the reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		difference, err := converter.fxDifference(
+			Transaction{Amount: 100, Time: day(1)},
+			BankStatement{Amount: 99, Currency: "IDR", Time: day(2)},
+		)
+		g.Expect(err).Should(BeNil())
+		g.Expect(difference).Should(Equal(0.0))
+	})
+
+	t.Run("missing rate", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		_, err := converter.fxDifference(
+			Transaction{Amount: 10, Currency: "EUR", Time: day(1)},
+			BankStatement{Amount: 170000, Currency: "IDR", Time: day(1)},
+		)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+func TestCurrencyConverter_CheckCurrencies(t *testing.T) {
+	t.Run("single currency without reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		err := currencyConverter{}.checkCurrencies(
+			[]Transaction{{Currency: "IDR"}},
+			[]BankStatement{{Currency: "IDR"}},
+		)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("mixed currencies without reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		err := currencyConverter{}.checkCurrencies(
+			[]Transaction{{Currency: "USD"}},
+			[]BankStatement{{}},
+		)
+		g.Expect(err).Should(MatchError("mixed currencies USD, unspecified need a reporting currency"))
+	})
+
+	t.Run("mixed currencies with reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		err := currencyConverter{reporting: "IDR"}.checkCurrencies(
+			[]Transaction{{Currency: "USD"}},
+			[]BankStatement{{Currency: "IDR"}},
+		)
+		g.Expect(err).Should(BeNil())
+	})
+}
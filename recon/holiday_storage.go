@@ -0,0 +1,60 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HolidayStorage reads holidays from a file with the columns date
+// (YYYY-MM-DD) and name, and optionally bank for holidays of one bank only.
+// Holidays are usually kept in one file per year.
+type HolidayStorage struct {
+	readerFactory ReaderFactory
+}
+
+func NewHolidayStorage(readerFactory ReaderFactory) HolidayStorage {
+	return HolidayStorage{readerFactory: readerFactory}
+}
+
+// GetHolidays reads the holidays of filename. The LoadReport has the checksum
+// of the file so runs can tell which holidays they used.
+func (h HolidayStorage) GetHolidays(ctx context.Context, filename string) ([]Holiday, LoadReport, error) {
+	report := LoadReport{Path: filename}
+
+	reader, err := h.readerFactory.NewReader(ctx, filename)
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer reader.Close()
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to read file: %w", err)
+	}
+	report.SHA256 = reader.Checksum()
+	report.RowsRead = max(len(records)-1, 0)
+
+	var holidays []Holiday
+	for i, row := range records {
+		if i == 0 { // skip header
+			continue
+		}
+		if len(row) < 2 {
+			return nil, report, fmt.Errorf("missing columns in row: %v", row)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(row[0]))
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid date in row: %v", row)
+		}
+
+		holiday := Holiday{Date: date, Name: strings.TrimSpace(row[1])}
+		if len(row) > 2 {
+			holiday.Bank = strings.TrimSpace(row[2])
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, report, nil
+}
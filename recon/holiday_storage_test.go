@@ -0,0 +1,75 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestHolidayStorage_GetHolidays(t *testing.T) {
+	t.Run("should read holidays", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+		storage := NewHolidayStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "holidays-2025.csv").Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"date", "name", "bank"},
+			{"2025-03-31", "Idul Fitri"},
+			{"2025-04-02", " Bank holiday ", "bca"},
+		}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		holidays, report, err := storage.GetHolidays(context.Background(), "holidays-2025.csv")
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(report).Should(Equal(LoadReport{Path: "holidays-2025.csv", SHA256: "checksum", RowsRead: 2}))
+		g.Expect(holidays).Should(Equal([]Holiday{
+			{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Name: "Idul Fitri"},
+			{Date: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC), Name: "Bank holiday", Bank: "bca"},
+		}))
+	})
+
+	t.Run("should return error when date is invalid", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+		storage := NewHolidayStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "holidays.csv").Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{{"date", "name"}, {"31/03/2025", "Idul Fitri"}}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		_, _, err := storage.GetHolidays(context.Background(), "holidays.csv")
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when file cannot be opened", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		storage := NewHolidayStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "holidays.csv").Return(nil, fmt.Errorf("not found"))
+
+		_, _, err := storage.GetHolidays(context.Background(), "holidays.csv")
+
+		g.Expect(err).Should(MatchError("failed to open file: not found"))
+	})
+}
@@ -0,0 +1,67 @@
+package recon
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+//go:embed html_report.tmpl
+var htmlReportTemplateText string
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"amount":   func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"datetime": func(t time.Time) string { return t.Format(time.RFC3339) },
+	"join":     func(row []string) string { return strings.Join(row, ",") },
+	"refs":     joinStatementRefs,
+}).Parse(htmlReportTemplateText))
+
+type htmlReportView struct {
+	Result
+	StartDate string
+	EndDate   string
+	MatchRate string
+}
+
+// HTMLReportStorage renders a recon result as a single self-contained HTML
+// file with inline styles and scripts, so it can be emailed or served as is.
+type HTMLReportStorage struct {
+	destinationFileNamePath string
+	fileWriterFactory       FileWriterFactory
+}
+
+func NewHTMLReportStorage(destinationFileNamePath string, fileWriterFactory FileWriterFactory) HTMLReportStorage {
+	return HTMLReportStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		fileWriterFactory:       fileWriterFactory,
+	}
+}
+
+func (h HTMLReportStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := h.fileWriterFactory.Create(h.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	view := htmlReportView{
+		Result:    result,
+		StartDate: result.StartDate.Format(time.DateOnly),
+		EndDate:   result.EndDate.Format(time.DateOnly),
+		MatchRate: fmt.Sprintf("%.2f%%", result.MatchRate()*100),
+	}
+
+	err = htmlReportTemplate.Execute(f, view)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
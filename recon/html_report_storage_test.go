@@ -0,0 +1,179 @@
+package recon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+type bufferWriteCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferWriteCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+type failingWriteCloser struct{}
+
+func (failingWriteCloser) Write([]byte) (int, error) { return 0, errors.New("write error") }
+func (failingWriteCloser) Close() error              { return nil }
+
+func TestHTMLReportStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.html"
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	result := Result{
+		StartDate: day,
+		EndDate:   day.AddDate(0, 0, 1),
+		Summary: Summary{
+			TotalTransactions:             2,
+			TotalAmountTransactions:       300,
+			MatchedTransactions:           1,
+			MatchedAmountTransactions:     100,
+			UnmatchedTransactions:         1,
+			UnmatchedAmountTransactions:   200,
+			TotalBankStatements:           2,
+			TotalAmountBankStatements:     400,
+			MatchedBankStatements:         1,
+			MatchedAmountBankStatements:   100,
+			UnmatchedBankStatements:       1,
+			UnmatchedAmountBankStatements: 300,
+			ReportingCurrency:             "IDR",
+			BalanceChecks: []BalanceCheck{
+				{Bank: "bri", Opening: 0, Lines: 300, Closing: 320, Gaps: []BalanceGap{{Line: 3, ID: "bri-2", Expected: 300, Actual: 320}}},
+			},
+		},
+		Matches: []Match{
+			{Transaction: Transaction{ID: "trx-1", Amount: 100, Type: Credit, Time: day}, BankStatement: BankStatement{Bank: "bca", ID: "bca-1", Amount: 100, Time: day}},
+			{Transaction: Transaction{ID: "trx-3", Amount: 150000, Currency: "IDR", Type: Credit, Time: day}, BankStatement: BankStatement{Bank: "bca", ID: "bca-3", Amount: 10, Currency: "USD", Time: day}, FXDifference: 0},
+		},
+		UnmatchedTransactions: []Transaction{{ID: "trx-2", Amount: 200, Type: Debit, Time: day}},
+		UnmatchedBankStatements: []BankStatementDiscrepancy{
+			{Bank: "bri", Statements: []BankStatement{{Bank: "bri", ID: "bri-<1>", Amount: 300, Time: day}, {Bank: "bri", ID: "bri-4", Amount: 20, Currency: "USD", Time: day}}},
+		},
+		TransactionInput: LoadReport{Path: "transaction.csv"},
+		BankStatementInputs: []LoadReport{
+			{Path: "bri.csv", RejectedRows: []RejectedRow{{Path: "bri.csv", Line: 4, Row: []string{"x"}, Reason: "missing columns"}}},
+		},
+		Exclusions: []AppliedOverride{{
+			Override:     Override{Action: OverrideExclude, TransactionIDs: []string{"trx-9"}, Reason: "test order", Author: "ops"},
+			Transactions: []Transaction{{ID: "trx-9", Amount: 10, Type: Credit, Time: day}},
+		}},
+		DuplicateTransactions: []DuplicateTransaction{{
+			Key:         DuplicateKeyID,
+			Transaction: Transaction{ID: "trx-1", Amount: 100, Time: day},
+			Original:    Transaction{ID: "trx-1", Amount: 100, Time: day},
+		}},
+		BankStatementReversals: []BankStatementReversal{{
+			Original: BankStatement{Bank: "bca", ID: "bca-5", Amount: 40, Time: day},
+			Reversal: BankStatement{Bank: "bca", ID: "bca-6", Amount: -40, Time: day, Reference: "bca-5"},
+		}},
+		UnappliedOverrides: []Override{
+			{Action: OverrideMatch, TransactionIDs: []string{"trx-8"}, Statements: []StatementRef{{Bank: "bca", ID: "bca-8"}}, Reason: "late posting", Author: "ops"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewHTMLReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		file := &bufferWriteCloser{}
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(file, nil)
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(file.closed).Should(BeTrue())
+
+		html := file.String()
+		g.Expect(html).Should(ContainSubstring("Period 2025-08-01 to 2025-08-02"))
+		g.Expect(html).Should(ContainSubstring(`<td>Unmatched Bank Statements</td><td class="num">1</td><td class="num">300.00</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>Balanced</td><td colspan="2"><span class="balanced">yes</span></td>`))
+		g.Expect(html).Should(ContainSubstring("50.00%"))
+		g.Expect(html).Should(ContainSubstring("<td>trx-2</td>"))
+		g.Expect(html).Should(ContainSubstring("Unmatched Bank Statements: bri"))
+		g.Expect(html).Should(ContainSubstring(`<td>bri-&lt;1&gt;</td><td class="num">300.00</td><td>IDR</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>bri-4</td><td class="num">20.00</td><td>USD</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>trx-2</td><td class="num">200.00</td><td>IDR</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>trx-3</td><td class="num">150000.00</td><td>IDR</td><td>credit</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>bca-3</td><td class="num">10.00</td><td>USD</td>`))
+		g.Expect(html).Should(ContainSubstring("<td>bca-1</td>"))
+		g.Expect(html).Should(ContainSubstring("<td>missing columns</td>"))
+		g.Expect(html).Should(ContainSubstring(`<td>Balance Breaks</td><td class="num">1</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>FX Difference</td><td></td><td class="num">0.00</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>Reporting Currency</td><td colspan="2">IDR</td>`))
+		g.Expect(html).Should(ContainSubstring(`<tr><td>transactions</td><td>id</td><td>trx-1</td><td class="num">100.00</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>flagged</td></tr>`))
+		g.Expect(html).Should(ContainSubstring(`<tr><td>bca</td><td>bca-5</td><td class="num">40.00</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>bca-6</td><td class="num">-40.00</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>bri</td><td class="num">0.00</td><td class="num">300.00</td><td class="num">320.00</td><td class="num">20.00</td><td>running balance</td><td><span class="unbalanced">break</span></td>`))
+		g.Expect(html).Should(ContainSubstring("Running balance gap at line 3 (ID bri-2): expected 300.00, stated 320.00, missing 20.00"))
+		g.Expect(html).Should(ContainSubstring(`<td>exclude</td><td>applied</td><td>trx-9</td><td></td><td class="num">10.00</td><td>test order</td><td>ops</td>`))
+		g.Expect(html).Should(ContainSubstring(`<td>match</td><td>not applied</td><td>trx-8</td><td>bca:bca-8</td>`))
+		// self-contained: no external stylesheets or scripts
+		g.Expect(html).ShouldNot(ContainSubstring("<link"))
+		g.Expect(html).ShouldNot(ContainSubstring("src="))
+	})
+
+	t.Run("shows unaccounted items as warnings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewHTMLReportStorage(destinationFileNamePath, mockFileWriterFactory)
+		warned := result
+		warned.Summary.Unaccounted = []string{"transactions in IDR: 3 loaded (300.00), 2 accounted for (200.00)"}
+
+		file := &bufferWriteCloser{}
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(file, nil)
+
+		err := storage.StoreReport(context.Background(), warned)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(file.String()).Should(ContainSubstring(`<td>Warning</td><td colspan="2" class="unbalanced">transactions in IDR: 3 loaded (300.00), 2 accounted for (200.00)</td>`))
+		g.Expect(file.String()).Should(ContainSubstring(`<span class="unbalanced">no</span>`))
+	})
+
+	t.Run("create file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewHTMLReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(nil, errors.New("create error"))
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewHTMLReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(failingWriteCloser{}, nil)
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
@@ -0,0 +1,566 @@
+package recon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	jobInputDir       = "input"
+	jobStateName      = "job.json"
+	jobWorkbookName   = "recon.xlsx"
+	jobJSONReportName = "recon.json"
+
+	// jobQueueSize bounds the number of jobs waiting for a worker.
+	jobQueueSize = 1024
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobFinished     = errors.New("job already finished")
+	ErrJobActive       = errors.New("job is queued or running")
+	ErrQueueFull       = errors.New("job queue is full")
+	ErrJobNotSucceeded = errors.New("job did not succeed")
+)
+
+// JobStatus is the state of a recon job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCanceled  JobStatus = "canceled"
+)
+
+// Finished reports whether the job will not run anymore.
+func (s JobStatus) Finished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// Job is a recon run managed by a JobManager.
+type Job struct {
+	ID         string     `json:"id"`
+	Status     JobStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartDate  string     `json:"start_date"`
+	EndDate    string     `json:"end_date"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobSpec is what a job runs: the input files, named relative to the input
+// directory of the job, the period and the options.
+type JobSpec struct {
+	StartDate          string   `json:"start_date"`
+	EndDate            string   `json:"end_date"`
+	Timezone           string   `json:"timezone,omitempty"`
+	TransactionFile    string   `json:"transaction_file"`
+	BankStatementFiles []string `json:"bank_statement_files"`
+	// FXRatesFile converts amounts into the reporting currency, none when
+	// empty.
+	FXRatesFile string `json:"fx_rates_file,omitempty"`
+	// HolidayFiles, like BusinessDays, count days in business days,
+	// skipping weekends and the holidays.
+	HolidayFiles []string `json:"holiday_files,omitempty"`
+	BusinessDays bool     `json:"business_days,omitempty"`
+	Options      Options  `json:"options"`
+}
+
+// Period parses the start and end dates as days in the time zone of the spec,
+// UTC when empty.
+func (s JobSpec) Period() (time.Time, time.Time, error) {
+	location := time.UTC
+	if s.Timezone != "" {
+		loaded, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid timezone: %w", err)
+		}
+		location = loaded
+	}
+	startDate, err := time.ParseInLocation(time.DateOnly, s.StartDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	endDate, err := time.ParseInLocation(time.DateOnly, s.EndDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+	}
+	return startDate, endDate, nil
+}
+
+// jobRecord is the state of a job as persisted in its directory.
+type jobRecord struct {
+	Job  Job     `json:"job"`
+	Spec JobSpec `json:"spec"`
+}
+
+type managedJob struct {
+	jobRecord
+	cancel context.CancelFunc
+}
+
+// jobRunner runs spec in the job directory dir.
+type jobRunner func(ctx context.Context, dir string, spec JobSpec) error
+
+// JobManager runs recon jobs on a bounded pool of workers. Every job has its
+// own directory below dir holding the input files, the reports and its state,
+// so that jobs survive a restart: finished jobs keep their outcome and jobs
+// still queued or running are queued again.
+type JobManager struct {
+	dir     string
+	run     jobRunner
+	logger  *log.Logger
+	now     func() time.Time
+	queue   chan string
+	stop    chan struct{}
+	stopped sync.Once
+	base    context.Context
+	abort   context.CancelFunc
+	workers sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*managedJob
+}
+
+// NewJobManager loads the jobs persisted below dir and starts workers to run
+// them and new ones, logging the job states it could not save to logger.
+func NewJobManager(dir string, workers int, logger *log.Logger) (*JobManager, error) {
+	return newJobManager(dir, workers, runReconJob, logger)
+}
+
+func newJobManager(dir string, workers int, run jobRunner, logger *log.Logger) (*JobManager, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("workers must be at least 1: %d", workers)
+	}
+	base, abort := context.WithCancel(context.Background())
+	m := &JobManager{
+		dir:    dir,
+		run:    run,
+		logger: logger,
+		now:    time.Now,
+		queue:  make(chan string, jobQueueSize),
+		stop:   make(chan struct{}),
+		base:   base,
+		abort:  abort,
+		jobs:   map[string]*managedJob{},
+	}
+
+	err := m.load()
+	if err != nil {
+		abort()
+		return nil, err
+	}
+	for range workers {
+		m.workers.Add(1)
+		go m.work()
+	}
+	return m, nil
+}
+
+// load reads the persisted jobs and queues again those that did not finish,
+// oldest first.
+func (m *JobManager) load() error {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return fmt.Errorf("read jobs directory error: %w", err)
+	}
+
+	var pending []*managedJob
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(m.dir, entry.Name(), jobStateName))
+		if errors.Is(err, os.ErrNotExist) {
+			// a job whose upload did not complete
+			os.RemoveAll(filepath.Join(m.dir, entry.Name()))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read job state error: %w", err)
+		}
+		var record jobRecord
+		err = json.Unmarshal(data, &record)
+		if err != nil {
+			return fmt.Errorf("invalid job state %s: %w", entry.Name(), err)
+		}
+
+		job := &managedJob{jobRecord: record}
+		m.jobs[record.Job.ID] = job
+		if !record.Job.Status.Finished() {
+			m.removeOutput(record.Job.ID)
+			job.Job.Status = JobQueued
+			job.Job.StartedAt = nil
+			err = m.persist(job)
+			if err != nil {
+				return err
+			}
+			pending = append(pending, job)
+		}
+	}
+
+	slices.SortFunc(pending, func(a, b *managedJob) int {
+		return a.Job.CreatedAt.Compare(b.Job.CreatedAt)
+	})
+	for _, job := range pending {
+		select {
+		case m.queue <- job.Job.ID:
+		default:
+			return ErrQueueFull
+		}
+	}
+	return nil
+}
+
+// Submit queues a job for spec. upload saves the input files named in spec
+// into dir, the input directory of the job; the job is dropped when it fails.
+func (m *JobManager) Submit(spec JobSpec, upload func(dir string) error) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	jobDir := filepath.Join(m.dir, id)
+	err = os.MkdirAll(filepath.Join(jobDir, jobInputDir), 0o755)
+	if err != nil {
+		return Job{}, fmt.Errorf("create job directory error: %w", err)
+	}
+	err = upload(filepath.Join(jobDir, jobInputDir))
+	if err != nil {
+		os.RemoveAll(jobDir)
+		return Job{}, err
+	}
+
+	job := &managedJob{jobRecord: jobRecord{
+		Job: Job{
+			ID:        id,
+			Status:    JobQueued,
+			StartDate: spec.StartDate,
+			EndDate:   spec.EndDate,
+			CreatedAt: m.now(),
+		},
+		Spec: spec,
+	}}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err = m.persist(job)
+	if err == nil {
+		select {
+		case m.queue <- id:
+		default:
+			err = ErrQueueFull
+		}
+	}
+	if err != nil {
+		os.RemoveAll(jobDir)
+		return Job{}, err
+	}
+	m.jobs[id] = job
+	return job.Job, nil
+}
+
+// Get returns the job with id.
+func (m *JobManager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return job.Job, nil
+}
+
+// List returns all jobs, oldest first.
+func (m *JobManager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job.Job)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return jobs
+}
+
+// Output returns the path of a report of a succeeded job, e.g. recon.xlsx.
+func (m *JobManager) Output(id, name string) (string, error) {
+	job, err := m.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if job.Status != JobSucceeded {
+		return "", fmt.Errorf("%w: job is %s", ErrJobNotSucceeded, job.Status)
+	}
+	return filepath.Join(m.dir, id, name), nil
+}
+
+// Cancel stops a queued or running job. A running job stops at its next
+// check of the context and its partial output is removed.
+func (m *JobManager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	switch job.Job.Status {
+	case JobQueued:
+		// the worker skips it when dequeued
+		m.finish(job, JobCanceled, context.Canceled)
+	case JobRunning:
+		job.cancel()
+	default:
+		return job.Job, ErrJobFinished
+	}
+	return job.Job, nil
+}
+
+// Delete removes a finished job and its directory.
+func (m *JobManager) Delete(id string) error {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if ok && !job.Job.Status.Finished() {
+		m.mu.Unlock()
+		return ErrJobActive
+	}
+	delete(m.jobs, id)
+	m.mu.Unlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+	err := os.RemoveAll(filepath.Join(m.dir, id))
+	if err != nil {
+		return fmt.Errorf("remove job directory error: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops taking jobs from the queue and waits for the running jobs.
+// When ctx is done first, running jobs are canceled and queued again for the
+// next start. Calling it again waits for the same workers.
+func (m *JobManager) Shutdown(ctx context.Context) error {
+	m.stopped.Do(func() { close(m.stop) })
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		m.abort()
+		return nil
+	case <-ctx.Done():
+		m.abort()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (m *JobManager) work() {
+	defer m.workers.Done()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case id := <-m.queue:
+			select {
+			case <-m.stop:
+				// stays queued for the next start
+				return
+			default:
+			}
+			m.runJob(id)
+		}
+	}
+}
+
+func (m *JobManager) runJob(id string) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if !ok || job.Job.Status != JobQueued {
+		m.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(m.base)
+	defer cancel()
+	startedAt := m.now()
+	job.cancel = cancel
+	job.Job.Status = JobRunning
+	job.Job.StartedAt = &startedAt
+	err := m.persist(job)
+	if err != nil {
+		job.cancel = nil
+		m.logger.Printf("Job %s: %v", id, err)
+		m.finish(job, JobFailed, err)
+		m.mu.Unlock()
+		return
+	}
+	spec := job.Spec
+	m.mu.Unlock()
+
+	err = m.run(ctx, filepath.Join(m.dir, id), spec)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.cancel = nil
+	switch {
+	case err == nil:
+		m.finish(job, JobSucceeded, nil)
+	case m.base.Err() != nil:
+		// shutting down, run again on the next start
+		m.removeOutput(id)
+		job.Job.Status = JobQueued
+		job.Job.StartedAt = nil
+		// still saved as running when this fails, so queued again all the same
+		if err := m.persist(job); err != nil {
+			m.logger.Printf("Job %s: %v", id, err)
+		}
+	case ctx.Err() != nil:
+		m.finish(job, JobCanceled, ctx.Err())
+	default:
+		m.finish(job, JobFailed, err)
+	}
+}
+
+// finish records the outcome of a job, removing the output of jobs that did
+// not succeed. A job whose outcome cannot be saved fails. The caller holds the
+// lock.
+func (m *JobManager) finish(job *managedJob, status JobStatus, err error) {
+	finishedAt := m.now()
+	job.Job.Status = status
+	job.Job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Job.Error = err.Error()
+		m.removeOutput(job.Job.ID)
+	}
+	err = m.persist(job)
+	if err != nil {
+		m.logger.Printf("Job %s: %v", job.Job.ID, err)
+		job.Job.Status = JobFailed
+		job.Job.Error = err.Error()
+		m.removeOutput(job.Job.ID)
+	}
+}
+
+func (m *JobManager) removeOutput(id string) {
+	for _, name := range []string{jobWorkbookName, jobJSONReportName} {
+		os.Remove(filepath.Join(m.dir, id, name))
+	}
+}
+
+// persist writes the state of a job, replacing the previous state at once.
+func (m *JobManager) persist(job *managedJob) error {
+	data, err := json.MarshalIndent(job.jobRecord, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode job state error: %w", err)
+	}
+	path := filepath.Join(m.dir, job.Job.ID, jobStateName)
+	err = os.WriteFile(path+".tmp", data, 0o644)
+	if err != nil {
+		return fmt.Errorf("write job state error: %w", err)
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return fmt.Errorf("write job state error: %w", err)
+	}
+	return nil
+}
+
+// runReconJob runs spec with the same workbook sheets as the command line and
+// the JSON report, written into dir. The FX rates and holiday files are
+// recorded with the run like on the command line.
+func runReconJob(ctx context.Context, dir string, spec JobSpec) error {
+	startDate, endDate, err := spec.Period()
+	if err != nil {
+		return err
+	}
+	input := filepath.Join(dir, jobInputDir)
+	var statementPaths []string
+	for _, name := range spec.BankStatementFiles {
+		statementPaths = append(statementPaths, filepath.Join(input, name))
+	}
+
+	executor := newDirExecutor(dir, nil, spec.Options)
+	csvReaderFactory := CSVReaderFactory{}
+	var configInputs []ConfigInput
+	if spec.FXRatesFile != "" {
+		rates, report, err := NewFXRateStorage(csvReaderFactory).GetRates(ctx, filepath.Join(input, spec.FXRatesFile))
+		if err != nil {
+			return err
+		}
+		executor = executor.WithFXRates(NewFXRates(rates))
+		configInputs = append(configInputs, ConfigInput{Kind: ConfigInputFXRates, LoadReport: report})
+	}
+	var holidays []Holiday
+	for _, name := range spec.HolidayFiles {
+		loaded, report, err := NewHolidayStorage(csvReaderFactory).GetHolidays(ctx, filepath.Join(input, name))
+		if err != nil {
+			return err
+		}
+		holidays = append(holidays, loaded...)
+		configInputs = append(configInputs, ConfigInput{Kind: ConfigInputHolidays, LoadReport: report})
+	}
+	if spec.BusinessDays || len(spec.HolidayFiles) > 0 {
+		executor = executor.WithCalendar(NewCalendar(holidays))
+	}
+	executor = executor.WithConfigInputs(configInputs...)
+	return executor.Execute(ctx, filepath.Join(input, spec.TransactionFile), statementPaths, startDate, endDate)
+}
+
+// newDirExecutor builds an executor reading CSV files and writing the
+// workbook and the JSON report into dir. fileAccounts names the bank and
+// account of statement files like BankStatementStorage.WithFileAccounts.
+func newDirExecutor(dir string, fileAccounts map[string]BankAccount, options Options) ReconExecutor {
+	reconPath := filepath.Join(dir, jobWorkbookName)
+	excelFactory := ExcelFactory{}
+	csvReaderFactory := CSVReaderFactory{}
+	return NewReconExecutor(
+		NewTransactionStorage(reconPath, "Transaction", excelFactory, csvReaderFactory),
+		NewBankStatementStorage(reconPath, excelFactory, csvReaderFactory).WithFileAccounts(fileAccounts),
+		NewSummaryStorage(reconPath, "Summary", excelFactory),
+		NewDashboardStorage(reconPath, "Dashboard", excelFactory),
+		NewRunInfoStorage(reconPath, "Run Info", excelFactory),
+		NewAgingStorage(reconPath, "Aging", excelFactory),
+		NewManualStorage(reconPath, "Manual", excelFactory),
+		NewDuplicatesStorage(reconPath, "Duplicates", excelFactory),
+		NewReversalsStorage(reconPath, "Reversals", excelFactory),
+		NewJSONReportStorage(filepath.Join(dir, jobJSONReportName), FileFactory{}),
+	).WithOptions(options)
+}
+
+// withDirNotifiers returns a copy of executor that tells the notifiers of
+// config about its runs, attaching the workbook and the JSON report of dir.
+func withDirNotifiers(executor ReconExecutor, dir string, config NotifyConfig, logger *log.Logger) ReconExecutor {
+	notifiers := config.Notifiers()
+	if len(notifiers) == 0 {
+		return executor
+	}
+	attachments := []string{filepath.Join(dir, jobWorkbookName), filepath.Join(dir, jobJSONReportName)}
+	return executor.WithNotifiers(config.NotifyRules, attachments, logger, notifiers...)
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generate job id error: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
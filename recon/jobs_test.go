@@ -0,0 +1,172 @@
+package recon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// blockingRunner writes partial output and waits until released or canceled.
+type blockingRunner struct {
+	started chan string
+	release chan error
+}
+
+func newBlockingRunner() blockingRunner {
+	return blockingRunner{started: make(chan string, 10), release: make(chan error)}
+}
+
+func (b blockingRunner) run(ctx context.Context, dir string, spec JobSpec) error {
+	os.WriteFile(filepath.Join(dir, jobWorkbookName), []byte("partial"), 0o644)
+	b.started <- filepath.Base(dir)
+	select {
+	case err := <-b.release:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func submitJob(g *WithT, m *JobManager) Job {
+	spec := JobSpec{StartDate: "2025-08-01", EndDate: "2025-08-01", TransactionFile: "transaction.csv", BankStatementFiles: []string{"bca.csv"}}
+	job, err := m.Submit(spec, func(dir string) error {
+		return os.WriteFile(filepath.Join(dir, "transaction.csv"), []byte("id,amount,type,time\n"), 0o644)
+	})
+	g.Expect(err).Should(BeNil())
+	return job
+}
+
+func jobStatus(m *JobManager, id string) func() JobStatus {
+	return func() JobStatus {
+		job, _ := m.Get(id)
+		return job.Status
+	}
+}
+
+func TestJobManager(t *testing.T) {
+	t.Run("runs at most workers jobs at once", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		runner := newBlockingRunner()
+		m, err := newJobManager(t.TempDir(), 1, runner.run, log.New(io.Discard, "", 0))
+		g.Expect(err).Should(BeNil())
+
+		first := submitJob(g, m)
+		second := submitJob(g, m)
+		g.Expect(<-runner.started).Should(Equal(first.ID))
+		g.Consistently(jobStatus(m, second.ID), 50*time.Millisecond).Should(Equal(JobQueued))
+		g.Expect(jobStatus(m, first.ID)()).Should(Equal(JobRunning))
+
+		runner.release <- nil
+		g.Expect(<-runner.started).Should(Equal(second.ID))
+		runner.release <- errors.New("boom")
+		g.Eventually(jobStatus(m, second.ID)).Should(Equal(JobFailed))
+
+		job, _ := m.Get(first.ID)
+		g.Expect(job.Status).Should(Equal(JobSucceeded))
+		g.Expect(job.StartedAt).ShouldNot(BeNil())
+		g.Expect(job.FinishedAt).ShouldNot(BeNil())
+		job, _ = m.Get(second.ID)
+		g.Expect(job.Error).Should(Equal("boom"))
+		g.Expect(m.List()).Should(HaveLen(2))
+		g.Expect(m.Shutdown(context.Background())).Should(Succeed())
+		g.Expect(m.Shutdown(context.Background())).Should(Succeed())
+	})
+
+	t.Run("cancels running and queued jobs and removes partial output", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		runner := newBlockingRunner()
+		m, err := newJobManager(dir, 1, runner.run, log.New(io.Discard, "", 0))
+		g.Expect(err).Should(BeNil())
+
+		running := submitJob(g, m)
+		queued := submitJob(g, m)
+		<-runner.started
+
+		_, err = m.Cancel(queued.ID)
+		g.Expect(err).Should(BeNil())
+		g.Expect(jobStatus(m, queued.ID)()).Should(Equal(JobCanceled))
+		_, err = m.Cancel(running.ID)
+		g.Expect(err).Should(BeNil())
+		g.Eventually(jobStatus(m, running.ID)).Should(Equal(JobCanceled))
+
+		g.Expect(filepath.Join(dir, running.ID, jobWorkbookName)).ShouldNot(BeAnExistingFile())
+		g.Expect(filepath.Join(dir, running.ID, jobInputDir, "transaction.csv")).Should(BeAnExistingFile())
+		_, err = m.Cancel(running.ID)
+		g.Expect(err).Should(MatchError(ErrJobFinished))
+		_, err = m.Output(running.ID, jobWorkbookName)
+		g.Expect(err).Should(MatchError(ErrJobNotSucceeded))
+
+		g.Expect(m.Delete(running.ID)).Should(Succeed())
+		g.Expect(filepath.Join(dir, running.ID)).ShouldNot(BeADirectory())
+		g.Expect(m.Delete(running.ID)).Should(MatchError(ErrJobNotFound))
+		g.Expect(m.Shutdown(context.Background())).Should(Succeed())
+	})
+
+	t.Run("keeps jobs across restarts", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		runner := newBlockingRunner()
+		m, err := newJobManager(dir, 1, runner.run, log.New(io.Discard, "", 0))
+		g.Expect(err).Should(BeNil())
+
+		done := submitJob(g, m)
+		<-runner.started
+		runner.release <- nil
+		g.Eventually(jobStatus(m, done.ID)).Should(Equal(JobSucceeded))
+		interrupted := submitJob(g, m)
+		<-runner.started
+		queued := submitJob(g, m)
+
+		// running jobs are canceled and queued again when shutdown times out
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		g.Expect(m.Shutdown(ctx)).Should(MatchError(context.DeadlineExceeded))
+		g.Expect(jobStatus(m, interrupted.ID)()).Should(Equal(JobQueued))
+		g.Expect(filepath.Join(dir, interrupted.ID, jobWorkbookName)).ShouldNot(BeAnExistingFile())
+
+		restarted, err := newJobManager(dir, 1, runner.run, log.New(io.Discard, "", 0))
+		g.Expect(err).Should(BeNil())
+		g.Expect(jobStatus(restarted, done.ID)()).Should(Equal(JobSucceeded))
+		g.Expect(<-runner.started).Should(Equal(interrupted.ID))
+		runner.release <- nil
+		g.Expect(<-runner.started).Should(Equal(queued.ID))
+		runner.release <- nil
+		g.Eventually(jobStatus(restarted, queued.ID)).Should(Equal(JobSucceeded))
+		g.Expect(restarted.Shutdown(context.Background())).Should(Succeed())
+	})
+
+	t.Run("fails a job whose outcome cannot be saved", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		var logs bytes.Buffer
+		// the job directory is gone by the time the job finishes
+		run := func(ctx context.Context, dir string, spec JobSpec) error {
+			return os.RemoveAll(dir)
+		}
+		m, err := newJobManager(t.TempDir(), 1, run, log.New(&logs, "", 0))
+		g.Expect(err).Should(BeNil())
+
+		job := submitJob(g, m)
+		g.Eventually(jobStatus(m, job.ID)).Should(Equal(JobFailed))
+
+		job, _ = m.Get(job.ID)
+		g.Expect(job.Error).Should(ContainSubstring("write job state error"))
+		g.Expect(logs.String()).Should(ContainSubstring("Job " + job.ID + ": write job state error"))
+		g.Expect(m.Shutdown(context.Background())).Should(Succeed())
+	})
+
+	t.Run("rejects fewer than one worker", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		_, err := NewJobManager(t.TempDir(), 0, log.New(io.Discard, "", 0))
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
@@ -0,0 +1,602 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSONReportSchemaVersion is bumped on every change to the JSON report
+// layout: the minor part for additive changes, the major part for changes
+// that break existing consumers.
+const JSONReportSchemaVersion = "2.3"
+
+// JSONReport is the document written by JSONReportStorage.
+type JSONReport struct {
+	SchemaVersion           string                  `json:"schema_version"`
+	Run                     JSONRun                 `json:"run"`
+	Period                  JSONPeriod              `json:"period"`
+	Inputs                  []JSONInput             `json:"inputs"`
+	Summary                 JSONSummary             `json:"summary"`
+	Matches                 []JSONMatch             `json:"matches"`
+	UnmatchedTransactions   []JSONTransaction       `json:"unmatched_transactions"`
+	UnmatchedBankStatements []JSONBankDiscrepancies `json:"unmatched_bank_statements"`
+	RejectedRows            []JSONRejectedRow       `json:"rejected_rows"`
+	CarriedForward          []JSONLedgerItem        `json:"carried_forward"`
+	Aging                   JSONAging               `json:"aging"`
+	Manual                  JSONManual              `json:"manual"`
+	Duplicates              JSONDuplicates          `json:"duplicates"`
+	Reversals               JSONReversals           `json:"reversals"`
+}
+
+type JSONRun struct {
+	ToolVersion string          `json:"tool_version"`
+	RunAt       time.Time       `json:"run_at"`
+	Arguments   []string        `json:"arguments"`
+	Match       JSONMatchConfig `json:"match_config"`
+	// BankCutoffs are the times of day, HH:MM, at which the business day of
+	// each bank ended.
+	BankCutoffs map[string]string   `json:"bank_cutoffs"`
+	Calendar    JSONCalendar        `json:"calendar"`
+	LoadWorkers int                 `json:"load_workers"`
+	Duplicates  JSONDuplicateConfig `json:"duplicate_config"`
+	Reversals   JSONReversalConfig  `json:"reversal_config"`
+}
+
+type JSONMatchConfig struct {
+	FXTolerance    float64 `json:"fx_tolerance"`
+	SettlementDays int     `json:"settlement_days"`
+}
+
+// JSONCalendar says whether the run counted business days, skipping weekends
+// and the holidays, or calendar days.
+type JSONCalendar struct {
+	BusinessDays bool `json:"business_days"`
+	Holidays     int  `json:"holidays"`
+}
+
+type JSONDuplicateConfig struct {
+	Keys   []string `json:"keys"`
+	Policy string   `json:"policy"`
+}
+
+type JSONReversalConfig struct {
+	WindowHours float64 `json:"window_hours"`
+}
+
+type JSONPeriod struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Timezone  string `json:"timezone"`
+}
+
+type JSONInput struct {
+	Kind         string  `json:"kind"`
+	Path         string  `json:"path"`
+	SHA256       string  `json:"sha256"`
+	RowsRead     int     `json:"rows_read"`
+	RowsFiltered int     `json:"rows_filtered"`
+	RowsRejected int     `json:"rows_rejected"`
+	RowsLoaded   int     `json:"rows_loaded"`
+	LoadSeconds  float64 `json:"load_seconds"`
+}
+
+const (
+	jsonInputTransactions   = "transactions"
+	jsonInputBankStatements = "bank_statements"
+)
+
+type JSONSummary struct {
+	Transactions            JSONSideSummary `json:"transactions"`
+	BankStatements          JSONSideSummary `json:"bank_statements"`
+	TotalProcessed          int             `json:"total_processed"`
+	MatchedAmountDifference float64         `json:"matched_amount_difference"`
+	FXDifference            float64         `json:"fx_difference"`
+	ReportingCurrency       string          `json:"reporting_currency"`
+	AmountDiscrepancy       float64         `json:"amount_discrepancy"`
+	ExplainedDiscrepancy    float64         `json:"explained_discrepancy"`
+	Balanced                bool            `json:"balanced"`
+	Accounts                []JSONAccount   `json:"accounts"`
+	BalanceBreaks           int             `json:"balance_breaks"`
+	BalanceChecks           []JSONBalance   `json:"balance_checks"`
+	Unaccounted             []string        `json:"unaccounted"`
+}
+
+type JSONAccount struct {
+	Bank           string          `json:"bank"`
+	Account        string          `json:"account"`
+	BankStatements JSONSideSummary `json:"bank_statements"`
+}
+
+type JSONBalance struct {
+	Bank       string           `json:"bank"`
+	Account    string           `json:"account"`
+	Opening    float64          `json:"opening"`
+	Lines      float64          `json:"lines"`
+	Closing    float64          `json:"closing"`
+	Difference float64          `json:"difference"`
+	Declared   bool             `json:"declared"`
+	Broken     bool             `json:"broken"`
+	Gaps       []JSONBalanceGap `json:"gaps"`
+}
+
+type JSONBalanceGap struct {
+	Line     int     `json:"line"`
+	ID       string  `json:"id"`
+	Expected float64 `json:"expected"`
+	Actual   float64 `json:"actual"`
+	Missing  float64 `json:"missing"`
+}
+
+type JSONSideSummary struct {
+	Count           int     `json:"count"`
+	Amount          float64 `json:"amount"`
+	MatchedCount    int     `json:"matched_count"`
+	MatchedAmount   float64 `json:"matched_amount"`
+	UnmatchedCount  int     `json:"unmatched_count"`
+	UnmatchedAmount float64 `json:"unmatched_amount"`
+}
+
+type JSONMatch struct {
+	Transaction   JSONTransaction   `json:"transaction"`
+	BankStatement JSONBankStatement `json:"bank_statement"`
+	FXDifference  float64           `json:"fx_difference"`
+}
+
+type JSONTransaction struct {
+	ID       string    `json:"id"`
+	Amount   float64   `json:"amount"`
+	Currency string    `json:"currency"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+}
+
+type JSONBankStatement struct {
+	Bank      string    `json:"bank"`
+	Account   string    `json:"account"`
+	ID        string    `json:"id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Time      time.Time `json:"time"`
+	Reference string    `json:"reference"`
+}
+
+type JSONBankDiscrepancies struct {
+	Bank       string              `json:"bank"`
+	Account    string              `json:"account"`
+	Amount     float64             `json:"amount"`
+	Statements []JSONBankStatement `json:"statements"`
+}
+
+type JSONRejectedRow struct {
+	Path   string   `json:"path"`
+	Line   int      `json:"line"`
+	Row    []string `json:"row"`
+	Reason string   `json:"reason"`
+}
+
+type JSONLedgerItem struct {
+	Kind     string `json:"kind"`
+	Key      string `json:"key"`
+	OpenedBy string `json:"opened_by"`
+	Cleared  bool   `json:"cleared"`
+}
+
+type JSONAging struct {
+	Buckets             []string         `json:"buckets"`
+	EscalateAfterDays   int              `json:"escalate_after_days"`
+	EscalateAboveAmount float64          `json:"escalate_above_amount"`
+	Groups              []JSONAgingGroup `json:"groups"`
+	Escalated           []JSONAgedItem   `json:"escalated"`
+}
+
+type JSONAgingGroup struct {
+	Side      string            `json:"side"`
+	Direction string            `json:"direction"`
+	Currency  string            `json:"currency"`
+	Buckets   []JSONAgingBucket `json:"buckets"`
+}
+
+type JSONAgingBucket struct {
+	Bucket string  `json:"bucket"`
+	Count  int     `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+type JSONAgedItem struct {
+	Side      string    `json:"side"`
+	Direction string    `json:"direction"`
+	ID        string    `json:"id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Time      time.Time `json:"time"`
+	AgeDays   int       `json:"age_days"`
+	Bucket    string    `json:"bucket"`
+}
+
+type JSONManual struct {
+	Matches    []JSONOverride `json:"matches"`
+	Unmatches  []JSONOverride `json:"unmatches"`
+	Exclusions []JSONOverride `json:"exclusions"`
+	Unapplied  []JSONOverride `json:"unapplied"`
+}
+
+type JSONOverride struct {
+	Action         string              `json:"action"`
+	Reason         string              `json:"reason"`
+	Author         string              `json:"author"`
+	TransactionIDs []string            `json:"transaction_ids"`
+	Statements     []JSONStatementRef  `json:"statements"`
+	Transactions   []JSONTransaction   `json:"transactions,omitempty"`
+	BankStatements []JSONBankStatement `json:"bank_statements,omitempty"`
+}
+
+type JSONStatementRef struct {
+	Bank    string `json:"bank"`
+	Account string `json:"account"`
+	ID      string `json:"id"`
+}
+
+type JSONDuplicates struct {
+	Keys           []string                     `json:"keys"`
+	Policy         string                       `json:"policy"`
+	Transactions   []JSONDuplicateTransaction   `json:"transactions"`
+	BankStatements []JSONDuplicateBankStatement `json:"bank_statements"`
+}
+
+type JSONDuplicateTransaction struct {
+	Key         string          `json:"key"`
+	Transaction JSONTransaction `json:"transaction"`
+	Original    JSONTransaction `json:"original"`
+}
+
+type JSONDuplicateBankStatement struct {
+	Key           string            `json:"key"`
+	BankStatement JSONBankStatement `json:"bank_statement"`
+	Original      JSONBankStatement `json:"original"`
+}
+
+type JSONReversals struct {
+	WindowHours    float64                     `json:"window_hours"`
+	Transactions   []JSONTransactionReversal   `json:"transactions"`
+	BankStatements []JSONBankStatementReversal `json:"bank_statements"`
+}
+
+type JSONTransactionReversal struct {
+	Original JSONTransaction `json:"original"`
+	Reversal JSONTransaction `json:"reversal"`
+}
+
+type JSONBankStatementReversal struct {
+	Original JSONBankStatement `json:"original"`
+	Reversal JSONBankStatement `json:"reversal"`
+}
+
+// NewJSONReport maps a recon result to the versioned JSON report layout.
+// Lists are never null so consumers can iterate them unconditionally.
+func NewJSONReport(result Result) JSONReport {
+	s := result.Summary
+	report := JSONReport{
+		SchemaVersion: JSONReportSchemaVersion,
+		Run: JSONRun{
+			ToolVersion: Version,
+			RunAt:       result.RunAt,
+			Arguments:   append([]string{}, result.Options.RunArguments...),
+			Match: JSONMatchConfig{
+				FXTolerance:    result.Options.Match.FXTolerance,
+				SettlementDays: result.Options.Match.SettlementDays,
+			},
+			BankCutoffs: map[string]string{},
+			Calendar: JSONCalendar{
+				BusinessDays: result.Calendar.businessDays(),
+				Holidays:     len(result.Calendar.holidays),
+			},
+			LoadWorkers: result.Options.LoadWorkers,
+		},
+		Period: JSONPeriod{
+			StartDate: result.StartDate.Format(time.DateOnly),
+			EndDate:   result.EndDate.Format(time.DateOnly),
+			Timezone:  result.StartDate.Location().String(),
+		},
+		Inputs: []JSONInput{},
+		Summary: JSONSummary{
+			Transactions: JSONSideSummary{
+				Count:           s.TotalTransactions,
+				Amount:          s.TotalAmountTransactions,
+				MatchedCount:    s.MatchedTransactions,
+				MatchedAmount:   s.MatchedAmountTransactions,
+				UnmatchedCount:  s.UnmatchedTransactions,
+				UnmatchedAmount: s.UnmatchedAmountTransactions,
+			},
+			BankStatements: JSONSideSummary{
+				Count:           s.TotalBankStatements,
+				Amount:          s.TotalAmountBankStatements,
+				MatchedCount:    s.MatchedBankStatements,
+				MatchedAmount:   s.MatchedAmountBankStatements,
+				UnmatchedCount:  s.UnmatchedBankStatements,
+				UnmatchedAmount: s.UnmatchedAmountBankStatements,
+			},
+			TotalProcessed:          s.TotalProcessed(),
+			MatchedAmountDifference: s.MatchedAmountDifference,
+			FXDifference:            s.FXDifference,
+			ReportingCurrency:       s.ReportingCurrency,
+			AmountDiscrepancy:       s.AmountDiscrepancy(),
+			ExplainedDiscrepancy:    s.ExplainedDiscrepancy(),
+			Balanced:                s.Balanced(),
+			Accounts:                []JSONAccount{},
+			BalanceBreaks:           s.BalanceBreaks(),
+			BalanceChecks:           []JSONBalance{},
+			Unaccounted:             append([]string{}, s.Unaccounted...),
+		},
+		Matches:                 []JSONMatch{},
+		UnmatchedTransactions:   []JSONTransaction{},
+		UnmatchedBankStatements: []JSONBankDiscrepancies{},
+		RejectedRows:            []JSONRejectedRow{},
+		CarriedForward:          []JSONLedgerItem{},
+	}
+
+	for _, a := range s.Accounts {
+		report.Summary.Accounts = append(report.Summary.Accounts, JSONAccount{
+			Bank:    a.Bank,
+			Account: a.Account,
+			BankStatements: JSONSideSummary{
+				Count:           a.TotalBankStatements,
+				Amount:          a.TotalAmountBankStatements,
+				MatchedCount:    a.MatchedBankStatements,
+				MatchedAmount:   a.MatchedAmountBankStatements,
+				UnmatchedCount:  a.UnmatchedBankStatements,
+				UnmatchedAmount: a.UnmatchedAmountBankStatements,
+			},
+		})
+	}
+
+	for _, check := range s.BalanceChecks {
+		balance := JSONBalance{
+			Bank:       check.Bank,
+			Account:    check.Account,
+			Opening:    check.Opening,
+			Lines:      check.Lines,
+			Closing:    check.Closing,
+			Difference: check.Difference(),
+			Declared:   check.Declared,
+			Broken:     check.Broken(),
+			Gaps:       []JSONBalanceGap{},
+		}
+		for _, gap := range check.Gaps {
+			balance.Gaps = append(balance.Gaps, JSONBalanceGap{Line: gap.Line, ID: gap.ID, Expected: gap.Expected, Actual: gap.Actual, Missing: gap.Missing()})
+		}
+		report.Summary.BalanceChecks = append(report.Summary.BalanceChecks, balance)
+	}
+
+	report.Inputs = append(report.Inputs, newJSONInput(jsonInputTransactions, result.TransactionInput))
+	for _, input := range result.BankStatementInputs {
+		report.Inputs = append(report.Inputs, newJSONInput(jsonInputBankStatements, input))
+	}
+	for _, input := range result.ConfigInputs {
+		report.Inputs = append(report.Inputs, newJSONInput(input.Kind, input.LoadReport))
+	}
+	for bank, cutoff := range result.Cutoffs {
+		report.Run.BankCutoffs[bank] = formatCutoff(cutoff)
+	}
+
+	for _, m := range result.Matches {
+		report.Matches = append(report.Matches, JSONMatch{
+			Transaction:   newJSONTransaction(m.Transaction),
+			BankStatement: newJSONBankStatement(m.BankStatement),
+			FXDifference:  m.FXDifference,
+		})
+	}
+
+	for _, t := range result.UnmatchedTransactions {
+		report.UnmatchedTransactions = append(report.UnmatchedTransactions, newJSONTransaction(t))
+	}
+
+	for _, group := range result.UnmatchedBankStatements {
+		discrepancies := JSONBankDiscrepancies{Bank: group.Bank, Account: group.Account, Amount: group.Amount(), Statements: []JSONBankStatement{}}
+		for _, statement := range group.Statements {
+			discrepancies.Statements = append(discrepancies.Statements, newJSONBankStatement(statement))
+		}
+		report.UnmatchedBankStatements = append(report.UnmatchedBankStatements, discrepancies)
+	}
+
+	for _, row := range result.RejectedRows() {
+		report.RejectedRows = append(report.RejectedRows, JSONRejectedRow{Path: row.Path, Line: row.Line, Row: row.Row, Reason: row.Reason})
+	}
+
+	cleared := map[string]bool{}
+	for _, item := range result.Cleared() {
+		cleared[item.Key()] = true
+	}
+	for _, item := range result.CarriedForward {
+		report.CarriedForward = append(report.CarriedForward, JSONLedgerItem{
+			Kind:     string(item.Kind),
+			Key:      item.Key(),
+			OpenedBy: item.OpenedBy,
+			Cleared:  cleared[item.Key()],
+		})
+	}
+
+	report.Aging = newJSONAging(result)
+	report.Manual = JSONManual{
+		Matches:    newJSONAppliedOverrides(result.ManualMatches),
+		Unmatches:  newJSONAppliedOverrides(result.ManualUnmatches),
+		Exclusions: newJSONAppliedOverrides(result.Exclusions),
+		Unapplied:  []JSONOverride{},
+	}
+	for _, override := range result.UnappliedOverrides {
+		report.Manual.Unapplied = append(report.Manual.Unapplied, newJSONOverride(override))
+	}
+
+	report.Duplicates = newJSONDuplicates(result)
+	report.Reversals = newJSONReversals(result)
+	report.Run.Duplicates = JSONDuplicateConfig{Keys: report.Duplicates.Keys, Policy: report.Duplicates.Policy}
+	report.Run.Reversals = JSONReversalConfig{WindowHours: report.Reversals.WindowHours}
+
+	return report
+}
+
+func newJSONDuplicates(result Result) JSONDuplicates {
+	config := result.Options.Duplicates
+	j := JSONDuplicates{
+		Keys:           []string{},
+		Policy:         string(config.policy()),
+		Transactions:   []JSONDuplicateTransaction{},
+		BankStatements: []JSONDuplicateBankStatement{},
+	}
+	for _, key := range config.Keys {
+		j.Keys = append(j.Keys, string(key))
+	}
+	for _, dup := range result.DuplicateTransactions {
+		j.Transactions = append(j.Transactions, JSONDuplicateTransaction{
+			Key:         string(dup.Key),
+			Transaction: newJSONTransaction(dup.Transaction),
+			Original:    newJSONTransaction(dup.Original),
+		})
+	}
+	for _, dup := range result.DuplicateBankStatements {
+		j.BankStatements = append(j.BankStatements, JSONDuplicateBankStatement{
+			Key:           string(dup.Key),
+			BankStatement: newJSONBankStatement(dup.BankStatement),
+			Original:      newJSONBankStatement(dup.Original),
+		})
+	}
+	return j
+}
+
+func newJSONReversals(result Result) JSONReversals {
+	j := JSONReversals{
+		WindowHours:    result.Options.Reversals.Window.Hours(),
+		Transactions:   []JSONTransactionReversal{},
+		BankStatements: []JSONBankStatementReversal{},
+	}
+	for _, reversal := range result.TransactionReversals {
+		j.Transactions = append(j.Transactions, JSONTransactionReversal{
+			Original: newJSONTransaction(reversal.Original),
+			Reversal: newJSONTransaction(reversal.Reversal),
+		})
+	}
+	for _, reversal := range result.BankStatementReversals {
+		j.BankStatements = append(j.BankStatements, JSONBankStatementReversal{
+			Original: newJSONBankStatement(reversal.Original),
+			Reversal: newJSONBankStatement(reversal.Reversal),
+		})
+	}
+	return j
+}
+
+func newJSONOverride(override Override) JSONOverride {
+	j := JSONOverride{
+		Action:         string(override.Action),
+		Reason:         override.Reason,
+		Author:         override.Author,
+		TransactionIDs: append([]string{}, override.TransactionIDs...),
+		Statements:     []JSONStatementRef{},
+	}
+	for _, ref := range override.Statements {
+		j.Statements = append(j.Statements, JSONStatementRef{Bank: ref.Bank, Account: ref.Account, ID: ref.ID})
+	}
+	return j
+}
+
+func newJSONAppliedOverrides(applied []AppliedOverride) []JSONOverride {
+	overrides := []JSONOverride{}
+	for _, a := range applied {
+		j := newJSONOverride(a.Override)
+		for _, t := range a.Transactions {
+			j.Transactions = append(j.Transactions, newJSONTransaction(t))
+		}
+		for _, s := range a.BankStatements {
+			j.BankStatements = append(j.BankStatements, newJSONBankStatement(s))
+		}
+		overrides = append(overrides, j)
+	}
+	return overrides
+}
+
+func newJSONAging(result Result) JSONAging {
+	aging := result.Aging()
+	config := result.Options.Aging
+	j := JSONAging{
+		Buckets:             aging.Labels,
+		EscalateAfterDays:   config.EscalateAfterDays,
+		EscalateAboveAmount: config.EscalateAboveAmount,
+		Groups:              []JSONAgingGroup{},
+		Escalated:           []JSONAgedItem{},
+	}
+	for _, group := range aging.Groups {
+		g := JSONAgingGroup{Side: group.Side, Direction: string(group.Direction), Currency: group.Currency}
+		for i, label := range aging.Labels {
+			g.Buckets = append(g.Buckets, JSONAgingBucket{Bucket: label, Count: group.Counts[i], Amount: group.Amounts[i]})
+		}
+		j.Groups = append(j.Groups, g)
+	}
+	for _, item := range aging.Escalated() {
+		j.Escalated = append(j.Escalated, JSONAgedItem{
+			Side:      item.Side,
+			Direction: string(item.Direction),
+			ID:        item.ID,
+			Amount:    item.Amount,
+			Currency:  item.Currency,
+			Time:      item.Time,
+			AgeDays:   item.AgeDays,
+			Bucket:    aging.Labels[item.Bucket],
+		})
+	}
+	return j
+}
+
+func newJSONInput(kind string, input LoadReport) JSONInput {
+	return JSONInput{
+		Kind:         kind,
+		Path:         input.Path,
+		SHA256:       input.SHA256,
+		RowsRead:     input.RowsRead,
+		RowsFiltered: input.RowsFiltered,
+		RowsRejected: input.RowsRejected(),
+		RowsLoaded:   input.RowsLoaded(),
+		LoadSeconds:  input.Duration.Seconds(),
+	}
+}
+
+func newJSONTransaction(t Transaction) JSONTransaction {
+	return JSONTransaction{ID: t.ID, Amount: t.Amount, Currency: t.Currency, Type: string(t.Type), Time: t.Time}
+}
+
+func newJSONBankStatement(s BankStatement) JSONBankStatement {
+	return JSONBankStatement{Bank: s.Bank, Account: s.Account, ID: s.ID, Amount: s.Amount, Currency: s.Currency, Time: s.Time, Reference: s.Reference}
+}
+
+type JSONReportStorage struct {
+	destinationFileNamePath string
+	fileWriterFactory       FileWriterFactory
+}
+
+func NewJSONReportStorage(destinationFileNamePath string, fileWriterFactory FileWriterFactory) JSONReportStorage {
+	return JSONReportStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		fileWriterFactory:       fileWriterFactory,
+	}
+}
+
+func (j JSONReportStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := j.fileWriterFactory.Create(j.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(NewJSONReport(result))
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
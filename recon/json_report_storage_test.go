@@ -0,0 +1,204 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// expectGolden compares actual with testdata/name, rewriting the file when
+// the tests run with -update.
+func expectGolden(g *WithT, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		g.Expect(os.MkdirAll("testdata", 0o755)).Should(Succeed())
+		g.Expect(os.WriteFile(path, actual, 0o644)).Should(Succeed())
+	}
+
+	expected, err := os.ReadFile(path)
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(actual)).Should(Equal(string(expected)))
+}
+
+func TestJSONReportStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.json"
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	result := Result{
+		RunAt: time.Date(2025, 8, 3, 9, 30, 0, 0, time.UTC),
+		Options: Options{
+			ReportingCurrency: "IDR",
+			RunArguments:      []string{"-start-date=2025-08-01", "-end-date=2025-08-02"},
+			Match:             MatchConfig{FXTolerance: 0.02, SettlementDays: 2},
+			Duplicates:        DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID, DuplicateKeyReference}, Policy: DuplicateKeepFirst},
+			Reversals:         ReversalConfig{Window: 48 * time.Hour},
+			Aging:             AgingConfig{EscalateAboveAmount: 250},
+			LoadWorkers:       4,
+		},
+		Cutoffs:   map[string]time.Duration{"BCA": 22 * time.Hour},
+		Calendar:  NewCalendar([]Holiday{{Date: day.AddDate(0, 0, 16), Name: "Independence Day"}}),
+		StartDate: day,
+		EndDate:   day.AddDate(0, 0, 1),
+		TransactionInput: LoadReport{
+			Path:         "data/transaction.csv",
+			SHA256:       "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+			RowsRead:     3,
+			RowsFiltered: 1,
+			Duration:     250 * time.Millisecond,
+		},
+		BankStatementInputs: []LoadReport{
+			{Path: "data/bca.csv", SHA256: "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9", RowsRead: 1},
+			{
+				Path:         "data/bri.csv",
+				SHA256:       "baa5a0964d3320fbc0c6a922140453c8513ea24ab8fd0577034804a967248096",
+				RowsRead:     2,
+				RejectedRows: []RejectedRow{{Path: "data/bri.csv", Line: 4, Row: []string{"9"}, Reason: "missing columns"}},
+			},
+		},
+		ConfigInputs: []ConfigInput{
+			{Kind: ConfigInputFXRates, LoadReport: LoadReport{Path: "data/rates.csv", SHA256: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8", RowsRead: 2}},
+			{Kind: ConfigInputHolidays, LoadReport: LoadReport{Path: "data/holidays-2025.csv", SHA256: "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3", RowsRead: 1}},
+		},
+		Summary: Summary{
+			TotalTransactions:             3,
+			TotalAmountTransactions:       160300,
+			MatchedTransactions:           2,
+			MatchedAmountTransactions:     160100,
+			UnmatchedTransactions:         1,
+			UnmatchedAmountTransactions:   200,
+			TotalBankStatements:           3,
+			TotalAmountBankStatements:     161400,
+			MatchedBankStatements:         2,
+			MatchedAmountBankStatements:   161100,
+			UnmatchedBankStatements:       1,
+			UnmatchedAmountBankStatements: 300,
+			FXDifference:                  -1000,
+			ReportingCurrency:             "IDR",
+			Accounts: []AccountSummary{
+				{Bank: "bca", TotalBankStatements: 2, TotalAmountBankStatements: 161100, MatchedBankStatements: 2, MatchedAmountBankStatements: 161100},
+				{Bank: "bri", Account: "222", TotalBankStatements: 1, TotalAmountBankStatements: 300, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 300},
+			},
+			BalanceChecks: []BalanceCheck{
+				{Bank: "bca", Opening: 1000, Lines: 100, Closing: 1100},
+				{Bank: "bri", Account: "222", Opening: 0, Lines: 300, Closing: 320, Gaps: []BalanceGap{{Line: 3, ID: "5", Expected: 300, Actual: 320}}},
+			},
+		},
+		Matches: []Match{
+			{Transaction: Transaction{ID: "1", Amount: 100, Type: Credit, Time: day}, BankStatement: BankStatement{Bank: "bca", ID: "1", Amount: 100, Time: day}},
+			{
+				Transaction:   Transaction{ID: "6", Amount: 10, Currency: "USD", Type: Credit, Time: day},
+				BankStatement: BankStatement{Bank: "bca", ID: "6", Amount: 161000, Currency: "IDR", Time: day},
+				FXDifference:  -1000,
+			},
+		},
+		UnmatchedTransactions: []Transaction{{ID: "2", Amount: 200, Type: Debit, Time: day.Add(2 * time.Hour)}},
+		ManualMatches: []AppliedOverride{{
+			Override: Override{
+				Action:         OverrideMatch,
+				TransactionIDs: []string{"7"},
+				Statements:     []StatementRef{{Bank: "bca", ID: "8"}},
+				Reason:         "customer paid twice, one refunded",
+				Author:         "finance@example.com",
+			},
+			Transactions:   []Transaction{{ID: "7", Amount: 50, Type: Credit, Time: day}},
+			BankStatements: []BankStatement{{Bank: "bca", ID: "8", Amount: 50, Time: day}},
+		}},
+		DuplicateBankStatements: []DuplicateBankStatement{{
+			Key:           DuplicateKeyReference,
+			BankStatement: BankStatement{Bank: "bca", ID: "9", Amount: 100, Time: day, Reference: "TRF-1"},
+			Original:      BankStatement{Bank: "bca", ID: "1", Amount: 100, Time: day, Reference: "TRF-1"},
+		}},
+		TransactionReversals: []TransactionReversal{{
+			Original: Transaction{ID: "10", Amount: 75, Type: Debit, Time: day},
+			Reversal: Transaction{ID: "11", Amount: 75, Type: Credit, Time: day.Add(time.Hour)},
+		}},
+		UnappliedOverrides: []Override{
+			{Action: OverrideExclude, TransactionIDs: []string{"old"}, Reason: "test data", Author: "ops"},
+		},
+		CarriedForward: []LedgerItem{
+			{Kind: LedgerBankStatement, BankStatement: BankStatement{Bank: "bca", ID: "1", Amount: 100, Time: day}, OpenedBy: "20250801T060000.000Z"},
+		},
+		UnmatchedBankStatements: []BankStatementDiscrepancy{
+			{Bank: "bri", Account: "222", Statements: []BankStatement{{Bank: "bri", Account: "222", ID: "3", Amount: 300, Time: day}}},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewJSONReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		file := &bufferWriteCloser{}
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(file, nil)
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(file.closed).Should(BeTrue())
+		expectGolden(g, "json_report.golden.json", file.Bytes())
+	})
+
+	t.Run("empty lists are written as arrays", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewJSONReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		file := &bufferWriteCloser{}
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(file, nil)
+
+		err := storage.StoreReport(context.Background(), Result{
+			RunAt:            result.RunAt,
+			StartDate:        day,
+			EndDate:          day,
+			TransactionInput: LoadReport{Path: "data/transaction.csv"},
+		})
+
+		g.Expect(err).Should(BeNil())
+		expectGolden(g, "json_report_empty.golden.json", file.Bytes())
+	})
+
+	t.Run("create file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewJSONReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(nil, errors.New("create error"))
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockFileWriterFactory := NewMockFileWriterFactory(ctrl)
+		storage := NewJSONReportStorage(destinationFileNamePath, mockFileWriterFactory)
+
+		mockFileWriterFactory.EXPECT().Create(destinationFileNamePath).Return(failingWriteCloser{}, nil)
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
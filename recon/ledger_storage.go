@@ -0,0 +1,213 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type LedgerItemKind string
+
+const (
+	LedgerTransaction   LedgerItemKind = "transaction"
+	LedgerBankStatement LedgerItemKind = "bank_statement"
+)
+
+// LedgerItem is an unmatched transaction or bank statement kept across runs
+// until a later run matches it.
+type LedgerItem struct {
+	Kind          LedgerItemKind
+	Transaction   Transaction   `json:",omitzero"`
+	BankStatement BankStatement `json:",omitzero"`
+	OpenedBy      string
+	ClearedBy     string
+	ClearedAt     time.Time
+}
+
+func (l LedgerItem) Key() string {
+	if l.Kind == LedgerTransaction {
+		return transactionLedgerKey(l.Transaction)
+	}
+	return bankStatementLedgerKey(l.BankStatement)
+}
+
+func (l LedgerItem) Time() time.Time {
+	if l.Kind == LedgerTransaction {
+		return l.Transaction.Time
+	}
+	return l.BankStatement.Time
+}
+
+func (l LedgerItem) Open() bool {
+	return l.ClearedBy == ""
+}
+
+// transactionLedgerKey identifies a transaction by its ID. Transactions
+// without one are told apart by their time, type, amount and currency, so
+// they do not overwrite each other.
+func transactionLedgerKey(t Transaction) string {
+	if t.ID == "" {
+		return fmt.Sprintf("%s/@%s/%s/%v/%s", LedgerTransaction, t.Time.UTC().Format(time.RFC3339Nano), t.Type, t.Amount, t.Currency)
+	}
+	return fmt.Sprintf("%s/%s", LedgerTransaction, t.ID)
+}
+
+// bankStatementLedgerKey identifies a statement line by its bank, account
+// and ID, or by its time, amount and currency when it has no ID.
+func bankStatementLedgerKey(s BankStatement) string {
+	id := s.ID
+	if id == "" {
+		id = fmt.Sprintf("@%s/%v/%s", s.Time.UTC().Format(time.RFC3339Nano), s.Amount, s.Currency)
+	}
+	if s.Account == "" {
+		return fmt.Sprintf("%s/%s/%s", LedgerBankStatement, s.Bank, id)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", LedgerBankStatement, s.Bank, s.Account, id)
+}
+
+var ledgerItemsBucket = []byte("items")
+
+// LedgerStorage keeps open items in a bbolt file. The file is opened per
+// call so separate runs never hold the lock for longer than an update.
+type LedgerStorage struct {
+	path string
+}
+
+func NewLedgerStorage(path string) LedgerStorage {
+	return LedgerStorage{path: path}
+}
+
+func (l LedgerStorage) open() (*bolt.DB, error) {
+	db, err := bolt.Open(l.path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %w", err)
+	}
+	return db, nil
+}
+
+// GetOpenItems returns the open items dated before the given time, oldest first.
+func (l LedgerStorage) GetOpenItems(ctx context.Context, before time.Time) ([]LedgerItem, error) {
+	db, err := l.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var items []LedgerItem
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ledgerItemsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var item LedgerItem
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("invalid ledger item %s: %w", k, err)
+			}
+			if item.Open() && item.Time().Before(before) {
+				items = append(items, item)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	sortLedgerItems(items)
+	return items, nil
+}
+
+// UpdateLedger clears every open item settled in the result and opens an
+// item for every unmatched transaction and bank statement not tracked yet.
+func (l LedgerStorage) UpdateLedger(ctx context.Context, result Result) error {
+	db, err := l.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	runID := result.RunID()
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(ledgerItemsBucket)
+		if err != nil {
+			return err
+		}
+
+		clear := func(key string) error {
+			v := bucket.Get([]byte(key))
+			if v == nil {
+				return nil
+			}
+			var item LedgerItem
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("invalid ledger item %s: %w", key, err)
+			}
+			if !item.Open() {
+				return nil
+			}
+			item.ClearedBy = runID
+			item.ClearedAt = result.RunAt
+			return putLedgerItem(bucket, item)
+		}
+
+		transactions, statements := result.Settled()
+		for _, t := range transactions {
+			if err := clear(transactionLedgerKey(t)); err != nil {
+				return err
+			}
+		}
+		for _, s := range statements {
+			if err := clear(bankStatementLedgerKey(s)); err != nil {
+				return err
+			}
+		}
+
+		open := func(item LedgerItem) error {
+			if bucket.Get([]byte(item.Key())) != nil {
+				return nil
+			}
+			item.OpenedBy = runID
+			return putLedgerItem(bucket, item)
+		}
+
+		for _, t := range result.UnmatchedTransactions {
+			if err := open(LedgerItem{Kind: LedgerTransaction, Transaction: t}); err != nil {
+				return err
+			}
+		}
+		for _, group := range result.UnmatchedBankStatements {
+			for _, s := range group.Statements {
+				if err := open(LedgerItem{Kind: LedgerBankStatement, BankStatement: s}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update ledger: %w", err)
+	}
+	return nil
+}
+
+func sortLedgerItems(items []LedgerItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Time().Equal(items[j].Time()) {
+			return items[i].Time().Before(items[j].Time())
+		}
+		return items[i].Key() < items[j].Key()
+	})
+}
+
+func putLedgerItem(bucket *bolt.Bucket, item LedgerItem) error {
+	v, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(item.Key()), v)
+}
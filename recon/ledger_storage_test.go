@@ -0,0 +1,148 @@
+package recon
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLedgerStorage(t *testing.T) {
+	jan31 := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
+	feb1 := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	janRunAt := time.Date(2025, 2, 1, 6, 0, 0, 0, time.UTC)
+	febRunAt := time.Date(2025, 3, 1, 6, 0, 0, 0, time.UTC)
+
+	janTransaction := Transaction{ID: "trx-1", Amount: 100, Type: Debit, Time: jan31}
+	janStatement := BankStatement{Bank: "bca", ID: "bca-9", Amount: 75, Time: jan31.Add(time.Hour)}
+	janResult := Result{
+		RunAt:                   janRunAt,
+		UnmatchedTransactions:   []Transaction{janTransaction},
+		UnmatchedBankStatements: []BankStatementDiscrepancy{{Bank: "bca", Statements: []BankStatement{janStatement}}},
+	}
+
+	t.Run("empty ledger has no open items", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "ledger.db"))
+
+		items, err := ledger.GetOpenItems(context.Background(), feb1)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(items).Should(BeEmpty())
+	})
+
+	t.Run("opens unmatched items, oldest first", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "ledger.db"))
+
+		g.Expect(ledger.UpdateLedger(context.Background(), janResult)).Should(Succeed())
+
+		items, err := ledger.GetOpenItems(context.Background(), feb1)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(items).Should(Equal([]LedgerItem{
+			{Kind: LedgerTransaction, Transaction: janTransaction, OpenedBy: janResult.RunID()},
+			{Kind: LedgerBankStatement, BankStatement: janStatement, OpenedBy: janResult.RunID()},
+		}))
+	})
+
+	t.Run("only returns items dated before the period", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "ledger.db"))
+
+		g.Expect(ledger.UpdateLedger(context.Background(), janResult)).Should(Succeed())
+
+		items, err := ledger.GetOpenItems(context.Background(), jan31)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(items).Should(BeEmpty())
+	})
+
+	t.Run("rerunning a period does not duplicate or reopen items", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "ledger.db"))
+
+		g.Expect(ledger.UpdateLedger(context.Background(), janResult)).Should(Succeed())
+		rerun := janResult
+		rerun.RunAt = janRunAt.Add(time.Hour)
+		g.Expect(ledger.UpdateLedger(context.Background(), rerun)).Should(Succeed())
+
+		items, err := ledger.GetOpenItems(context.Background(), feb1)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(items).Should(HaveLen(2))
+		g.Expect(items[0].OpenedBy).Should(Equal(janResult.RunID()))
+	})
+
+	t.Run("clears matched items with the clearing run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "ledger.db"))
+
+		g.Expect(ledger.UpdateLedger(context.Background(), janResult)).Should(Succeed())
+
+		febResult := Result{
+			RunAt: febRunAt,
+			Matches: []Match{
+				{Transaction: janTransaction, BankStatement: BankStatement{Bank: "bca", ID: "bca-10", Amount: 100, Time: feb1}},
+			},
+		}
+		g.Expect(ledger.UpdateLedger(context.Background(), febResult)).Should(Succeed())
+
+		items, err := ledger.GetOpenItems(context.Background(), feb1)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(items).Should(Equal([]LedgerItem{
+			{Kind: LedgerBankStatement, BankStatement: janStatement, OpenedBy: janResult.RunID()},
+		}))
+	})
+
+	t.Run("keeps items without an ID apart", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "ledger.db"))
+		first := Transaction{Amount: 100, Type: Debit, Time: jan31}
+		second := Transaction{Amount: 40, Type: Debit, Time: jan31}
+		line := BankStatement{Bank: "bca", Amount: 75, Time: jan31}
+
+		g.Expect(ledger.UpdateLedger(context.Background(), Result{
+			RunAt:                   janRunAt,
+			UnmatchedTransactions:   []Transaction{first, second},
+			UnmatchedBankStatements: []BankStatementDiscrepancy{{Bank: "bca", Statements: []BankStatement{line, janStatement}}},
+		})).Should(Succeed())
+		g.Expect(ledger.UpdateLedger(context.Background(), Result{
+			RunAt:   febRunAt,
+			Matches: []Match{{Transaction: second, BankStatement: BankStatement{Bank: "bca", ID: "bca-10", Amount: 40, Time: feb1}}},
+		})).Should(Succeed())
+
+		items, err := ledger.GetOpenItems(context.Background(), feb1)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(items).Should(HaveLen(3))
+		g.Expect(items[0].BankStatement).Should(Equal(line))
+		g.Expect(items[1].Transaction).Should(Equal(first))
+		g.Expect(items[2].BankStatement).Should(Equal(janStatement))
+	})
+
+	t.Run("invalid ledger file", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ledger := NewLedgerStorage(filepath.Join(t.TempDir(), "missing", "ledger.db"))
+
+		_, err := ledger.GetOpenItems(context.Background(), feb1)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+func TestLedgerItem_Key(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	single := LedgerItem{Kind: LedgerBankStatement, BankStatement: BankStatement{Bank: "bca", ID: "1"}}
+	multi := LedgerItem{Kind: LedgerBankStatement, BankStatement: BankStatement{Bank: "bca", Account: "111", ID: "1"}}
+
+	g.Expect(single.Key()).Should(Equal("bank_statement/bca/1"))
+	g.Expect(multi.Key()).Should(Equal("bank_statement/bca/111/1"))
+
+	noID := LedgerItem{Kind: LedgerTransaction, Transaction: Transaction{Amount: 100, Currency: "USD", Type: Debit, Time: time.Date(2025, 1, 31, 17, 0, 0, 0, time.FixedZone("WIB", 7*3600))}}
+	g.Expect(noID.Key()).Should(Equal("transaction/@2025-01-31T10:00:00Z/debit/100/USD"))
+}
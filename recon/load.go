@@ -0,0 +1,94 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// loadedInputs are the input files of a run, bank statement files in the
+// order of their paths whatever order they finished loading in.
+type loadedInputs struct {
+	transactions      []Transaction
+	transactionReport LoadReport
+	statements        [][]BankStatement
+	statementReports  []LoadReport
+}
+
+// loadInputs loads the transaction file and the bank statement files, up to
+// Options.LoadWorkers files at once, timing each file.
+func (r ReconExecutor) loadInputs(ctx context.Context, transactionPath string, bankStatementPaths []string, startDate time.Time, endDate time.Time) (loadedInputs, error) {
+	inputs := loadedInputs{
+		statements:       make([][]BankStatement, len(bankStatementPaths)),
+		statementReports: make([]LoadReport, len(bankStatementPaths)),
+	}
+
+	loads := []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			start := r.now()
+			transactions, report, err := r.transactionStorage.GetTransactions(ctx, transactionPath, startDate, endDate)
+			if err != nil {
+				return fmt.Errorf("get transactions error: %w", err)
+			}
+			report.Duration = r.now().Sub(start)
+			inputs.transactions, inputs.transactionReport = transactions, report
+			return nil
+		},
+	}
+	for i, path := range bankStatementPaths {
+		loads = append(loads, func(ctx context.Context) error {
+			start := r.now()
+			statements, report, err := r.bankStatementRepoStorage.GetBankStatements(ctx, path, startDate, endDate)
+			if err != nil {
+				return fmt.Errorf("get bank statements error: %w", err)
+			}
+			report.Duration = r.now().Sub(start)
+			inputs.statements[i], inputs.statementReports[i] = statements, report
+			return nil
+		})
+	}
+
+	err := runLimited(ctx, r.options.LoadWorkers, loads)
+	if err != nil {
+		return loadedInputs{}, err
+	}
+	return inputs, nil
+}
+
+// runLimited runs tasks in order with up to workers of them at once, one after
+// another when workers is below 2. The first failure cancels the other tasks
+// and no more are started. The error returned is that of the first failed
+// task in order, not counting tasks canceled because of another failure.
+func runLimited(ctx context.Context, workers int, tasks []func(ctx context.Context) error) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(tasks))
+	slots := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		slots <- struct{}{}
+		if runCtx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			errs[i] = task(runCtx)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && (ctx.Err() != nil || !errors.Is(err, context.Canceled)) {
+			return err
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,62 @@
+package recon
+
+import "time"
+
+// LoadReport describes how an input file was loaded.
+type LoadReport struct {
+	Path   string
+	SHA256 string
+	// RowsRead counts the data rows in the file, header excluded.
+	RowsRead int
+	// RowsFiltered counts the rows skipped for falling outside the date range.
+	RowsFiltered int
+	RejectedRows []RejectedRow
+	// Balances holds the running balance of each account in a bank
+	// statement file with a balance column.
+	Balances []RunningBalance
+	// Duration is the time spent loading the file.
+	Duration time.Duration
+}
+
+// Kinds of ConfigInput.
+const (
+	ConfigInputFXRates          = "fx_rates"
+	ConfigInputOverrides        = "overrides"
+	ConfigInputHolidays         = "holidays"
+	ConfigInputDeclaredBalances = "declared_balances"
+)
+
+// ConfigInput is the LoadReport of a file that configures a run instead of
+// being reconciled, e.g. the FX rates. Kind is one of the ConfigInput kinds.
+type ConfigInput struct {
+	Kind string
+	LoadReport
+}
+
+// RowsRejected counts the rows that could not be read as a record.
+func (l LoadReport) RowsRejected() int {
+	return len(l.RejectedRows)
+}
+
+// RowsLoaded counts the rows handed to the recon.
+func (l LoadReport) RowsLoaded() int {
+	return l.RowsRead - l.RowsFiltered - l.RowsRejected()
+}
+
+// RejectedRow is an input row that was skipped because it could not be read
+// as a record.
+type RejectedRow struct {
+	Path   string
+	Line   int
+	Row    []string
+	Reason string
+}
+
+func (l *LoadReport) reject(line int, row []string, reason string) {
+	l.RejectedRows = append(l.RejectedRows, RejectedRow{
+		Path:   l.Path,
+		Line:   line,
+		Row:    row,
+		Reason: reason,
+	})
+}
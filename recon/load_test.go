@@ -0,0 +1,137 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestRunLimited(t *testing.T) {
+	t.Run("runs at most workers tasks at once", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		var running, peak atomic.Int32
+		var tasks []func(context.Context) error
+		for range 8 {
+			tasks = append(tasks, func(context.Context) error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}
+
+		g.Expect(runLimited(context.Background(), 3, tasks)).Should(Succeed())
+		g.Expect(peak.Load()).Should(BeNumerically("<=", 3))
+		g.Expect(peak.Load()).Should(BeNumerically(">", 1))
+	})
+
+	t.Run("stops after the first failure when sequential", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		var ran []int
+		task := func(i int, err error) func(context.Context) error {
+			return func(context.Context) error {
+				ran = append(ran, i)
+				return err
+			}
+		}
+
+		err := runLimited(context.Background(), 0, []func(context.Context) error{
+			task(0, nil), task(1, errors.New("second")), task(2, nil),
+		})
+
+		g.Expect(err).Should(MatchError("second"))
+		g.Expect(ran).Should(Equal([]int{0, 1}))
+	})
+
+	t.Run("returns the failure rather than the tasks it canceled", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		var started sync.WaitGroup
+		started.Add(2)
+		err := runLimited(context.Background(), 2, []func(context.Context) error{
+			func(ctx context.Context) error {
+				started.Done()
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			func(ctx context.Context) error {
+				started.Done()
+				started.Wait()
+				return errors.New("bank file broken")
+			},
+		})
+
+		g.Expect(err).Should(MatchError("bank file broken"))
+	})
+
+	t.Run("returns the error of a canceled context", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := runLimited(ctx, 2, []func(context.Context) error{
+			func(ctx context.Context) error { return ctx.Err() },
+		})
+
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+}
+
+func TestReconExecutor_LoadInputs(t *testing.T) {
+	startDate, _ := time.Parse(time.DateOnly, "2025-08-01")
+	endDate, _ := time.Parse(time.DateOnly, "2025-08-30")
+
+	t.Run("merges files in path order and times them", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		reconExecutor := suite.reconExecutor.WithOptions(Options{LoadWorkers: 3})
+		// every reading of the clock is a second later
+		var ticks atomic.Int64
+		reconExecutor.now = func() time.Time {
+			return reconExecutorRunAt.Add(time.Duration(ticks.Add(1)) * time.Second)
+		}
+
+		bca := []BankStatement{{Bank: "bca", ID: "a", Amount: 100, Time: startDate}}
+		bri := []BankStatement{{Bank: "bri", ID: "b", Amount: 200, Time: startDate}}
+		briLoaded := make(chan struct{})
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), "transaction.csv", startDate, endDate).Return([]Transaction{{ID: "1"}}, LoadReport{Path: "transaction.csv"}, nil)
+		// bca finishes last
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.csv", startDate, endDate).DoAndReturn(func(context.Context, string, time.Time, time.Time) ([]BankStatement, LoadReport, error) {
+			<-briLoaded
+			return bca, LoadReport{Path: "bca.csv"}, nil
+		})
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.csv", startDate, endDate).DoAndReturn(func(context.Context, string, time.Time, time.Time) ([]BankStatement, LoadReport, error) {
+			defer close(briLoaded)
+			return bri, LoadReport{Path: "bri.csv"}, nil
+		})
+
+		inputs, err := reconExecutor.loadInputs(context.Background(), "transaction.csv", []string{"bca.csv", "bri.csv"}, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(inputs.transactions).Should(Equal([]Transaction{{ID: "1"}}))
+		g.Expect(inputs.statements).Should(Equal([][]BankStatement{bca, bri}))
+		g.Expect(inputs.transactionReport.Path).Should(Equal("transaction.csv"))
+		g.Expect(inputs.statementReports[0].Path).Should(Equal("bca.csv"))
+		g.Expect(inputs.statementReports[1].Path).Should(Equal("bri.csv"))
+		g.Expect(inputs.transactionReport.Duration).Should(BeNumerically(">", 0))
+		g.Expect(inputs.statementReports[0].Duration).Should(BeNumerically(">", 0))
+		g.Expect(inputs.statementReports[1].Duration).Should(BeNumerically(">", 0))
+	})
+}
@@ -0,0 +1,73 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ManualStorage lists the manual decisions of a run: forced matches, forced
+// unmatches and exclusions, plus the overrides that did not apply.
+type ManualStorage struct {
+	destinationFileNamePath string
+	destinationSheetName    string
+	excelWriterFactory      ExcelWriterFactory
+}
+
+func NewManualStorage(destinationFileNamePath string, destinationSheetName string, excelWriterFactory ExcelWriterFactory) ManualStorage {
+	return ManualStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		destinationSheetName:    destinationSheetName,
+		excelWriterFactory:      excelWriterFactory,
+	}
+}
+
+func (m ManualStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := m.excelWriterFactory.New(m.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	index, err := f.GetSheetIndex(m.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet index: %w", err)
+	}
+
+	if index == -1 {
+		_, err = f.NewSheet(m.destinationSheetName)
+		if err != nil {
+			return fmt.Errorf("failed to create sheet: %w", err)
+		}
+	}
+
+	rows := [][]any{{"Action", "Status", "Transactions", "Statements", "Amount", "Reason", "Author"}}
+	for _, applied := range result.AppliedOverrides() {
+		rows = append(rows, []any{string(applied.Action), "applied", strings.Join(applied.TransactionIDs, ";"), joinStatementRefs(applied.Statements), applied.Amount(), applied.Reason, applied.Author})
+	}
+	for _, override := range result.UnappliedOverrides {
+		rows = append(rows, []any{string(override.Action), "not applied", strings.Join(override.TransactionIDs, ";"), joinStatementRefs(override.Statements), "", override.Reason, override.Author})
+	}
+
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			f.SetCellValue(m.destinationSheetName, cell, v)
+		}
+	}
+
+	err = f.SaveAs(m.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("save as error: %w", err)
+	}
+	return nil
+}
+
+func joinStatementRefs(refs []StatementRef) string {
+	var values []string
+	for _, ref := range refs {
+		values = append(values, ref.String())
+	}
+	return strings.Join(values, ";")
+}
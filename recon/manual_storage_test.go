@@ -0,0 +1,95 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestManualStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.xlsx"
+	destinationSheetName := "Manual"
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	result := Result{
+		ManualMatches: []AppliedOverride{{
+			Override:       Override{Action: OverrideMatch, TransactionIDs: []string{"1", "2"}, Statements: []StatementRef{{Bank: "BCA", ID: "a"}}, Reason: "split payment", Author: "ops"},
+			Transactions:   []Transaction{{ID: "1", Amount: 100, Time: day}, {ID: "2", Amount: 50, Time: day}},
+			BankStatements: []BankStatement{{Bank: "BCA", ID: "a", Amount: 150, Time: day}},
+		}},
+		Exclusions: []AppliedOverride{{
+			Override:       Override{Action: OverrideExclude, Statements: []StatementRef{{Bank: "BRI", ID: "x"}}, Reason: "bank fee", Author: "ops"},
+			BankStatements: []BankStatement{{Bank: "BRI", ID: "x", Amount: 5, Time: day}},
+		}},
+		UnappliedOverrides: []Override{
+			{Action: OverrideUnmatch, TransactionIDs: []string{"9"}, Reason: "disputed", Author: "ops"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		manualStorage := NewManualStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		cells := map[string]any{
+			"A1": "Action", "B1": "Status", "C1": "Transactions", "D1": "Statements", "E1": "Amount", "F1": "Reason", "G1": "Author",
+			"A2": "match", "B2": "applied", "C2": "1;2", "D2": "BCA:a", "E2": 0.0, "F2": "split payment", "G2": "ops",
+			"A3": "exclude", "B3": "applied", "C3": "", "D3": "BRI:x", "E3": -5.0, "F3": "bank fee", "G3": "ops",
+			"A4": "unmatch", "B4": "not applied", "C4": "9", "D4": "", "E4": "", "F4": "disputed", "G4": "ops",
+		}
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(4, nil)
+		for cell, v := range cells {
+			mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := manualStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		manualStorage := NewManualStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, errors.New("open file error"))
+
+		err := manualStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("save as error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		manualStorage := NewManualStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
+
+		err := manualStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
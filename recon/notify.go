@@ -0,0 +1,415 @@
+package recon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const notifyTimeout = 30 * time.Second
+
+// NotifyRules say which runs notifiers are told about.
+type NotifyRules struct {
+	OnCompletion bool `yaml:"on_completion"`
+	OnFailure    bool `yaml:"on_failure"`
+	// UnmatchedCountAbove and UnmatchedAmountAbove select completed runs
+	// leaving more unmatched items, or a larger unmatched amount, on both sides
+	// together. The unmatched amount adds up the absolute amounts of the
+	// items, so unmatched debits and credits do not cancel out.
+	// UnmatchedAmountAbove is in the reporting currency of the run, which the
+	// amounts are converted to before they are added up. Zero turns them off.
+	UnmatchedCountAbove  int     `yaml:"unmatched_count_above"`
+	UnmatchedAmountAbove float64 `yaml:"unmatched_amount_above"`
+}
+
+// breaches describes the thresholds result exceeds.
+func (n NotifyRules) breaches(result Result, converter currencyConverter) []string {
+	var breaches []string
+	s := result.Summary
+	count := s.UnmatchedTransactions + s.UnmatchedBankStatements
+	if n.UnmatchedCountAbove > 0 && count > n.UnmatchedCountAbove {
+		breaches = append(breaches, fmt.Sprintf("%d unmatched items, above %d", count, n.UnmatchedCountAbove))
+	}
+	if n.UnmatchedAmountAbove > 0 {
+		amount := unmatchedExposure(result, converter)
+		if amount > n.UnmatchedAmountAbove {
+			breaches = append(breaches, fmt.Sprintf("unmatched amount %s, above %s", notifyAmount(amount, s.ReportingCurrency), notifyAmount(n.UnmatchedAmountAbove, s.ReportingCurrency)))
+		}
+	}
+	return breaches
+}
+
+// unmatchedExposure adds up the absolute amounts of the unmatched items of
+// result in the reporting currency. The summary of the run converted each of
+// them already, so converting them again does not fail.
+func unmatchedExposure(result Result, converter currencyConverter) float64 {
+	var total float64
+	for _, t := range result.UnmatchedTransactions {
+		amount, _ := converter.toReporting(t.Amount, t.Currency, t.Time)
+		total += math.Abs(amount)
+	}
+	for _, group := range result.UnmatchedBankStatements {
+		for _, s := range group.Statements {
+			amount, _ := converter.toReporting(s.Amount, s.Currency, s.Time)
+			total += math.Abs(amount)
+		}
+	}
+	return total
+}
+
+// notifyAmount formats amount with currency, when the amounts of the run were
+// converted to one.
+func notifyAmount(amount float64, currency string) string {
+	if currency == "" {
+		return fmt.Sprintf("%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
+
+func (n NotifyRules) selects(notification Notification) bool {
+	if notification.Status == JobFailed {
+		return n.OnFailure
+	}
+	return n.OnCompletion || len(notification.Breaches) > 0
+}
+
+// Notification tells about one run. WebhookNotifier posts it as it is.
+type Notification struct {
+	Status JobStatus  `json:"status"`
+	RunAt  time.Time  `json:"run_at"`
+	Period JSONPeriod `json:"period"`
+	// Summary is left out for failed runs.
+	Summary *JSONSummary `json:"summary,omitempty"`
+	// Breaches are the thresholds of NotifyRules the run exceeded.
+	Breaches []string `json:"breaches,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	// Attachments are the report files of the run. They are left out for
+	// failed runs, whose report files are those of an earlier run.
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+func newNotification(rules NotifyRules, converter currencyConverter, runAt, startDate, endDate time.Time, result Result, err error, attachments []string) Notification {
+	n := Notification{
+		Status: JobSucceeded,
+		RunAt:  runAt,
+		Period: JSONPeriod{
+			StartDate: startDate.Format(time.DateOnly),
+			EndDate:   endDate.Format(time.DateOnly),
+			Timezone:  startDate.Location().String(),
+		},
+	}
+	if err != nil {
+		n.Status, n.Error = JobFailed, err.Error()
+		return n
+	}
+	n.Attachments = attachments
+	summary := NewJSONReport(result).Summary
+	n.Summary = &summary
+	n.Breaches = rules.breaches(result, converter)
+	return n
+}
+
+// Title is a one line account of the run, e.g. "Recon 2025-08-01 to
+// 2025-08-01 failed".
+func (n Notification) Title() string {
+	outcome := "completed"
+	switch {
+	case n.Status == JobFailed:
+		outcome = "failed"
+	case len(n.Breaches) > 0:
+		outcome = "exceeded thresholds"
+	}
+	return fmt.Sprintf("Recon %s to %s %s", n.Period.StartDate, n.Period.EndDate, outcome)
+}
+
+// Lines are the details of the run below the title.
+func (n Notification) Lines() []string {
+	if n.Status == JobFailed {
+		return []string{"Error: " + n.Error}
+	}
+	s := n.Summary
+	lines := []string{
+		fmt.Sprintf("Transactions: %d, %d matched, %d unmatched (%s)", s.Transactions.Count, s.Transactions.MatchedCount, s.Transactions.UnmatchedCount, notifyAmount(s.Transactions.UnmatchedAmount, s.ReportingCurrency)),
+		fmt.Sprintf("Bank statements: %d, %d matched, %d unmatched (%s)", s.BankStatements.Count, s.BankStatements.MatchedCount, s.BankStatements.UnmatchedCount, notifyAmount(s.BankStatements.UnmatchedAmount, s.ReportingCurrency)),
+	}
+	for _, breach := range n.Breaches {
+		lines = append(lines, "Threshold exceeded: "+breach)
+	}
+	return lines
+}
+
+// NotifyConfig lists the notifiers of runs and when they are told.
+type NotifyConfig struct {
+	NotifyRules `yaml:",inline"`
+	// Webhooks get the Notification posted as JSON.
+	Webhooks []string `yaml:"webhooks"`
+	// SlackWebhooks get a Slack message, e.g. Slack incoming webhooks or
+	// chat tools taking their payload.
+	SlackWebhooks []string   `yaml:"slack_webhooks"`
+	Email         SMTPConfig `yaml:"email"`
+}
+
+// Notifiers returns the notifiers config sets up.
+func (c NotifyConfig) Notifiers() []Notifier {
+	var notifiers []Notifier
+	for _, url := range c.Webhooks {
+		notifiers = append(notifiers, NewWebhookNotifier(url, nil))
+	}
+	for _, url := range c.SlackWebhooks {
+		notifiers = append(notifiers, NewSlackNotifier(url, nil))
+	}
+	if c.Email.Addr != "" && len(c.Email.To) > 0 {
+		notifiers = append(notifiers, NewEmailNotifier(c.Email))
+	}
+	return notifiers
+}
+
+// notify tells the notifiers about n if the rules select it.
+func (r ReconExecutor) notify(ctx context.Context, n Notification) error {
+	if !r.notifyRules.selects(n) {
+		return nil
+	}
+	var errs []error
+	for _, notifier := range r.notifiers {
+		err := notifier.Notify(ctx, n)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("notify error: %w", err)
+	}
+	return nil
+}
+
+// WebhookNotifier posts the Notification of a run as JSON.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier posts to url with client, or a client with a timeout
+// when nil.
+func NewWebhookNotifier(url string, client *http.Client) WebhookNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: notifyTimeout}
+	}
+	return WebhookNotifier{url: url, client: client}
+}
+
+func (w WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	return postJSON(ctx, w.client, w.url, notification)
+}
+
+// SlackNotifier posts the outcome of a run as a Slack message.
+type SlackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// NewSlackNotifier posts to the incoming webhook url with client, or a
+// client with a timeout when nil.
+func NewSlackNotifier(url string, client *http.Client) SlackNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: notifyTimeout}
+	}
+	return SlackNotifier{url: url, client: client}
+}
+
+func (s SlackNotifier) Notify(ctx context.Context, notification Notification) error {
+	text := "*" + notification.Title() + "*\n" + strings.Join(notification.Lines(), "\n")
+	return postJSON(ctx, s.client, s.url, slackMessage{Text: text})
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode payload error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	// the URL of a webhook is often its secret, keep it out of the error
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if err != nil {
+		return fmt.Errorf("post error: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post error: answered %s", resp.Status)
+	}
+	return nil
+}
+
+// SMTPConfig is the mail server and the addresses of EmailNotifier.
+type SMTPConfig struct {
+	// Addr is the host and port of the server, e.g. smtp.example.com:587.
+	Addr string `yaml:"addr"`
+	// Username and Password log in when Username is set. The server has to
+	// offer TLS for that, unless it runs on localhost.
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// EmailNotifier mails the outcome of a run with its report files attached.
+type EmailNotifier struct {
+	config SMTPConfig
+	now    func() time.Time
+}
+
+func NewEmailNotifier(config SMTPConfig) EmailNotifier {
+	return EmailNotifier{config: config, now: time.Now}
+}
+
+func (e EmailNotifier) Notify(ctx context.Context, notification Notification) error {
+	msg, err := e.message(notification)
+	if err != nil {
+		return err
+	}
+	err = e.send(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("send mail error: %w", err)
+	}
+	return nil
+}
+
+// send is smtp.SendMail giving up once ctx is done or after notifyTimeout.
+func (e EmailNotifier) send(ctx context.Context, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", e.config.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(e.config.Addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: host})
+		if err != nil {
+			return err
+		}
+	}
+	if e.config.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", e.config.Username, e.config.Password, host))
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(e.config.From)
+	if err != nil {
+		return err
+	}
+	for _, to := range e.config.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message writes the mail of notification. Attachments missing, like a
+// report the run was not set up to write, are left out.
+func (e EmailNotifier) message(notification Notification) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Title()))
+	fmt.Fprintf(&buf, "Date: %s\r\n", e.now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", w.Boundary())
+
+	part, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, fmt.Errorf("write mail error: %w", err)
+	}
+	fmt.Fprintf(part, "%s\r\n\r\n%s\r\n", notification.Title(), strings.Join(notification.Lines(), "\r\n"))
+
+	for _, path := range notification.Attachments {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read attachment error: %w", err)
+		}
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(path)})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("write mail error: %w", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(content)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("write mail error: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,174 @@
+package recon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+var testNotification = Notification{
+	Status: JobSucceeded,
+	RunAt:  time.Date(2025, 8, 2, 6, 0, 0, 0, time.UTC),
+	Period: JSONPeriod{StartDate: "2025-08-01", EndDate: "2025-08-01", Timezone: "UTC"},
+	Summary: &JSONSummary{
+		Transactions:   JSONSideSummary{Count: 3, MatchedCount: 2, UnmatchedCount: 1, UnmatchedAmount: 250},
+		BankStatements: JSONSideSummary{Count: 2, MatchedCount: 2},
+	},
+	Breaches: []string{"unmatched amount 250.00, above 100.00"},
+}
+
+// newHookStandIn records the bodies posted to it and answers with status.
+func newHookStandIn(t *testing.T, status int) (*httptest.Server, chan []byte) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, bodies
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	t.Run("posts the notification as JSON", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		server, bodies := newHookStandIn(t, http.StatusNoContent)
+
+		err := NewWebhookNotifier(server.URL, nil).Notify(context.Background(), testNotification)
+
+		g.Expect(err).Should(BeNil())
+		var posted Notification
+		g.Expect(json.Unmarshal(<-bodies, &posted)).Should(Succeed())
+		g.Expect(posted).Should(Equal(testNotification))
+	})
+
+	t.Run("fails on an error status without telling the URL", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		server, _ := newHookStandIn(t, http.StatusInternalServerError)
+
+		err := NewWebhookNotifier(server.URL+"/secret-token", nil).Notify(context.Background(), testNotification)
+
+		g.Expect(err).Should(MatchError(ContainSubstring("500")))
+		g.Expect(err.Error()).ShouldNot(ContainSubstring("secret-token"))
+	})
+}
+
+func TestSlackNotifier_Notify(t *testing.T) {
+	g := NewGomegaWithT(t)
+	server, bodies := newHookStandIn(t, http.StatusOK)
+
+	err := NewSlackNotifier(server.URL, nil).Notify(context.Background(), testNotification)
+
+	g.Expect(err).Should(BeNil())
+	g.Expect(<-bodies).Should(MatchJSON(`{"text": "*Recon 2025-08-01 to 2025-08-01 exceeded thresholds*\nTransactions: 3, 2 matched, 1 unmatched (250.00)\nBank statements: 2, 2 matched, 0 unmatched (0.00)\nThreshold exceeded: unmatched amount 250.00, above 100.00"}`))
+}
+
+type smtpMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// newSMTPStandIn accepts mails on a local port and hands them over.
+func newSMTPStandIn(t *testing.T) (string, chan smtpMail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	mails := make(chan smtpMail, 1)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				tp := textproto.NewConn(conn)
+				defer tp.Close()
+				var received smtpMail
+				tp.PrintfLine("220 localhost")
+				for {
+					line, err := tp.ReadLine()
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(line)
+					switch {
+					case strings.HasPrefix(command, "MAIL FROM:"):
+						received.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+					case strings.HasPrefix(command, "RCPT TO:"):
+						received.to = append(received.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+					case command == "DATA":
+						tp.PrintfLine("354 go ahead")
+						received.data, _ = tp.ReadDotBytes()
+						mails <- received
+						tp.PrintfLine("250 queued")
+						continue
+					case command == "QUIT":
+						tp.PrintfLine("221 bye")
+						return
+					}
+					tp.PrintfLine("250 ok")
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), mails
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	g := NewGomegaWithT(t)
+	addr, mails := newSMTPStandIn(t)
+	dir := t.TempDir()
+	workbook := filepath.Join(dir, "recon.xlsx")
+	g.Expect(os.WriteFile(workbook, []byte(strings.Repeat("workbook ", 30)), 0o644)).Should(Succeed())
+	notification := testNotification
+	notification.Attachments = []string{workbook, filepath.Join(dir, "recon.json")}
+
+	err := NewEmailNotifier(SMTPConfig{Addr: addr, From: "recon@example.com", To: []string{"finance@example.com", "ops@example.com"}}).Notify(context.Background(), notification)
+
+	g.Expect(err).Should(BeNil())
+	received := <-mails
+	g.Expect(received.from).Should(Equal("recon@example.com"))
+	g.Expect(received.to).Should(Equal([]string{"finance@example.com", "ops@example.com"}))
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(received.data)))
+	g.Expect(err).Should(BeNil())
+	g.Expect(msg.Header.Get("Subject")).Should(Equal("Recon 2025-08-01 to 2025-08-01 exceeded thresholds"))
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	g.Expect(err).Should(BeNil())
+	g.Expect(mediaType).Should(Equal("multipart/mixed"))
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := parts.NextPart()
+	g.Expect(err).Should(BeNil())
+	body, _ := io.ReadAll(text)
+	g.Expect(string(body)).Should(ContainSubstring("Threshold exceeded: unmatched amount 250.00, above 100.00"))
+
+	// the missing JSON report is left out
+	attachment, err := parts.NextPart()
+	g.Expect(err).Should(BeNil())
+	g.Expect(attachment.FileName()).Should(Equal("recon.xlsx"))
+	content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, attachment))
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(content)).Should(Equal(strings.Repeat("workbook ", 30)))
+	_, err = parts.NextPart()
+	g.Expect(err).Should(Equal(io.EOF))
+}
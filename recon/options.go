@@ -0,0 +1,56 @@
+package recon
+
+import (
+	"fmt"
+)
+
+// Options tunes a recon run. The zero value matches on exact amounts.
+type Options struct {
+	// RunArguments are the command line arguments the run was started
+	// with. They are only recorded for provenance.
+	RunArguments []string `yaml:"-"`
+	// ReportingCurrency is the currency Summary totals are converted to.
+	// Items without a currency are taken to be in it. When empty, amounts
+	// are added up as they are and must not mix currencies.
+	ReportingCurrency string `yaml:"reporting_currency"`
+	// LoadWorkers is the number of input files loaded at once. Below 2
+	// files are loaded one after another.
+	LoadWorkers int             `yaml:"load_workers"`
+	Match       MatchConfig     `yaml:"match"`
+	Aging       AgingConfig     `yaml:"aging"`
+	Duplicates  DuplicateConfig `yaml:"duplicates"`
+	Reversals   ReversalConfig  `yaml:"reversals"`
+}
+
+// MatchConfig controls how transactions are paired with bank statements.
+type MatchConfig struct {
+	// FXTolerance is the largest difference, relative to the converted
+	// transaction amount, between a transaction and a bank statement in
+	// another currency that still counts as a match, e.g. 0.01 for 1%.
+	// Zero disables matching across currencies.
+	FXTolerance float64 `yaml:"fx_tolerance"`
+	// SettlementDays is the number of business days after a transaction
+	// within which its bank statement must be dated, e.g. 1 to match a
+	// Friday transaction with a Monday statement. Zero disables the window.
+	SettlementDays int `yaml:"settlement_days"`
+}
+
+// Validate reports the first invalid option.
+func (o Options) Validate() error {
+	if o.LoadWorkers < 0 {
+		return fmt.Errorf("load workers must not be negative: %v", o.LoadWorkers)
+	}
+	if o.Match.FXTolerance < 0 {
+		return fmt.Errorf("fx tolerance must not be negative: %v", o.Match.FXTolerance)
+	}
+	if o.Match.SettlementDays < 0 {
+		return fmt.Errorf("settlement days must not be negative: %v", o.Match.SettlementDays)
+	}
+	if err := o.Duplicates.Validate(); err != nil {
+		return err
+	}
+	if err := o.Reversals.Validate(); err != nil {
+		return err
+	}
+	return o.Aging.Validate()
+}
@@ -0,0 +1,47 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	t.Run("zero value is valid", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Options{}.Validate()).Should(Succeed())
+	})
+
+	t.Run("negative load workers", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Options{LoadWorkers: -1}.Validate()).ShouldNot(Succeed())
+	})
+
+	t.Run("negative FX tolerance", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Options{Match: MatchConfig{FXTolerance: -0.01}}.Validate()).ShouldNot(Succeed())
+	})
+
+	t.Run("negative settlement days", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Options{Match: MatchConfig{SettlementDays: -1}}.Validate()).ShouldNot(Succeed())
+	})
+
+	t.Run("negative reversal window", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Options{Reversals: ReversalConfig{Window: -time.Hour}}.Validate()).ShouldNot(Succeed())
+	})
+
+	t.Run("aging buckets not ascending", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Options{Aging: AgingConfig{Buckets: []int{3, 3}}}.Validate()).ShouldNot(Succeed())
+		g.Expect(Options{Aging: AgingConfig{Buckets: []int{-1}}}.Validate()).ShouldNot(Succeed())
+	})
+}
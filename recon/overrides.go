@@ -0,0 +1,176 @@
+package recon
+
+import (
+	"fmt"
+)
+
+type OverrideAction string
+
+const (
+	// OverrideMatch pairs the listed transactions with the listed bank statements.
+	OverrideMatch OverrideAction = "match"
+	// OverrideUnmatch keeps the listed items out of automatic matching.
+	OverrideUnmatch OverrideAction = "unmatch"
+	// OverrideExclude removes the listed items from the recon altogether.
+	OverrideExclude OverrideAction = "exclude"
+)
+
+// StatementRef identifies a bank statement by bank, account and statement ID.
+// Account is empty for banks with a single account.
+type StatementRef struct {
+	Bank    string `yaml:"bank"`
+	Account string `yaml:"account,omitempty"`
+	ID      string `yaml:"id"`
+}
+
+// String writes the reference as bank:id, or bank/account:id.
+func (s StatementRef) String() string {
+	if s.Account == "" {
+		return s.Bank + ":" + s.ID
+	}
+	return s.Bank + "/" + s.Account + ":" + s.ID
+}
+
+// Override is a decision taken by a person that automatic matching must respect.
+type Override struct {
+	Action         OverrideAction `yaml:"action"`
+	TransactionIDs []string       `yaml:"transactions"`
+	Statements     []StatementRef `yaml:"statements"`
+	Reason         string         `yaml:"reason"`
+	Author         string         `yaml:"author"`
+}
+
+func (o Override) Validate() error {
+	switch o.Action {
+	case OverrideMatch:
+		if len(o.TransactionIDs) == 0 || len(o.Statements) == 0 {
+			return fmt.Errorf("match override needs at least one transaction and one statement")
+		}
+	case OverrideUnmatch, OverrideExclude:
+		if len(o.TransactionIDs) == 0 && len(o.Statements) == 0 {
+			return fmt.Errorf("%s override needs at least one transaction or statement", o.Action)
+		}
+	default:
+		return fmt.Errorf("unknown override action %q", o.Action)
+	}
+	if o.Reason == "" || o.Author == "" {
+		return fmt.Errorf("%s override needs a reason and an author", o.Action)
+	}
+	return nil
+}
+
+// AppliedOverride is an override together with the items it applied to.
+type AppliedOverride struct {
+	Override
+	Transactions   []Transaction
+	BankStatements []BankStatement
+}
+
+// Amount is the transaction total minus the bank statement total.
+func (a AppliedOverride) Amount() float64 {
+	var amount float64
+	for _, t := range a.Transactions {
+		amount += t.Amount
+	}
+	for _, s := range a.BankStatements {
+		amount -= s.Amount
+	}
+	return amount
+}
+
+// overrideOutcome splits the loaded items by the overrides that apply to them.
+type overrideOutcome struct {
+	// transactions and statements are left for automatic matching.
+	transactions []Transaction
+	statements   []BankStatement
+
+	matches    []AppliedOverride
+	unmatches  []AppliedOverride
+	exclusions []AppliedOverride
+	// unapplied overrides reference items that are not part of the run.
+	unapplied []Override
+}
+
+// applyOverrides resolves each override against the loaded items. An override
+// applies only when every item it lists is present and not claimed by an
+// earlier override, so override files can span several periods.
+func applyOverrides(overrides []Override, transactions []Transaction, statements []BankStatement) overrideOutcome {
+	transactionIndex := map[string][]int{}
+	for i, t := range transactions {
+		transactionIndex[t.ID] = append(transactionIndex[t.ID], i)
+	}
+	statementIndex := map[StatementRef][]int{}
+	for i, s := range statements {
+		ref := StatementRef{Bank: s.Bank, Account: s.Account, ID: s.ID}
+		statementIndex[ref] = append(statementIndex[ref], i)
+	}
+	claimedTransactions := make([]bool, len(transactions))
+	claimedStatements := make([]bool, len(statements))
+
+	var outcome overrideOutcome
+	for _, override := range overrides {
+		transactionIndexes, ok := claim(override.TransactionIDs, transactionIndex, claimedTransactions)
+		if !ok {
+			outcome.unapplied = append(outcome.unapplied, override)
+			continue
+		}
+		statementIndexes, ok := claim(override.Statements, statementIndex, claimedStatements)
+		if !ok {
+			outcome.unapplied = append(outcome.unapplied, override)
+			continue
+		}
+
+		applied := AppliedOverride{Override: override}
+		for _, i := range transactionIndexes {
+			claimedTransactions[i] = true
+			applied.Transactions = append(applied.Transactions, transactions[i])
+		}
+		for _, i := range statementIndexes {
+			claimedStatements[i] = true
+			applied.BankStatements = append(applied.BankStatements, statements[i])
+		}
+
+		switch override.Action {
+		case OverrideMatch:
+			outcome.matches = append(outcome.matches, applied)
+		case OverrideUnmatch:
+			outcome.unmatches = append(outcome.unmatches, applied)
+		case OverrideExclude:
+			outcome.exclusions = append(outcome.exclusions, applied)
+		}
+	}
+
+	for i, t := range transactions {
+		if !claimedTransactions[i] {
+			outcome.transactions = append(outcome.transactions, t)
+		}
+	}
+	for i, s := range statements {
+		if !claimedStatements[i] {
+			outcome.statements = append(outcome.statements, s)
+		}
+	}
+	return outcome
+}
+
+// claim finds an unclaimed item for every key, without claiming anything
+// when one of them is missing.
+func claim[K comparable](keys []K, index map[K][]int, claimed []bool) ([]int, bool) {
+	var indexes []int
+	taken := map[int]bool{}
+	for _, key := range keys {
+		found := false
+		for _, i := range index[key] {
+			if !claimed[i] && !taken[i] {
+				indexes = append(indexes, i)
+				taken[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return indexes, true
+}
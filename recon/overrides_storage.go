@@ -0,0 +1,120 @@
+package recon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// OverridesStorage reads manual overrides from a YAML file (.yaml, .yml) or
+// from a CSV file with the columns action, transactions, statements, reason
+// and author. In CSV, transactions are separated by ";" and statements are
+// written as bank:id or bank/account:id, also separated by ";".
+type OverridesStorage struct {
+	readerFactory ReaderFactory
+}
+
+func NewOverridesStorage(readerFactory ReaderFactory) OverridesStorage {
+	return OverridesStorage{readerFactory: readerFactory}
+}
+
+// GetOverrides reads the overrides of filename. The LoadReport has the
+// checksum of the file so runs can tell which decisions they applied.
+func (o OverridesStorage) GetOverrides(ctx context.Context, filename string) ([]Override, LoadReport, error) {
+	report := LoadReport{Path: filename}
+	var overrides []Override
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		overrides, report.SHA256, err = o.readYAML(ctx, filename)
+	default:
+		overrides, report.SHA256, err = o.readCSV(ctx, filename)
+	}
+	if err != nil {
+		return nil, report, err
+	}
+	report.RowsRead = len(overrides)
+
+	for i, override := range overrides {
+		if err := override.Validate(); err != nil {
+			return nil, report, fmt.Errorf("invalid override %d: %w", i+1, err)
+		}
+	}
+	return overrides, report, nil
+}
+
+func (o OverridesStorage) readYAML(ctx context.Context, filename string) ([]Override, string, error) {
+	file, err := o.readerFactory.Open(ctx, filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+	checksum := sha256.Sum256(content)
+	var overrides []Override
+	err = yaml.Unmarshal(content, &overrides)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return overrides, hex.EncodeToString(checksum[:]), nil
+}
+
+func (o OverridesStorage) readCSV(ctx context.Context, filename string) ([]Override, string, error) {
+	reader, err := o.readerFactory.NewReader(ctx, filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer reader.Close()
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var overrides []Override
+	for i, row := range records {
+		if i == 0 { // skip header
+			continue
+		}
+		if len(row) < 5 {
+			return nil, "", fmt.Errorf("missing columns in row: %v", row)
+		}
+
+		override := Override{
+			Action:         OverrideAction(strings.TrimSpace(row[0])),
+			TransactionIDs: splitList(row[1]),
+			Reason:         strings.TrimSpace(row[3]),
+			Author:         strings.TrimSpace(row[4]),
+		}
+		for _, ref := range splitList(row[2]) {
+			bankAccount, id, ok := strings.Cut(ref, ":")
+			if !ok {
+				return nil, "", fmt.Errorf("invalid statement %q in row: %v", ref, row)
+			}
+			bank, account, _ := strings.Cut(bankAccount, "/")
+			override.Statements = append(override.Statements, StatementRef{Bank: bank, Account: account, ID: id})
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, reader.Checksum(), nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
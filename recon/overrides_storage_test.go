@@ -0,0 +1,131 @@
+package recon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestOverridesStorage_GetOverrides(t *testing.T) {
+	t.Run("should read overrides from csv", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+		storage := NewOverridesStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "overrides.csv").Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"action", "transactions", "statements", "reason", "author"},
+			{"match", "1; 2", "BCA:a", "split payment", "ops"},
+			{"exclude", "", "BRI:x;BRI/222:y", "bank fee", "ops"},
+		}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		overrides, report, err := storage.GetOverrides(context.Background(), "overrides.csv")
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(report).Should(Equal(LoadReport{Path: "overrides.csv", SHA256: "checksum", RowsRead: 2}))
+		g.Expect(overrides).Should(Equal([]Override{
+			{Action: OverrideMatch, TransactionIDs: []string{"1", "2"}, Statements: []StatementRef{{Bank: "BCA", ID: "a"}}, Reason: "split payment", Author: "ops"},
+			{Action: OverrideExclude, Statements: []StatementRef{{Bank: "BRI", ID: "x"}, {Bank: "BRI", Account: "222", ID: "y"}}, Reason: "bank fee", Author: "ops"},
+		}))
+	})
+
+	t.Run("should read overrides from yaml", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		path := filepath.Join(t.TempDir(), "overrides.yaml")
+		content := `
+- action: unmatch
+  transactions: ["3"]
+  reason: disputed
+  author: ops
+- action: match
+  transactions: ["1"]
+  statements:
+    - {bank: BCA, id: a}
+  reason: late posting
+  author: ops
+`
+		g.Expect(os.WriteFile(path, []byte(content), 0o644)).Should(Succeed())
+
+		overrides, report, err := NewOverridesStorage(CSVReaderFactory{}).GetOverrides(context.Background(), path)
+
+		g.Expect(err).Should(BeNil())
+		sum := sha256.Sum256([]byte(content))
+		g.Expect(report).Should(Equal(LoadReport{Path: path, SHA256: hex.EncodeToString(sum[:]), RowsRead: 2}))
+		g.Expect(overrides).Should(Equal([]Override{
+			{Action: OverrideUnmatch, TransactionIDs: []string{"3"}, Reason: "disputed", Author: "ops"},
+			{Action: OverrideMatch, TransactionIDs: []string{"1"}, Statements: []StatementRef{{Bank: "BCA", ID: "a"}}, Reason: "late posting", Author: "ops"},
+		}))
+	})
+
+	t.Run("should return error when an override is invalid", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+		storage := NewOverridesStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "overrides.csv").Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"action", "transactions", "statements", "reason", "author"},
+			{"exclude", "1", "", "", "ops"},
+		}, nil)
+		mockReader.EXPECT().Checksum().Return("checksum")
+		mockReader.EXPECT().Close().Return(nil)
+
+		_, _, err := storage.GetOverrides(context.Background(), "overrides.csv")
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when a statement reference is malformed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		mockReader := NewMockReader(ctrl)
+		storage := NewOverridesStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "overrides.csv").Return(mockReader, nil)
+		mockReader.EXPECT().ReadAll().Return([][]string{
+			{"action", "transactions", "statements", "reason", "author"},
+			{"exclude", "", "a", "fee", "ops"},
+		}, nil)
+		mockReader.EXPECT().Close().Return(nil)
+
+		_, _, err := storage.GetOverrides(context.Background(), "overrides.csv")
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when file cannot be opened", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockReaderFactory := NewMockReaderFactory(ctrl)
+		storage := NewOverridesStorage(mockReaderFactory)
+
+		mockReaderFactory.EXPECT().NewReader(gomock.Any(), "overrides.csv").Return(nil, fmt.Errorf("open error"))
+
+		_, _, err := storage.GetOverrides(context.Background(), "overrides.csv")
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
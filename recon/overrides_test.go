@@ -0,0 +1,94 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestOverride_Validate(t *testing.T) {
+	statements := []StatementRef{{Bank: "BCA", ID: "a"}}
+	tests := []struct {
+		name     string
+		override Override
+		valid    bool
+	}{
+		{"match", Override{Action: OverrideMatch, TransactionIDs: []string{"1"}, Statements: statements, Reason: "r", Author: "a"}, true},
+		{"match without statements", Override{Action: OverrideMatch, TransactionIDs: []string{"1"}, Reason: "r", Author: "a"}, false},
+		{"unmatch statement only", Override{Action: OverrideUnmatch, Statements: statements, Reason: "r", Author: "a"}, true},
+		{"exclude without items", Override{Action: OverrideExclude, Reason: "r", Author: "a"}, false},
+		{"missing reason", Override{Action: OverrideExclude, TransactionIDs: []string{"1"}, Author: "a"}, false},
+		{"missing author", Override{Action: OverrideExclude, TransactionIDs: []string{"1"}, Reason: "r"}, false},
+		{"unknown action", Override{Action: "merge", TransactionIDs: []string{"1"}, Reason: "r", Author: "a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := tt.override.Validate()
+			if tt.valid {
+				g.Expect(err).Should(BeNil())
+			} else {
+				g.Expect(err).ShouldNot(BeNil())
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+	transactions := []Transaction{
+		{ID: "1", Amount: 100, Type: Credit, Time: day},
+		{ID: "2", Amount: 50, Type: Credit, Time: day},
+		{ID: "3", Amount: 70, Type: Debit, Time: day},
+		{ID: "4", Amount: 10, Type: Debit, Time: day},
+	}
+	statements := []BankStatement{
+		{Bank: "BCA", ID: "a", Amount: 150, Time: day},
+		{Bank: "BCA", ID: "b", Amount: 70, Time: day},
+		{Bank: "BRI", ID: "a", Amount: 5, Time: day},
+	}
+
+	t.Run("should split items by action and leave the rest for matching", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		overrides := []Override{
+			{Action: OverrideMatch, TransactionIDs: []string{"1", "2"}, Statements: []StatementRef{{Bank: "BCA", ID: "a"}}, Reason: "split payment", Author: "ops"},
+			{Action: OverrideUnmatch, TransactionIDs: []string{"3"}, Reason: "disputed", Author: "ops"},
+			{Action: OverrideExclude, Statements: []StatementRef{{Bank: "BRI", ID: "a"}}, Reason: "bank fee", Author: "ops"},
+		}
+
+		outcome := applyOverrides(overrides, transactions, statements)
+
+		g.Expect(outcome.matches).Should(Equal([]AppliedOverride{
+			{Override: overrides[0], Transactions: transactions[:2], BankStatements: statements[:1]},
+		}))
+		g.Expect(outcome.unmatches).Should(Equal([]AppliedOverride{
+			{Override: overrides[1], Transactions: transactions[2:3]},
+		}))
+		g.Expect(outcome.exclusions).Should(Equal([]AppliedOverride{
+			{Override: overrides[2], BankStatements: statements[2:]},
+		}))
+		g.Expect(outcome.unapplied).Should(BeEmpty())
+		g.Expect(outcome.transactions).Should(Equal(transactions[3:]))
+		g.Expect(outcome.statements).Should(Equal(statements[1:2]))
+	})
+
+	t.Run("should leave overrides unapplied when items are missing or already claimed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		overrides := []Override{
+			{Action: OverrideExclude, TransactionIDs: []string{"1"}, Reason: "test", Author: "ops"},
+			{Action: OverrideMatch, TransactionIDs: []string{"1"}, Statements: []StatementRef{{Bank: "BCA", ID: "a"}}, Reason: "late", Author: "ops"},
+			{Action: OverrideMatch, TransactionIDs: []string{"2"}, Statements: []StatementRef{{Bank: "BCA", ID: "z"}}, Reason: "late", Author: "ops"},
+		}
+
+		outcome := applyOverrides(overrides, transactions, statements)
+
+		g.Expect(outcome.exclusions).Should(HaveLen(1))
+		g.Expect(outcome.matches).Should(BeEmpty())
+		g.Expect(outcome.unapplied).Should(Equal(overrides[1:]))
+		g.Expect(outcome.transactions).Should(Equal(transactions[1:]))
+		g.Expect(outcome.statements).Should(Equal(statements))
+	})
+}
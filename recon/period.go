@@ -0,0 +1,60 @@
+package recon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Period is the half-open range [Start, End) of instants that fall on the
+// business days of a recon.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewPeriod covers the days from startDate to endDate, both inclusive, in the
+// location of startDate. A day ends at cutoff, the time of day after which a
+// bank books lines on the next day; zero means midnight.
+func NewPeriod(startDate, endDate time.Time, cutoff time.Duration) Period {
+	if cutoff <= 0 {
+		cutoff = 24 * time.Hour
+	}
+	location := startDate.Location()
+	y, m, d := startDate.Date()
+	start := time.Date(y, m, d-1, 0, 0, 0, 0, location).Add(cutoff)
+	y, m, d = endDate.In(location).Date()
+	end := time.Date(y, m, d, 0, 0, 0, 0, location).Add(cutoff)
+	return Period{Start: start, End: end}
+}
+
+// Contains reports whether t is at or after Start and before End.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// formatCutoffs writes cutoffs as bank=HH:MM entries sorted by bank, the
+// way -bank-cutoffs takes them.
+func formatCutoffs(cutoffs map[string]time.Duration) string {
+	entries := make([]string, 0, len(cutoffs))
+	for bank, cutoff := range cutoffs {
+		entries = append(entries, bank+"="+formatCutoff(cutoff))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// formatCutoff writes cutoff as HH:MM.
+func formatCutoff(cutoff time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(cutoff.Hours()), int(cutoff.Minutes())%60)
+}
+
+// ParseCutoff reads a cutoff time of day written as HH:MM, e.g. 22:00.
+func ParseCutoff(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid cutoff %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,65 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPeriod_Contains(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	startDate := time.Date(2025, 8, 1, 0, 0, 0, 0, jakarta)
+	endDate := time.Date(2025, 8, 2, 0, 0, 0, 0, jakarta)
+
+	t.Run("covers whole days in the business time zone", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		period := NewPeriod(startDate, endDate, 0)
+
+		g.Expect(period.Contains(time.Date(2025, 7, 31, 16, 59, 59, 0, time.UTC))).Should(BeFalse())
+		g.Expect(period.Contains(time.Date(2025, 7, 31, 17, 0, 0, 0, time.UTC))).Should(BeTrue())
+		g.Expect(period.Contains(time.Date(2025, 8, 2, 23, 59, 59, 999999999, jakarta))).Should(BeTrue())
+		g.Expect(period.Contains(time.Date(2025, 8, 2, 17, 0, 0, 0, time.UTC))).Should(BeFalse())
+	})
+
+	t.Run("excludes midnight after the end date", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		period := NewPeriod(startDate, endDate, 0)
+
+		g.Expect(period.Contains(time.Date(2025, 8, 3, 0, 0, 0, 0, jakarta))).Should(BeFalse())
+		g.Expect(period.End).Should(Equal(time.Date(2025, 8, 3, 0, 0, 0, 0, jakarta)))
+	})
+
+	t.Run("ends days at the cutoff", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		period := NewPeriod(startDate, endDate, 22*time.Hour)
+
+		g.Expect(period.Contains(time.Date(2025, 7, 31, 21, 59, 59, 0, jakarta))).Should(BeFalse())
+		g.Expect(period.Contains(time.Date(2025, 7, 31, 22, 0, 0, 0, jakarta))).Should(BeTrue())
+		g.Expect(period.Contains(time.Date(2025, 8, 2, 21, 59, 59, 0, jakarta))).Should(BeTrue())
+		g.Expect(period.Contains(time.Date(2025, 8, 2, 22, 0, 0, 0, jakarta))).Should(BeFalse())
+	})
+
+	t.Run("single day", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		period := NewPeriod(startDate, startDate, 0)
+
+		g.Expect(period.Contains(startDate)).Should(BeTrue())
+		g.Expect(period.Contains(startDate.Add(24 * time.Hour))).Should(BeFalse())
+	})
+}
+
+func TestParseCutoff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cutoff, err := ParseCutoff("22:30")
+	g.Expect(err).Should(BeNil())
+	g.Expect(cutoff).Should(Equal(22*time.Hour + 30*time.Minute))
+
+	_, err = ParseCutoff("10pm")
+	g.Expect(err).ShouldNot(BeNil())
+}
@@ -1,101 +1,511 @@
 package recon
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"log"
+	"slices"
 	"time"
-
-	"github.com/samber/lo"
 )
 
-type bankStatementDisrepancyGroup struct {
-	Statements []BankStatement
-}
-
-func (b *bankStatementDisrepancyGroup) Add(statement BankStatement) {
-	b.Statements = append(b.Statements, statement)
+// Match is a transaction paired with the bank statement that settles it.
+type Match struct {
+	Transaction   Transaction
+	BankStatement BankStatement
+	// FXDifference is the part of the difference between both sides, in the
+	// reporting currency, that comes from exchange rates.
+	FXDifference float64
 }
 
 type ReconExecutor struct {
 	transactionStorage       TransactionStorageProvider
 	bankStatementRepoStorage BankStatementStorageProvider
 	summaryRepoStorage       SummaryStorageProvider
+	reportRepoStorages       []ReportStorageProvider
+	ledger                   LedgerProvider
+	overrides                []Override
+	declaredBalances         []DeclaredBalance
+	fxRates                  FXRates
+	calendar                 Calendar
+	cutoffs                  map[string]time.Duration
+	configInputs             []ConfigInput
+	options                  Options
+	notifyRules              NotifyRules
+	notifiers                []Notifier
+	attachments              []string
+	notifyLogger             *log.Logger
+
+	now func() time.Time
 }
 
-func NewReconExecutor(transactionRepo TransactionStorageProvider, bankStatementRepo BankStatementStorageProvider, summaryRepo SummaryStorageProvider) ReconExecutor {
+func NewReconExecutor(transactionRepo TransactionStorageProvider, bankStatementRepo BankStatementStorageProvider, summaryRepo SummaryStorageProvider, reportRepos ...ReportStorageProvider) ReconExecutor {
 	return ReconExecutor{
 		transactionStorage:       transactionRepo,
 		bankStatementRepoStorage: bankStatementRepo,
 		summaryRepoStorage:       summaryRepo,
+		reportRepoStorages:       reportRepos,
+		now:                      time.Now,
 	}
 }
 
-func (r ReconExecutor) Execute(transactionPath string, bankStatementPathArray []string, startDate time.Time, endDate time.Time) error {
-	transactions, err := r.transactionStorage.GetTransactions(transactionPath, startDate, endDate)
+// WithOptions returns a copy of the executor that runs with options.
+func (r ReconExecutor) WithOptions(options Options) ReconExecutor {
+	r.options = options
+	return r
+}
+
+// WithLedger returns a copy of the executor that carries unmatched items
+// forward through ledger: open items from before the period take part in the
+// matching, and the ledger is updated with the outcome.
+func (r ReconExecutor) WithLedger(ledger LedgerProvider) ReconExecutor {
+	r.ledger = ledger
+	return r
+}
+
+// WithOverrides returns a copy of the executor that applies overrides
+// before automatic matching.
+func (r ReconExecutor) WithOverrides(overrides []Override) ReconExecutor {
+	r.overrides = overrides
+	return r
+}
+
+// WithDeclaredBalances returns a copy of the executor that checks the bank
+// statement lines against opening and closing balances declared per account.
+// Accounts without a declared balance are checked against their running
+// balance column, if any.
+func (r ReconExecutor) WithDeclaredBalances(balances []DeclaredBalance) ReconExecutor {
+	r.declaredBalances = balances
+	return r
+}
+
+// WithFXRates returns a copy of the executor that converts amounts with
+// rates, to the reporting currency and between currencies when matching.
+func (r ReconExecutor) WithFXRates(rates FXRates) ReconExecutor {
+	r.fxRates = rates
+	return r
+}
+
+// WithCalendar returns a copy of the executor that counts settlement windows
+// and aging in business days of calendar instead of calendar days.
+func (r ReconExecutor) WithCalendar(calendar Calendar) ReconExecutor {
+	r.calendar = calendar
+	return r
+}
+
+// WithConfigInputs returns a copy of the executor that records inputs, the
+// files its overrides, balances, rates and calendar were read from, with its
+// runs.
+func (r ReconExecutor) WithConfigInputs(inputs ...ConfigInput) ReconExecutor {
+	r.configInputs = inputs
+	return r
+}
+
+// WithCutoffs returns a copy of the executor that knows the cutoff times of
+// day the bank statement storage ends the business day of banks at. They are
+// recorded with its runs, and ledger lines of a bank booked after its cutoff
+// on the day before the period are left to the period instead of carried
+// forward.
+func (r ReconExecutor) WithCutoffs(cutoffs map[string]time.Duration) ReconExecutor {
+	r.cutoffs = cutoffs
+	return r
+}
+
+// WithNotifiers returns a copy of the executor that tells notifiers about
+// the runs rules select. attachments are the report files of a run, attached
+// by notifiers that send files when the run succeeded. Notifiers that fail are logged to logger and
+// leave the outcome of the run as it is.
+func (r ReconExecutor) WithNotifiers(rules NotifyRules, attachments []string, logger *log.Logger, notifiers ...Notifier) ReconExecutor {
+	r.notifyRules = rules
+	r.attachments = attachments
+	r.notifyLogger = logger
+	r.notifiers = notifiers
+	return r
+}
+
+// Execute runs the recon of the period from startDate to endDate: it loads
+// the input files, reconciles them and stores the Result. Once ctx is done it
+// stops with the error of ctx; nothing is stored when that happens before the
+// matching finished.
+func (r ReconExecutor) Execute(ctx context.Context, transactionPath string, bankStatementPathArray []string, startDate time.Time, endDate time.Time) error {
+	runAt := r.now()
+	result, err := r.execute(ctx, runAt, transactionPath, bankStatementPathArray, startDate, endDate)
+	if len(r.notifiers) == 0 || ctx.Err() != nil {
+		return err
+	}
+	converter := currencyConverter{rates: r.fxRates, reporting: r.options.ReportingCurrency}
+	notifyErr := r.notify(ctx, newNotification(r.notifyRules, converter, runAt, startDate, endDate, result, err, r.attachments))
+	if notifyErr != nil {
+		r.notifyLogger.Printf("Recon %s to %s: %v", startDate.Format(time.DateOnly), endDate.Format(time.DateOnly), notifyErr)
+	}
+	return err
+}
+
+func (r ReconExecutor) execute(ctx context.Context, runAt time.Time, transactionPath string, bankStatementPathArray []string, startDate time.Time, endDate time.Time) (Result, error) {
+	err := r.options.Validate()
 	if err != nil {
-		return fmt.Errorf("get transactions error: %w", err)
+		return Result{}, fmt.Errorf("invalid options: %w", err)
 	}
 
-	total := Summary{TotalProcessed: len(transactions)}
+	var carriedForward []LedgerItem
+	if r.ledger != nil {
+		carriedForward, err = r.ledger.GetOpenItems(ctx, startDate)
+		if err != nil {
+			return Result{}, fmt.Errorf("get open ledger items error: %w", err)
+		}
+		// the period of a bank with a cutoff starts at the cutoff of the day
+		// before, so its later lines are loaded again with the period
+		carriedForward = slices.DeleteFunc(carriedForward, func(item LedgerItem) bool {
+			if item.Kind != LedgerBankStatement {
+				return false
+			}
+			period := NewPeriod(startDate, endDate, r.cutoffs[item.BankStatement.Bank])
+			return !item.Time().Before(period.Start)
+		})
+	}
 
-	bankStatementMap := map[float64]*BankStatementGroup{}
-	for _, path := range bankStatementPathArray {
-		statements, err := r.bankStatementRepoStorage.GetBankStatements(path, startDate, endDate)
+	inputs, err := r.loadInputs(ctx, transactionPath, bankStatementPathArray, startDate, endDate)
+	if err != nil {
+		return Result{}, err
+	}
+	in := reconInput{
+		runAt:               runAt,
+		startDate:           startDate,
+		endDate:             endDate,
+		carriedForward:      carriedForward,
+		transactions:        inputs.transactions,
+		transactionInput:    inputs.transactionReport,
+		bankStatementInputs: inputs.statementReports,
+	}
+	for i, loaded := range inputs.statements {
+		in.statements = append(in.statements, loaded...)
+		in.runningBalances = append(in.runningBalances, inputs.statementReports[i].Balances...)
+	}
+
+	result, err := r.reconcile(ctx, in)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// nothing is stored for a run canceled before this point
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	return result, r.store(ctx, result)
+}
+
+// store persists result through the storages and updates the ledger.
+func (r ReconExecutor) store(ctx context.Context, result Result) error {
+	err := r.summaryRepoStorage.StoreSummary(ctx, result.Summary)
+	if err != nil {
+		return fmt.Errorf("store summary error: %w", err)
+	}
+
+	err = r.transactionStorage.StoreTransactions(ctx, result.UnmatchedTransactions)
+	if err != nil {
+		return fmt.Errorf("store transactions error: %w", err)
+	}
+
+	for _, group := range result.UnmatchedBankStatements {
+		err = r.bankStatementRepoStorage.StoreBankStatements(ctx, group.Statements, group.BankAccount().String())
 		if err != nil {
-			return fmt.Errorf("get bank statements error: %w", err)
+			return fmt.Errorf("store bank statements error: %w", err)
 		}
+	}
 
-		for _, statement := range statements {
-			if _, ok := bankStatementMap[statement.Amount]; !ok {
-				bankStatementMap[statement.Amount] = &BankStatementGroup{}
-			}
-			bankStatementMap[statement.Amount].Add(statement)
-			total.TotalAmountBankStatements += statement.Amount
+	for _, reportRepo := range r.reportRepoStorages {
+		err = reportRepo.StoreReport(ctx, result)
+		if err != nil {
+			return fmt.Errorf("store report error: %w", err)
 		}
 	}
 
-	transactionDiscrepancies := lo.Filter(transactions, func(t Transaction, i int) bool {
-		total.TotalAmountTransactions += t.Amount
-		bankStatements := bankStatementMap[t.Amount]
-		if bankStatements != nil && len(bankStatements.BankStatements) > 0 {
-			bankStatementMap[t.Amount].Shift()
-			total.TotalMatched++
-			// remove the transaction if it has a matching bank statement
-			return false
+	if r.ledger != nil {
+		err = r.ledger.UpdateLedger(ctx, result)
+		if err != nil {
+			return fmt.Errorf("update ledger error: %w", err)
 		}
-		total.TotalUnmatched++
-		return true
+	}
+
+	return nil
+}
+
+// Reconcile matches transactions with statements in memory and returns the
+// outcome, without loading or storing anything. The period of the Result
+// spans the days of the earliest and the latest item.
+//
+// Overrides, declared balances, FX rates and a calendar are set on a
+// ReconExecutor, whose Reconcile method uses them the same way.
+func Reconcile(transactions []Transaction, statements []BankStatement, options Options) (Result, error) {
+	return NewReconExecutor(nil, nil, nil).WithOptions(options).Reconcile(transactions, statements)
+}
+
+// Reconcile matches transactions with statements in memory with the options,
+// overrides, declared balances, FX rates and calendar of the executor. Its
+// storages and ledger are not used. The period of the Result spans the days
+// of the earliest and the latest item.
+func (r ReconExecutor) Reconcile(transactions []Transaction, statements []BankStatement) (Result, error) {
+	err := r.options.Validate()
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid options: %w", err)
+	}
+
+	startDate, endDate := itemPeriod(transactions, statements)
+	return r.reconcile(context.Background(), reconInput{
+		runAt:        r.now(),
+		startDate:    startDate,
+		endDate:      endDate,
+		transactions: transactions,
+		statements:   statements,
 	})
+}
 
-	bankStatementDisrepancy := map[string]*bankStatementDisrepancyGroup{}
-	for _, group := range bankStatementMap {
-		if len(group.BankStatements) > 0 {
-			for _, statement := range group.BankStatements {
-				if _, ok := bankStatementDisrepancy[statement.Bank]; !ok {
-					bankStatementDisrepancy[statement.Bank] = &bankStatementDisrepancyGroup{}
-				}
-				bankStatementDisrepancy[statement.Bank].Add(statement)
-				total.TotalProcessed++
-				total.TotalUnmatched++
-			}
+// reconInput is what a run reconciles: the loaded items of the period, plus
+// the open ledger items carried forward from before it.
+type reconInput struct {
+	runAt     time.Time
+	startDate time.Time
+	endDate   time.Time
+
+	carriedForward      []LedgerItem
+	transactions        []Transaction
+	statements          []BankStatement
+	runningBalances     []RunningBalance
+	transactionInput    LoadReport
+	bankStatementInputs []LoadReport
+}
+
+// reconcile matches the items of in and summarizes the outcome. The items
+// of in are left as they are.
+func (r ReconExecutor) reconcile(ctx context.Context, in reconInput) (Result, error) {
+	// older items go first so they are matched before newer ones
+	var transactions []Transaction
+	var statements []BankStatement
+	for _, item := range in.carriedForward {
+		switch item.Kind {
+		case LedgerTransaction:
+			transactions = append(transactions, item.Transaction)
+		case LedgerBankStatement:
+			statements = append(statements, item.BankStatement)
 		}
 	}
+	transactions = append(transactions, in.transactions...)
+	statements = append(statements, in.statements...)
+	balanceChecks := checkBalances(in.statements, in.runningBalances, r.declaredBalances)
 
-	err = r.summaryRepoStorage.StoreSummary(total)
+	duplicates, transactions, statements, err := r.options.Duplicates.apply(transactions, statements)
 	if err != nil {
-		return fmt.Errorf("store summary error: %w", err)
+		return Result{}, fmt.Errorf("duplicates error: %w", err)
 	}
 
-	err = r.transactionStorage.StoreTransactions(transactionDiscrepancies)
+	converter := currencyConverter{rates: r.fxRates, reporting: r.options.ReportingCurrency}
+	if converter.reporting != "" {
+		for i := range transactions {
+			transactions[i].Currency = converter.currency(transactions[i].Currency)
+		}
+		for i := range statements {
+			statements[i].Currency = converter.currency(statements[i].Currency)
+		}
+	}
+	err = converter.checkCurrencies(transactions, statements)
 	if err != nil {
-		return fmt.Errorf("store transactions error: %w", err)
+		return Result{}, err
+	}
+
+	overrides := applyOverrides(r.overrides, transactions, statements)
+	reversed, transactions, statements := r.options.Reversals.apply(overrides.transactions, overrides.statements)
+	pool := newStatementPool(statements)
+	if r.options.Match.FXTolerance > 0 {
+		pool = pool.withFX(converter, r.options.Match.FXTolerance)
+	}
+	if r.options.Match.SettlementDays > 0 {
+		pool = pool.withWindow(r.calendar, r.options.Match.SettlementDays)
 	}
 
-	for bank, group := range bankStatementDisrepancy {
-		err = r.bankStatementRepoStorage.StoreBankStatements(group.Statements, bank)
+	var matches []Match
+	transactionDiscrepancies := []Transaction{}
+	for _, t := range transactions {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		statement, ok := pool.take(t)
+		if !ok {
+			transactionDiscrepancies = append(transactionDiscrepancies, t)
+			continue
+		}
+		fxDifference, err := converter.fxDifference(t, statement)
 		if err != nil {
-			return fmt.Errorf("store bank statements error: %w", err)
+			return Result{}, fmt.Errorf("convert match error: %w", err)
 		}
+		matches = append(matches, Match{Transaction: t, BankStatement: statement, FXDifference: fxDifference})
 	}
 
-	return nil
+	unmatchedStatements := pool.unmatched()
+	for _, unmatch := range overrides.unmatches {
+		transactionDiscrepancies = append(transactionDiscrepancies, unmatch.Transactions...)
+		unmatchedStatements = append(unmatchedStatements, unmatch.BankStatements...)
+	}
+
+	// keep account order and statement order as loaded so the output is stable
+	var bankStatementDisrepancies []BankStatementDiscrepancy
+	accountIndex := map[BankAccount]int{}
+	for _, statement := range unmatchedStatements {
+		account := statement.BankAccount()
+		if _, ok := accountIndex[account]; !ok {
+			accountIndex[account] = len(bankStatementDisrepancies)
+			bankStatementDisrepancies = append(bankStatementDisrepancies, BankStatementDiscrepancy{Bank: account.Bank, Account: account.Account})
+		}
+		group := &bankStatementDisrepancies[accountIndex[account]]
+		group.Statements = append(group.Statements, statement)
+	}
+
+	total, err := summarize(matches, overrides.matches, transactionDiscrepancies, bankStatementDisrepancies, converter)
+	if err != nil {
+		return Result{}, fmt.Errorf("summarize error: %w", err)
+	}
+	total.BalanceChecks = balanceChecks
+
+	result := Result{
+		RunAt:                   in.runAt,
+		Options:                 r.options,
+		StartDate:               in.startDate,
+		EndDate:                 in.endDate,
+		TransactionInput:        in.transactionInput,
+		BankStatementInputs:     in.bankStatementInputs,
+		Summary:                 total,
+		Matches:                 matches,
+		UnmatchedTransactions:   transactionDiscrepancies,
+		UnmatchedBankStatements: bankStatementDisrepancies,
+		CarriedForward:          in.carriedForward,
+		ManualMatches:           overrides.matches,
+		ManualUnmatches:         overrides.unmatches,
+		Exclusions:              overrides.exclusions,
+		UnappliedOverrides:      overrides.unapplied,
+		FXRates:                 r.fxRates,
+		Calendar:                r.calendar,
+		Cutoffs:                 r.cutoffs,
+		ConfigInputs:            r.configInputs,
+		DuplicateTransactions:   duplicates.transactions,
+		DuplicateBankStatements: duplicates.statements,
+		TransactionReversals:    reversed.transactions,
+		BankStatementReversals:  reversed.statements,
+	}
+	result.Summary.Unaccounted = unaccounted(in, result, converter)
+	return result, nil
+}
+
+// itemPeriod returns the days of the earliest and the latest item, in the
+// time zone of each, or the zero time when there are no items.
+func itemPeriod(transactions []Transaction, statements []BankStatement) (time.Time, time.Time) {
+	var times []time.Time
+	for _, t := range transactions {
+		times = append(times, t.Time)
+	}
+	for _, s := range statements {
+		times = append(times, s.Time)
+	}
+	if len(times) == 0 {
+		return time.Time{}, time.Time{}
+	}
+	earliest := slices.MinFunc(times, time.Time.Compare)
+	latest := slices.MaxFunc(times, time.Time.Compare)
+	return truncateToDay(earliest), truncateToDay(latest)
+}
+
+// summarize counts both sides of the recon, in the reporting currency.
+// Manual matches count as matched.
+func summarize(matches []Match, manualMatches []AppliedOverride, unmatchedTransactions []Transaction, unmatchedStatements []BankStatementDiscrepancy, converter currencyConverter) (Summary, error) {
+	total := Summary{ReportingCurrency: converter.reporting}
+
+	var convertErr error
+	transactionAmount := func(t Transaction) float64 {
+		amount, err := converter.toReporting(t.Amount, t.Currency, t.Time)
+		if err != nil && convertErr == nil {
+			convertErr = fmt.Errorf("transaction %s: %w", t.ID, err)
+		}
+		return amount
+	}
+	statementAmount := func(s BankStatement) float64 {
+		amount, err := converter.toReporting(s.Amount, s.Currency, s.Time)
+		if err != nil && convertErr == nil {
+			convertErr = fmt.Errorf("bank statement %s/%s: %w", s.BankAccount(), s.ID, err)
+		}
+		return amount
+	}
+
+	matchTransaction := func(t Transaction) {
+		amount := transactionAmount(t)
+		total.TotalTransactions++
+		total.TotalAmountTransactions += amount
+		total.MatchedTransactions++
+		total.MatchedAmountTransactions += amount
+		total.MatchedAmountDifference += amount
+	}
+	accountIndex := map[BankAccount]int{}
+	account := func(s BankStatement, amount float64) *AccountSummary {
+		if _, ok := accountIndex[s.BankAccount()]; !ok {
+			accountIndex[s.BankAccount()] = len(total.Accounts)
+			total.Accounts = append(total.Accounts, AccountSummary{Bank: s.Bank, Account: s.Account})
+		}
+		a := &total.Accounts[accountIndex[s.BankAccount()]]
+		a.TotalBankStatements++
+		a.TotalAmountBankStatements += amount
+		return a
+	}
+	matchStatement := func(s BankStatement) {
+		amount := statementAmount(s)
+		total.TotalBankStatements++
+		total.TotalAmountBankStatements += amount
+		total.MatchedBankStatements++
+		total.MatchedAmountBankStatements += amount
+		total.MatchedAmountDifference -= amount
+
+		a := account(s, amount)
+		a.MatchedBankStatements++
+		a.MatchedAmountBankStatements += amount
+	}
+
+	for _, m := range matches {
+		matchTransaction(m.Transaction)
+		matchStatement(m.BankStatement)
+		total.MatchedAmountDifference -= m.FXDifference
+		total.FXDifference += m.FXDifference
+	}
+	for _, m := range manualMatches {
+		for _, t := range m.Transactions {
+			matchTransaction(t)
+		}
+		for _, s := range m.BankStatements {
+			matchStatement(s)
+		}
+	}
+
+	for _, t := range unmatchedTransactions {
+		amount := transactionAmount(t)
+		total.TotalTransactions++
+		total.TotalAmountTransactions += amount
+		total.UnmatchedTransactions++
+		total.UnmatchedAmountTransactions += amount
+	}
+	for _, group := range unmatchedStatements {
+		for _, s := range group.Statements {
+			amount := statementAmount(s)
+			total.TotalBankStatements++
+			total.TotalAmountBankStatements += amount
+			total.UnmatchedBankStatements++
+			total.UnmatchedAmountBankStatements += amount
+
+			a := account(s, amount)
+			a.UnmatchedBankStatements++
+			a.UnmatchedAmountBankStatements += amount
+		}
+	}
+	if convertErr != nil {
+		return Summary{}, convertErr
+	}
+
+	slices.SortFunc(total.Accounts, func(a, b AccountSummary) int {
+		return cmp.Or(cmp.Compare(a.Bank, b.Bank), cmp.Compare(a.Account, b.Account))
+	})
+	return total, nil
 }
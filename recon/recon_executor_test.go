@@ -1,7 +1,11 @@
 package recon
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log"
 	"testing"
 	"time"
 
@@ -13,19 +17,27 @@ type reconExecutorSuite struct {
 	mockTransactionStorage       *MockTransactionStorageProvider
 	mockBankStatementRepoStorage *MockBankStatementStorageProvider
 	mockSummaryRepoStorage       *MockSummaryStorageProvider
+	mockReportRepoStorage        *MockReportStorageProvider
 	reconExecutor                ReconExecutor
 }
 
+var reconExecutorRunAt = time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)
+
 func getReconExecutorSuite(ctrl *gomock.Controller) reconExecutorSuite {
 	mockTransactionStorage := NewMockTransactionStorageProvider(ctrl)
 	mockBankStatementRepoStorage := NewMockBankStatementStorageProvider(ctrl)
 	mockSummaryRepoStorage := NewMockSummaryStorageProvider(ctrl)
+	mockReportRepoStorage := NewMockReportStorageProvider(ctrl)
+
+	reconExecutor := NewReconExecutor(mockTransactionStorage, mockBankStatementRepoStorage, mockSummaryRepoStorage, mockReportRepoStorage)
+	reconExecutor.now = func() time.Time { return reconExecutorRunAt }
 
 	return reconExecutorSuite{
 		mockTransactionStorage:       mockTransactionStorage,
 		mockBankStatementRepoStorage: mockBankStatementRepoStorage,
 		mockSummaryRepoStorage:       mockSummaryRepoStorage,
-		reconExecutor:                NewReconExecutor(mockTransactionStorage, mockBankStatementRepoStorage, mockSummaryRepoStorage),
+		mockReportRepoStorage:        mockReportRepoStorage,
+		reconExecutor:                reconExecutor,
 	}
 }
 
@@ -57,24 +69,56 @@ func TestReconExecutor_Execute(t *testing.T) {
 			{Bank: "BRI", Amount: 400.0, Time: startDate},
 		}
 
-		suite.mockTransactionStorage.EXPECT().GetTransactions(transactionPath, startDate, endDate).Return(transactions, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bca.xlsx", startDate, endDate).Return(bankStatementsBCA, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bri.xlsx", startDate, endDate).Return(bankStatementsBRI, nil)
+		transactionReport := LoadReport{Path: transactionPath, SHA256: "trx"}
+		bcaReport := LoadReport{Path: "bca.xlsx", SHA256: "bca"}
+		briReport := LoadReport{Path: "bri.xlsx", SHA256: "bri", RejectedRows: []RejectedRow{{Path: "bri.xlsx", Line: 2, Reason: "missing columns"}}}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, transactionReport, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, bcaReport, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(bankStatementsBRI, briReport, nil)
 
 		expectedSummary := Summary{
-			TotalAmountBankStatements: 1000.0,
-			TotalAmountTransactions:   550.0,
-			TotalMatched:              2,
-			TotalUnmatched:            3,
-			TotalProcessed:            5,
+			TotalTransactions:             3,
+			TotalAmountTransactions:       550.0,
+			MatchedTransactions:           2,
+			MatchedAmountTransactions:     300.0,
+			UnmatchedTransactions:         1,
+			UnmatchedAmountTransactions:   250.0,
+			TotalBankStatements:           4,
+			TotalAmountBankStatements:     1000.0,
+			MatchedBankStatements:         2,
+			MatchedAmountBankStatements:   300.0,
+			UnmatchedBankStatements:       2,
+			UnmatchedAmountBankStatements: 700.0,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 2, TotalAmountBankStatements: 400.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 100.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 300.0},
+				{Bank: "BRI", TotalBankStatements: 2, TotalAmountBankStatements: 600.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 200.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 400.0},
+			},
 		}
-		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(expectedSummary).Return(nil)
-		suite.mockTransactionStorage.EXPECT().StoreTransactions([]Transaction{{ID: "3", Amount: 250.0, Type: Debit, Time: startDate}}).Return(nil)
-
-		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements([]BankStatement{{Bank: "BCA", Amount: 300.0, Time: startDate}}, "BCA").Return(nil)
-		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements([]BankStatement{{Bank: "BRI", Amount: 400.0, Time: startDate}}, "BRI").Return(nil)
-
-		err := suite.reconExecutor.Execute(transactionPath, bankStatementPaths, startDate, endDate)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), expectedSummary).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), []Transaction{{ID: "3", Amount: 250.0, Type: Debit, Time: startDate}}).Return(nil)
+
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), []BankStatement{{Bank: "BCA", Amount: 300.0, Time: startDate}}, "BCA").Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), []BankStatement{{Bank: "BRI", Amount: 400.0, Time: startDate}}, "BRI").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), Result{
+			RunAt:               reconExecutorRunAt,
+			StartDate:           startDate,
+			EndDate:             endDate,
+			TransactionInput:    transactionReport,
+			BankStatementInputs: []LoadReport{bcaReport, briReport},
+			Summary:             expectedSummary,
+			Matches: []Match{
+				{Transaction: transactions[0], BankStatement: bankStatementsBCA[0]},
+				{Transaction: transactions[1], BankStatement: bankStatementsBRI[0]},
+			},
+			UnmatchedTransactions: []Transaction{transactions[2]},
+			UnmatchedBankStatements: []BankStatementDiscrepancy{
+				{Bank: "BCA", Statements: []BankStatement{bankStatementsBCA[1]}},
+				{Bank: "BRI", Statements: []BankStatement{bankStatementsBRI[1]}},
+			},
+		}).Return(nil)
+
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
 		g.Expect(err).Should(BeNil())
 	})
 
@@ -85,9 +129,9 @@ func TestReconExecutor_Execute(t *testing.T) {
 
 		suite := getReconExecutorSuite(ctrl)
 
-		suite.mockTransactionStorage.EXPECT().GetTransactions(transactionPath, startDate, endDate).Return(nil, fmt.Errorf("get transactions error"))
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(nil, LoadReport{}, fmt.Errorf("get transactions error"))
 
-		err := suite.reconExecutor.Execute(transactionPath, bankStatementPaths, startDate, endDate)
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
 		g.Expect(err).ShouldNot(BeNil())
 	})
 
@@ -98,10 +142,10 @@ func TestReconExecutor_Execute(t *testing.T) {
 
 		suite := getReconExecutorSuite(ctrl)
 
-		suite.mockTransactionStorage.EXPECT().GetTransactions(transactionPath, startDate, endDate).Return([]Transaction{}, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bca.xlsx", startDate, endDate).Return(nil, fmt.Errorf("get bank statements error"))
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return([]Transaction{}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(nil, LoadReport{}, fmt.Errorf("get bank statements error"))
 
-		err := suite.reconExecutor.Execute(transactionPath, bankStatementPaths, startDate, endDate)
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
 		g.Expect(err).ShouldNot(BeNil())
 	})
 
@@ -120,21 +164,27 @@ func TestReconExecutor_Execute(t *testing.T) {
 			{Bank: "BCA", Amount: 100.0, Time: startDate},
 		}
 
-		suite.mockTransactionStorage.EXPECT().GetTransactions(transactionPath, startDate, endDate).Return(transactions, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bca.xlsx", startDate, endDate).Return(bankStatementsBCA, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bri.xlsx", startDate, endDate).Return([]BankStatement{}, nil)
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
 
 		expectedSummary := Summary{
-			TotalProcessed:            1,
-			TotalAmountBankStatements: 100.0,
-			TotalAmountTransactions:   100.0,
-			TotalMatched:              1,
-			TotalUnmatched:            0,
+			TotalTransactions:           1,
+			TotalAmountTransactions:     100.0,
+			MatchedTransactions:         1,
+			MatchedAmountTransactions:   100.0,
+			TotalBankStatements:         1,
+			TotalAmountBankStatements:   100.0,
+			MatchedBankStatements:       1,
+			MatchedAmountBankStatements: 100.0,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 1, TotalAmountBankStatements: 100.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 100.0},
+			},
 		}
 
-		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Eq(expectedSummary)).Return(fmt.Errorf("store summary error"))
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Eq(expectedSummary)).Return(fmt.Errorf("store summary error"))
 
-		err := suite.reconExecutor.Execute(transactionPath, bankStatementPaths, startDate, endDate)
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
 		g.Expect(err).ShouldNot(BeNil())
 	})
 
@@ -153,21 +203,27 @@ func TestReconExecutor_Execute(t *testing.T) {
 			{Bank: "BCA", Amount: 100.0, Time: startDate},
 		}
 
-		suite.mockTransactionStorage.EXPECT().GetTransactions(transactionPath, startDate, endDate).Return(transactions, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bca.xlsx", startDate, endDate).Return(bankStatementsBCA, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bri.xlsx", startDate, endDate).Return([]BankStatement{}, nil)
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
 
 		expectedSummary := Summary{
-			TotalProcessed:            1,
-			TotalAmountBankStatements: 100.0,
-			TotalAmountTransactions:   100.0,
-			TotalMatched:              1,
-			TotalUnmatched:            0,
+			TotalTransactions:           1,
+			TotalAmountTransactions:     100.0,
+			MatchedTransactions:         1,
+			MatchedAmountTransactions:   100.0,
+			TotalBankStatements:         1,
+			TotalAmountBankStatements:   100.0,
+			MatchedBankStatements:       1,
+			MatchedAmountBankStatements: 100.0,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 1, TotalAmountBankStatements: 100.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 100.0},
+			},
 		}
-		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Eq(expectedSummary)).Return(nil)
-		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Eq([]Transaction{})).Return(fmt.Errorf("store transactions error"))
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Eq(expectedSummary)).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(fmt.Errorf("store transactions error"))
 
-		err := suite.reconExecutor.Execute(transactionPath, bankStatementPaths, startDate, endDate)
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
 		g.Expect(err).ShouldNot(BeNil())
 	})
 
@@ -187,22 +243,720 @@ func TestReconExecutor_Execute(t *testing.T) {
 			{Bank: "BCA", Amount: 100.0, Time: startDate},
 		}
 
-		suite.mockTransactionStorage.EXPECT().GetTransactions(transactionPath, startDate, endDate).Return(transactions, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bca.xlsx", startDate, endDate).Return(bankStatementsBCA, nil)
-		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements("bri.xlsx", startDate, endDate).Return([]BankStatement{}, nil)
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		expectedSummary := Summary{
+			TotalTransactions:             1,
+			TotalAmountTransactions:       100.0,
+			MatchedTransactions:           1,
+			MatchedAmountTransactions:     100.0,
+			TotalBankStatements:           2,
+			TotalAmountBankStatements:     200.0,
+			MatchedBankStatements:         1,
+			MatchedAmountBankStatements:   100.0,
+			UnmatchedBankStatements:       1,
+			UnmatchedAmountBankStatements: 100.0,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 2, TotalAmountBankStatements: 200.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 100.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 100.0},
+			},
+		}
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Eq(expectedSummary)).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), gomock.Eq([]BankStatement{{Bank: "BCA", Amount: 100.0, Time: startDate}}), "BCA").Return(fmt.Errorf("store bank statements error"))
+
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when StoreReport fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return([]Transaction{}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), Summary{}).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(fmt.Errorf("store report error"))
+
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should leave ledger lines booked after the cutoff of their bank to the period", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockLedger := NewMockLedgerProvider(ctrl)
+		reconExecutor := suite.reconExecutor.WithLedger(mockLedger).WithCutoffs(map[string]time.Duration{"BCA": 22 * time.Hour})
+
+		// BCA books lines after 22:00 on the next day, so the 23:00 line is
+		// in the period and loaded from the file again
+		lateStatement := BankStatement{Bank: "BCA", ID: "late", Amount: 100.0, Time: startDate.Add(-time.Hour)}
+		earlyStatement := BankStatement{Bank: "BCA", ID: "early", Amount: 70.0, Time: startDate.Add(-3 * time.Hour)}
+		lateTransaction := Transaction{ID: "0", Amount: 100.0, Type: Debit, Time: startDate.Add(-time.Hour)}
+		mockLedger.EXPECT().GetOpenItems(gomock.Any(), startDate).Return([]LedgerItem{
+			{Kind: LedgerBankStatement, BankStatement: earlyStatement, OpenedBy: "previous"},
+			{Kind: LedgerTransaction, Transaction: lateTransaction, OpenedBy: "previous"},
+			{Kind: LedgerBankStatement, BankStatement: lateStatement, OpenedBy: "previous"},
+		}, nil)
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return([]BankStatement{lateStatement}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), gomock.Any(), "BCA").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.CarriedForward).Should(HaveLen(2))
+			g.Expect(result.Summary.TotalBankStatements).Should(Equal(2))
+			g.Expect(result.Matches).Should(ConsistOf(Match{Transaction: lateTransaction, BankStatement: lateStatement}))
+			return nil
+		})
+		mockLedger.EXPECT().UpdateLedger(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should carry open ledger items forward and update the ledger", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockLedger := NewMockLedgerProvider(ctrl)
+		reconExecutor := suite.reconExecutor.WithLedger(mockLedger)
+
+		previousDay := startDate.AddDate(0, 0, -1)
+		carriedTransaction := Transaction{ID: "0", Amount: 300.0, Type: Debit, Time: previousDay}
+		carriedStatement := BankStatement{Bank: "BRI", ID: "x", Amount: 50.0, Time: previousDay}
+		carriedForward := []LedgerItem{
+			{Kind: LedgerTransaction, Transaction: carriedTransaction, OpenedBy: "previous"},
+			{Kind: LedgerBankStatement, BankStatement: carriedStatement, OpenedBy: "previous"},
+		}
+
+		transactions := []Transaction{{ID: "1", Amount: 100.0, Type: Credit, Time: startDate}}
+		bankStatementsBCA := []BankStatement{
+			{Bank: "BCA", ID: "a", Amount: 100.0, Time: startDate},
+			{Bank: "BCA", ID: "b", Amount: 300.0, Time: startDate},
+		}
+
+		mockLedger.EXPECT().GetOpenItems(gomock.Any(), startDate).Return(carriedForward, nil)
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		expectedSummary := Summary{
+			TotalTransactions:             2,
+			TotalAmountTransactions:       400.0,
+			MatchedTransactions:           2,
+			MatchedAmountTransactions:     400.0,
+			TotalBankStatements:           3,
+			TotalAmountBankStatements:     450.0,
+			MatchedBankStatements:         2,
+			MatchedAmountBankStatements:   400.0,
+			UnmatchedBankStatements:       1,
+			UnmatchedAmountBankStatements: 50.0,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 2, TotalAmountBankStatements: 400.0, MatchedBankStatements: 2, MatchedAmountBankStatements: 400.0},
+				{Bank: "BRI", TotalBankStatements: 1, TotalAmountBankStatements: 50.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 50.0},
+			},
+		}
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), expectedSummary).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), []BankStatement{carriedStatement}, "BRI").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(nil)
+		mockLedger.EXPECT().UpdateLedger(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.CarriedForward).Should(Equal(carriedForward))
+			g.Expect(result.Cleared()).Should(Equal(carriedForward[:1]))
+			g.Expect(result.Matches[0].Transaction).Should(Equal(carriedTransaction))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should apply overrides before automatic matching", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		overrides := []Override{
+			{Action: OverrideMatch, TransactionIDs: []string{"1", "2"}, Statements: []StatementRef{{Bank: "BCA", ID: "a"}}, Reason: "split payment", Author: "ops"},
+			{Action: OverrideUnmatch, TransactionIDs: []string{"3"}, Reason: "disputed", Author: "ops"},
+			{Action: OverrideExclude, Statements: []StatementRef{{Bank: "BRI", ID: "fee"}}, Reason: "bank fee", Author: "ops"},
+			{Action: OverrideExclude, TransactionIDs: []string{"missing"}, Reason: "test", Author: "ops"},
+		}
+		reconExecutor := suite.reconExecutor.WithOverrides(overrides)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100.0, Type: Credit, Time: startDate},
+			{ID: "2", Amount: 50.0, Type: Credit, Time: startDate},
+			{ID: "3", Amount: 70.0, Type: Debit, Time: startDate},
+		}
+		bankStatementsBCA := []BankStatement{
+			{Bank: "BCA", ID: "a", Amount: 150.0, Time: startDate},
+			{Bank: "BCA", ID: "b", Amount: 70.0, Time: startDate},
+		}
+		bankStatementsBRI := []BankStatement{{Bank: "BRI", ID: "fee", Amount: 5.0, Time: startDate}}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(bankStatementsBRI, LoadReport{}, nil)
 
 		expectedSummary := Summary{
-			TotalProcessed:            2,
-			TotalAmountBankStatements: 200.0,
-			TotalAmountTransactions:   100.0,
-			TotalMatched:              1,
-			TotalUnmatched:            1,
+			TotalTransactions:             3,
+			TotalAmountTransactions:       220.0,
+			MatchedTransactions:           2,
+			MatchedAmountTransactions:     150.0,
+			UnmatchedTransactions:         1,
+			UnmatchedAmountTransactions:   70.0,
+			TotalBankStatements:           2,
+			TotalAmountBankStatements:     220.0,
+			MatchedBankStatements:         1,
+			MatchedAmountBankStatements:   150.0,
+			UnmatchedBankStatements:       1,
+			UnmatchedAmountBankStatements: 70.0,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 2, TotalAmountBankStatements: 220.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 150.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 70.0},
+			},
+		}
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), expectedSummary).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), []Transaction{transactions[2]}).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), []BankStatement{bankStatementsBCA[1]}, "BCA").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.Matches).Should(BeEmpty())
+			g.Expect(result.ManualMatches).Should(HaveLen(1))
+			g.Expect(result.ManualUnmatches).Should(HaveLen(1))
+			g.Expect(result.Exclusions).Should(HaveLen(1))
+			g.Expect(result.UnappliedOverrides).Should(Equal(overrides[3:]))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should report balance breaks in the summary", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		reconExecutor := suite.reconExecutor.WithDeclaredBalances([]DeclaredBalance{{Bank: "bri", Opening: 500, Closing: 600}})
+
+		transactions := []Transaction{{ID: "1", Amount: 100.0, Type: Credit, Time: startDate}}
+		bankStatementsBCA := []BankStatement{{Bank: "bca", ID: "a", Amount: 100.0, Time: startDate}}
+		bankStatementsBRI := []BankStatement{{Bank: "bri", ID: "b", Amount: 90.0, Time: startDate}}
+		bcaReport := LoadReport{Path: "bca.xlsx", Balances: []RunningBalance{{Bank: "bca", Opening: 1000, Closing: 1100}}}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, bcaReport, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(bankStatementsBRI, LoadReport{}, nil)
+
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, summary Summary) error {
+			g.Expect(summary.BalanceChecks).Should(Equal([]BalanceCheck{
+				{Bank: "bca", Opening: 1000, Lines: 100, Closing: 1100},
+				{Bank: "bri", Opening: 500, Lines: 90, Closing: 600, Declared: true},
+			}))
+			g.Expect(summary.BalanceBreaks()).Should(Equal(1))
+			return nil
+		})
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), bankStatementsBRI, "bri").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should group unmatched bank statements by account", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+
+		transactions := []Transaction{{ID: "1", Amount: 100.0, Type: Credit, Time: startDate}}
+		bankStatementsBCA := []BankStatement{
+			{Bank: "bca", Account: "222", ID: "a", Amount: 50.0, Time: startDate},
+			{Bank: "bca", Account: "111", ID: "a", Amount: 100.0, Time: startDate},
+			{Bank: "bca", Account: "111", ID: "b", Amount: 30.0, Time: startDate},
+		}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, summary Summary) error {
+			g.Expect(summary.Accounts).Should(Equal([]AccountSummary{
+				{Bank: "bca", Account: "111", TotalBankStatements: 2, TotalAmountBankStatements: 130.0, MatchedBankStatements: 1, MatchedAmountBankStatements: 100.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 30.0},
+				{Bank: "bca", Account: "222", TotalBankStatements: 1, TotalAmountBankStatements: 50.0, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 50.0},
+			}))
+			return nil
+		})
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), bankStatementsBCA[:1], "bca 222").Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), bankStatementsBCA[2:], "bca 111").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.UnmatchedBankStatements).Should(Equal([]BankStatementDiscrepancy{
+				{Bank: "bca", Account: "222", Statements: bankStatementsBCA[:1]},
+				{Bank: "bca", Account: "111", Statements: bankStatementsBCA[2:]},
+			}))
+			return nil
+		})
+
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should match across currencies and report the FX difference", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		options := Options{ReportingCurrency: "IDR", Match: MatchConfig{FXTolerance: 0.01}}
+		rates := NewFXRates([]FXRate{{Date: startDate, Base: "USD", Quote: "IDR", Rate: 16000}})
+		reconExecutor := suite.reconExecutor.WithOptions(options).WithFXRates(rates)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 10.0, Currency: "USD", Type: Credit, Time: startDate},
+			{ID: "2", Amount: 5000.0, Type: Credit, Time: startDate},
+		}
+		bankStatementsBCA := []BankStatement{
+			{Bank: "bca", ID: "a", Amount: 159000.0, Currency: "IDR", Time: startDate},
+			{Bank: "bca", ID: "b", Amount: 5000.0, Currency: "IDR", Time: startDate},
+		}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, summary Summary) error {
+			g.Expect(summary.ReportingCurrency).Should(Equal("IDR"))
+			g.Expect(summary.TotalAmountTransactions).Should(Equal(165000.0))
+			g.Expect(summary.TotalAmountBankStatements).Should(Equal(164000.0))
+			g.Expect(summary.MatchedAmountDifference).Should(Equal(0.0))
+			g.Expect(summary.FXDifference).Should(Equal(1000.0))
+			g.Expect(summary.Balanced()).Should(BeTrue())
+			return nil
+		})
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.Matches).Should(HaveLen(2))
+			g.Expect(result.Matches[0].BankStatement.ID).Should(Equal("a"))
+			g.Expect(result.Matches[0].FXDifference).Should(Equal(1000.0))
+			g.Expect(result.Matches[1].Transaction.Currency).Should(Equal("IDR"))
+			g.Expect(result.Matches[1].FXDifference).Should(Equal(0.0))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should return error when currencies are mixed without a reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+
+		transactions := []Transaction{{ID: "1", Amount: 10.0, Currency: "USD", Type: Credit, Time: startDate}}
+		bankStatementsBCA := []BankStatement{{Bank: "bca", ID: "a", Amount: 160000.0, Currency: "IDR", Time: startDate}}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		err := suite.reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should match only statements settling within the business day window", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		// 2025-08-01 is a Friday, 2025-08-04 the following Monday
+		calendar := NewCalendar([]Holiday{{Date: startDate.AddDate(0, 0, 3), Name: "Bank holiday", Bank: "bri"}})
+		reconExecutor := suite.reconExecutor.WithOptions(Options{Match: MatchConfig{SettlementDays: 1}}).WithCalendar(calendar)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100.0, Type: Credit, Time: startDate},
+			{ID: "2", Amount: 200.0, Type: Credit, Time: startDate},
 		}
-		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Eq(expectedSummary)).Return(nil)
-		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Eq([]Transaction{})).Return(nil)
-		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Eq([]BankStatement{{Bank: "BCA", Amount: 100.0, Time: startDate}}), "BCA").Return(fmt.Errorf("store bank statements error"))
+		bankStatementsBCA := []BankStatement{{Bank: "bca", ID: "a", Amount: 100.0, Time: startDate.AddDate(0, 0, 3)}}
+		bankStatementsBRI := []BankStatement{{Bank: "bri", ID: "b", Amount: 200.0, Time: startDate.AddDate(0, 0, 5)}}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(bankStatementsBRI, LoadReport{}, nil)
+
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), transactions[1:]).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), bankStatementsBRI, "bri").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.Matches).Should(HaveLen(1))
+			g.Expect(result.Matches[0].BankStatement.ID).Should(Equal("a"))
+			g.Expect(result.Calendar).Should(Equal(calendar))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should leave duplicates out of matching with keep-first", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		options := Options{Duplicates: DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateKeepFirst}}
+		reconExecutor := suite.reconExecutor.WithOptions(options)
+
+		transactions := []Transaction{{ID: "1", Amount: 100.0, Type: Credit, Time: startDate}}
+		// overlapping exports repeat line a
+		bankStatementsBCA := []BankStatement{{Bank: "bca", ID: "a", Amount: 100.0, Time: startDate}}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, summary Summary) error {
+			g.Expect(summary.TotalBankStatements).Should(Equal(1))
+			g.Expect(summary.UnmatchedBankStatements).Should(Equal(0))
+			return nil
+		})
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.DuplicateBankStatements).Should(Equal([]DuplicateBankStatement{
+				{Key: DuplicateKeyID, BankStatement: bankStatementsBCA[0], Original: bankStatementsBCA[0]},
+			}))
+			g.Expect(result.DuplicateTransactions).Should(BeEmpty())
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should return error when duplicates are rejected", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		options := Options{Duplicates: DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateReject}}
+		reconExecutor := suite.reconExecutor.WithOptions(options)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100.0, Type: Credit, Time: startDate},
+			{ID: "1", Amount: 100.0, Type: Credit, Time: startDate},
+		}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(MatchError("duplicates error: 1 duplicate transactions, first 1 repeating 1 on id"))
+	})
+
+	t.Run("should store nothing when the context is canceled", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).DoAndReturn(func(context.Context, string, time.Time, time.Time) ([]Transaction, LoadReport, error) {
+			cancel()
+			return []Transaction{{ID: "1", Amount: 100.0, Type: Credit, Time: startDate}}, LoadReport{}, nil
+		})
+
+		err := suite.reconExecutor.Execute(ctx, transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+
+	t.Run("should pair reversals before matching", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		reconExecutor := suite.reconExecutor.WithOptions(Options{Reversals: ReversalConfig{Window: 24 * time.Hour}})
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100.0, Type: Debit, Time: startDate},
+			{ID: "2", Amount: 100.0, Type: Credit, Time: startDate.Add(time.Hour)},
+			{ID: "3", Amount: 40.0, Type: Credit, Time: startDate},
+		}
+		bankStatementsBCA := []BankStatement{
+			{Bank: "bca", ID: "a", Amount: 40.0, Time: startDate},
+			{Bank: "bca", ID: "b", Amount: -40.0, Time: startDate.Add(time.Hour), Reference: "a"},
+			{Bank: "bca", ID: "c", Amount: 40.0, Time: startDate.Add(2 * time.Hour)},
+		}
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(bankStatementsBCA, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return([]BankStatement{}, LoadReport{}, nil)
+
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, summary Summary) error {
+			g.Expect(summary.TotalTransactions).Should(Equal(1))
+			g.Expect(summary.TotalBankStatements).Should(Equal(1))
+			g.Expect(summary.Balanced()).Should(BeTrue())
+			return nil
+		})
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Eq([]Transaction{})).Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, result Result) error {
+			g.Expect(result.Matches).Should(Equal([]Match{{Transaction: transactions[2], BankStatement: bankStatementsBCA[2]}}))
+			g.Expect(result.TransactionReversals).Should(Equal([]TransactionReversal{{Original: transactions[0], Reversal: transactions[1]}}))
+			g.Expect(result.BankStatementReversals).Should(Equal([]BankStatementReversal{{Original: bankStatementsBCA[0], Reversal: bankStatementsBCA[1]}}))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should return error when GetOpenItems fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockLedger := NewMockLedgerProvider(ctrl)
+		reconExecutor := suite.reconExecutor.WithLedger(mockLedger)
+
+		mockLedger.EXPECT().GetOpenItems(gomock.Any(), startDate).Return(nil, fmt.Errorf("ledger error"))
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when options are invalid", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		reconExecutor := suite.reconExecutor.WithOptions(Options{Match: MatchConfig{FXTolerance: -1}})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should notify about failed runs", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockNotifier := NewMockNotifier(ctrl)
+		var logs bytes.Buffer
+		reconExecutor := suite.reconExecutor.WithNotifiers(NotifyRules{OnFailure: true}, []string{"recon.xlsx"}, log.New(&logs, "", 0), mockNotifier)
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(nil, LoadReport{}, fmt.Errorf("get transactions error"))
+		mockNotifier.EXPECT().Notify(gomock.Any(), Notification{
+			Status: JobFailed,
+			RunAt:  reconExecutorRunAt,
+			Period: JSONPeriod{StartDate: "2025-08-01", EndDate: "2025-08-30", Timezone: "UTC"},
+			Error:  "get transactions error: get transactions error",
+		}).Return(fmt.Errorf("webhook down"))
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(MatchError("get transactions error: get transactions error"))
+		g.Expect(logs.String()).Should(Equal("Recon 2025-08-01 to 2025-08-30: notify error: webhook down\n"))
+	})
+
+	t.Run("should notify when unmatched items exceed a threshold", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockNotifier := NewMockNotifier(ctrl)
+		reconExecutor := suite.reconExecutor.WithNotifiers(NotifyRules{OnFailure: true, UnmatchedCountAbove: 1, UnmatchedAmountAbove: 1000}, []string{"recon.xlsx"}, log.New(io.Discard, "", 0), mockNotifier)
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return([]Transaction{{ID: "1", Amount: 100, Type: Debit, Time: startDate}}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return([]BankStatement{{Bank: "BCA", Amount: 70, Time: startDate}}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), gomock.Any(), "BCA").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(nil)
+		mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n Notification) error {
+			g.Expect(n.Status).Should(Equal(JobSucceeded))
+			g.Expect(n.Summary.Transactions.UnmatchedCount).Should(Equal(1))
+			g.Expect(n.Breaches).Should(Equal([]string{"2 unmatched items, above 1"}))
+			g.Expect(n.Title()).Should(Equal("Recon 2025-08-01 to 2025-08-30 exceeded thresholds"))
+			g.Expect(n.Attachments).Should(Equal([]string{"recon.xlsx"}))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should compare the unmatched amount threshold in the reporting currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockNotifier := NewMockNotifier(ctrl)
+		reconExecutor := suite.reconExecutor.
+			WithOptions(Options{ReportingCurrency: "IDR"}).
+			WithFXRates(NewFXRates([]FXRate{{Date: startDate, Base: "USD", Quote: "IDR", Rate: 16000}})).
+			WithNotifiers(NotifyRules{UnmatchedAmountAbove: 1000000}, nil, log.New(io.Discard, "", 0), mockNotifier)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100, Currency: "USD", Type: Debit, Time: startDate},
+			{ID: "2", Amount: 50000, Currency: "IDR", Type: Debit, Time: startDate},
+		}
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(transactions, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(nil)
+		mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n Notification) error {
+			g.Expect(n.Breaches).Should(Equal([]string{"unmatched amount 1650000.00 IDR, above 1000000.00 IDR"}))
+			g.Expect(n.Lines()[0]).Should(Equal("Transactions: 2, 0 matched, 2 unmatched (1650000.00 IDR)"))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should not let unmatched debits and credits cancel out in the amount threshold", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		mockNotifier := NewMockNotifier(ctrl)
+		reconExecutor := suite.reconExecutor.WithNotifiers(NotifyRules{UnmatchedAmountAbove: 1500}, nil, log.New(io.Discard, "", 0), mockNotifier)
+
+		statements := []BankStatement{
+			{Bank: "BCA", ID: "a", Amount: 1000, Time: startDate},
+			{Bank: "BCA", ID: "b", Amount: -1000, Time: startDate},
+		}
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return(statements, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockBankStatementRepoStorage.EXPECT().StoreBankStatements(gomock.Any(), gomock.Any(), "BCA").Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(nil)
+		mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n Notification) error {
+			g.Expect(n.Summary.BankStatements.UnmatchedAmount).Should(BeZero())
+			g.Expect(n.Breaches).Should(Equal([]string{"unmatched amount 2000.00, above 1500.00"}))
+			return nil
+		})
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("should not notify about runs the rules leave out", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getReconExecutorSuite(ctrl)
+		// any call fails the test
+		mockNotifier := NewMockNotifier(ctrl)
+		reconExecutor := suite.reconExecutor.WithNotifiers(NotifyRules{OnFailure: true, UnmatchedCountAbove: 5}, nil, log.New(io.Discard, "", 0), mockNotifier)
+
+		suite.mockTransactionStorage.EXPECT().GetTransactions(gomock.Any(), transactionPath, startDate, endDate).Return([]Transaction{{ID: "1", Amount: 100, Type: Debit, Time: startDate}}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bca.xlsx", startDate, endDate).Return([]BankStatement{{Bank: "BCA", Amount: 100, Time: startDate}}, LoadReport{}, nil)
+		suite.mockBankStatementRepoStorage.EXPECT().GetBankStatements(gomock.Any(), "bri.xlsx", startDate, endDate).Return(nil, LoadReport{}, nil)
+		suite.mockSummaryRepoStorage.EXPECT().StoreSummary(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockTransactionStorage.EXPECT().StoreTransactions(gomock.Any(), gomock.Any()).Return(nil)
+		suite.mockReportRepoStorage.EXPECT().StoreReport(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := reconExecutor.Execute(context.Background(), transactionPath, bankStatementPaths, startDate, endDate)
+		g.Expect(err).Should(BeNil())
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	t.Run("should return matches and discrepancies as values", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100.0, Type: Debit, Time: day.Add(10 * time.Hour)},
+			{ID: "2", Amount: 250.0, Type: Debit, Time: day.Add(26 * time.Hour)},
+		}
+		statements := []BankStatement{
+			{Bank: "BCA", ID: "a", Amount: 100.0, Time: day.Add(12 * time.Hour)},
+			{Bank: "BCA", ID: "b", Amount: 300.0, Time: day.Add(50 * time.Hour)},
+		}
+
+		result, err := Reconcile(transactions, statements, Options{})
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(result.Matches).Should(Equal([]Match{{Transaction: transactions[0], BankStatement: statements[0]}}))
+		g.Expect(result.UnmatchedTransactions).Should(Equal([]Transaction{transactions[1]}))
+		g.Expect(result.UnmatchedBankStatements).Should(Equal([]BankStatementDiscrepancy{{Bank: "BCA", Statements: []BankStatement{statements[1]}}}))
+		g.Expect(result.Summary.MatchedTransactions).Should(Equal(1))
+		g.Expect(result.Summary.UnmatchedAmountBankStatements).Should(Equal(300.0))
+		g.Expect(result.StartDate).Should(Equal(day))
+		g.Expect(result.EndDate).Should(Equal(day.AddDate(0, 0, 2)))
+	})
+
+	t.Run("should use the overrides and FX rates of the executor", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100.0, Type: Debit, Currency: "USD", Time: day},
+			{ID: "2", Amount: 50.0, Type: Debit, Currency: "USD", Time: day},
+		}
+		statements := []BankStatement{{Bank: "BCA", ID: "a", Amount: 100.0, Currency: "USD", Time: day}}
+
+		reconExecutor := NewReconExecutor(nil, nil, nil).
+			WithOptions(Options{ReportingCurrency: "IDR"}).
+			WithFXRates(NewFXRates([]FXRate{{Date: day, Base: "USD", Quote: "IDR", Rate: 16000}})).
+			WithOverrides([]Override{{Action: OverrideExclude, TransactionIDs: []string{"2"}}})
+
+		result, err := reconExecutor.Reconcile(transactions, statements)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(result.Matches).Should(HaveLen(1))
+		g.Expect(result.Exclusions).Should(HaveLen(1))
+		g.Expect(result.Summary.MatchedAmountTransactions).Should(Equal(1600000.0))
+		g.Expect(transactions[0].Currency).Should(Equal("USD"))
+	})
+
+	t.Run("should return error when options are invalid", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		_, err := Reconcile(nil, nil, Options{Match: MatchConfig{FXTolerance: -1}})
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error when the summary does not convert", func(t *testing.T) {
+		g := NewGomegaWithT(t)
 
-		err := suite.reconExecutor.Execute(transactionPath, bankStatementPaths, startDate, endDate)
+		_, err := Reconcile([]Transaction{{ID: "1", Amount: 100.0, Currency: "USD", Time: day}}, nil, Options{ReportingCurrency: "IDR"})
 		g.Expect(err).ShouldNot(BeNil())
 	})
 }
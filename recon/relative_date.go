@@ -0,0 +1,105 @@
+package recon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDate reads a day written as YYYY-MM-DD or relative to the day of now:
+// today, yesterday, N days ago, N business days ago, start of week, start of
+// month, start of last month or end of last month. Hyphens may stand for
+// spaces, e.g. 3-days-ago. Business days are those of calendar for every
+// bank. The day is in the time zone of now.
+func ParseDate(expr string, now time.Time, calendar Calendar) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	if day, err := time.ParseInLocation(time.DateOnly, expr, now.Location()); err == nil {
+		return day, nil
+	}
+
+	today := truncateToDay(now)
+	words := strings.Fields(strings.ReplaceAll(strings.ToLower(expr), "-", " "))
+	switch strings.Join(words, " ") {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "start of week":
+		// weeks start on Monday
+		return today.AddDate(0, 0, -(int(today.Weekday())+6)%7), nil
+	case "start of month":
+		return today.AddDate(0, 0, 1-today.Day()), nil
+	case "start of last month":
+		return today.AddDate(0, 0, 1-today.Day()).AddDate(0, -1, 0), nil
+	case "end of last month":
+		return today.AddDate(0, 0, -today.Day()), nil
+	}
+
+	if len(words) >= 3 && words[len(words)-1] == "ago" {
+		n, err := strconv.Atoi(words[0])
+		unit := strings.Join(words[1:len(words)-1], " ")
+		if err == nil && n >= 0 {
+			switch unit {
+			case "day", "days":
+				return today.AddDate(0, 0, -n), nil
+			case "business day", "business days":
+				return businessDaysBefore(today, n, calendar), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD, today, yesterday, N days ago, N business days ago, start of week, start of month, start of last month or end of last month", expr)
+}
+
+// ParseDateRange reads a window of days relative to the day of now: month to
+// date, week to date, last month, last N days or last N business days, or a
+// single day as ParseDate reads it. The last N days and business days end
+// before today. Hyphens may stand for spaces, e.g. month-to-date.
+func ParseDateRange(expr string, now time.Time, calendar Calendar) (time.Time, time.Time, error) {
+	today := truncateToDay(now)
+	words := strings.Fields(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(expr)), "-", " "))
+	switch strings.Join(words, " ") {
+	case "month to date":
+		start, _ := ParseDate("start of month", now, calendar)
+		return start, today, nil
+	case "week to date":
+		start, _ := ParseDate("start of week", now, calendar)
+		return start, today, nil
+	case "last month":
+		start, _ := ParseDate("start of last month", now, calendar)
+		end, _ := ParseDate("end of last month", now, calendar)
+		return start, end, nil
+	}
+
+	if len(words) >= 3 && words[0] == "last" {
+		n, err := strconv.Atoi(words[1])
+		unit := strings.Join(words[2:], " ")
+		if err == nil && n > 0 {
+			switch unit {
+			case "day", "days":
+				return today.AddDate(0, 0, -n), today.AddDate(0, 0, -1), nil
+			case "business day", "business days":
+				return businessDaysBefore(today, n, calendar), businessDaysBefore(today, 1, calendar), nil
+			}
+		}
+	}
+
+	day, err := ParseDate(expr, now, calendar)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range %q, expected month to date, week to date, last month, last N days, last N business days or a single day", expr)
+	}
+	return day, day, nil
+}
+
+// businessDaysBefore steps back n business days of calendar from today, for
+// every bank. Zero days is today.
+func businessDaysBefore(today time.Time, n int, calendar Calendar) time.Time {
+	day := today
+	for n > 0 {
+		day = day.AddDate(0, 0, -1)
+		if calendar.IsBusinessDay(day, "") {
+			n--
+		}
+	}
+	return day
+}
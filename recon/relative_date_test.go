@@ -0,0 +1,89 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseDate(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	// a Wednesday
+	now := time.Date(2025, 8, 13, 6, 0, 0, 0, jakarta)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, jakarta) }
+	calendar := NewCalendar([]Holiday{{Date: day(8, 11), Name: "Holiday"}})
+
+	for _, tt := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"2025-07-04", day(7, 4)},
+		{"today", day(8, 13)},
+		{"Yesterday", day(8, 12)},
+		{"3 days ago", day(8, 10)},
+		{"1-day-ago", day(8, 12)},
+		{"2 business days ago", day(8, 8)},
+		{"start of week", day(8, 11)},
+		{"start-of-month", day(8, 1)},
+		{"start of last month", day(7, 1)},
+		{"end of last month", day(7, 31)},
+	} {
+		t.Run(tt.expr, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			got, err := ParseDate(tt.expr, now, calendar)
+			g.Expect(err).Should(BeNil())
+			g.Expect(got).Should(Equal(tt.want))
+		})
+	}
+
+	t.Run("rejects unknown expressions", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		for _, expr := range []string{"", "tomorrow", "x days ago", "3 weeks ago", "2025-13-01"} {
+			_, err := ParseDate(expr, now, calendar)
+			g.Expect(err).ShouldNot(BeNil(), expr)
+		}
+	})
+}
+
+func TestParseDateRange(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	// a Wednesday
+	now := time.Date(2025, 8, 13, 6, 0, 0, 0, jakarta)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, jakarta) }
+	calendar := NewCalendar([]Holiday{{Date: day(8, 11), Name: "Holiday"}})
+
+	for _, tt := range []struct {
+		expr       string
+		start, end time.Time
+	}{
+		{"yesterday", day(8, 12), day(8, 12)},
+		{"2025-08-01", day(8, 1), day(8, 1)},
+		{"month-to-date", day(8, 1), day(8, 13)},
+		{"week to date", day(8, 11), day(8, 13)},
+		{"last month", day(7, 1), day(7, 31)},
+		{"last 7 days", day(8, 6), day(8, 12)},
+		// skips the weekend and the holiday on Monday
+		{"last 3 business days", day(8, 7), day(8, 12)},
+	} {
+		t.Run(tt.expr, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			start, end, err := ParseDateRange(tt.expr, now, calendar)
+			g.Expect(err).Should(BeNil())
+			g.Expect(start).Should(Equal(tt.start))
+			g.Expect(end).Should(Equal(tt.end))
+		})
+	}
+
+	t.Run("rejects unknown expressions", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		for _, expr := range []string{"", "next week", "last 0 days", "last x business days"} {
+			_, _, err := ParseDateRange(expr, now, calendar)
+			g.Expect(err).ShouldNot(BeNil(), expr)
+		}
+	})
+}
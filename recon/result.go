@@ -0,0 +1,205 @@
+package recon
+
+import (
+	"time"
+)
+
+// Result is everything a recon run computed, handed to report storages.
+type Result struct {
+	RunAt     time.Time
+	Options   Options
+	StartDate time.Time
+	EndDate   time.Time
+
+	TransactionInput    LoadReport
+	BankStatementInputs []LoadReport
+	// ConfigInputs are the files the overrides, declared balances, FX rates
+	// and holidays were read from.
+	ConfigInputs []ConfigInput
+
+	Summary                 Summary
+	Matches                 []Match
+	UnmatchedTransactions   []Transaction
+	UnmatchedBankStatements []BankStatementDiscrepancy
+
+	// CarriedForward are the open ledger items from prior periods that took
+	// part in this run.
+	CarriedForward []LedgerItem
+
+	// ManualMatches, ManualUnmatches and Exclusions are the overrides that
+	// applied to this run. Manually unmatched items are also listed with the
+	// unmatched items; excluded items are left out of everything else.
+	ManualMatches      []AppliedOverride
+	ManualUnmatches    []AppliedOverride
+	Exclusions         []AppliedOverride
+	UnappliedOverrides []Override
+
+	// DuplicateTransactions and DuplicateBankStatements are the later copies
+	// of repeated items. Options.Duplicates says whether they were left out.
+	DuplicateTransactions   []DuplicateTransaction
+	DuplicateBankStatements []DuplicateBankStatement
+
+	// TransactionReversals and BankStatementReversals are the items paired
+	// with their reversal before matching. They net to zero and are left out
+	// of matching and the summary.
+	TransactionReversals   []TransactionReversal
+	BankStatementReversals []BankStatementReversal
+
+	// FXRates are the rates amounts were converted with.
+	FXRates FXRates
+	// Calendar counts the age of unmatched items in business days.
+	Calendar Calendar
+	// Cutoffs are the times of day at which the business day of banks ended.
+	Cutoffs map[string]time.Duration
+}
+
+// AppliedOverrides lists the manual matches, manual unmatches and exclusions.
+func (r Result) AppliedOverrides() []AppliedOverride {
+	var applied []AppliedOverride
+	applied = append(applied, r.ManualMatches...)
+	applied = append(applied, r.ManualUnmatches...)
+	return append(applied, r.Exclusions...)
+}
+
+// RunID identifies the run in the ledger.
+func (r Result) RunID() string {
+	return r.RunAt.UTC().Format("20060102T150405.000Z")
+}
+
+// Settled returns the items that need no further attention: matched
+// automatically or manually, excluded, or netted out by a reversal.
+func (r Result) Settled() ([]Transaction, []BankStatement) {
+	var transactions []Transaction
+	var statements []BankStatement
+	for _, m := range r.Matches {
+		transactions = append(transactions, m.Transaction)
+		statements = append(statements, m.BankStatement)
+	}
+	for _, applied := range r.AppliedOverrides() {
+		if applied.Action == OverrideUnmatch {
+			continue
+		}
+		transactions = append(transactions, applied.Transactions...)
+		statements = append(statements, applied.BankStatements...)
+	}
+	for _, reversal := range r.TransactionReversals {
+		transactions = append(transactions, reversal.Original, reversal.Reversal)
+	}
+	for _, reversal := range r.BankStatementReversals {
+		statements = append(statements, reversal.Original, reversal.Reversal)
+	}
+	return transactions, statements
+}
+
+// Cleared returns the carried forward items settled by this run.
+func (r Result) Cleared() []LedgerItem {
+	matched := map[string]bool{}
+	transactions, statements := r.Settled()
+	for _, t := range transactions {
+		matched[transactionLedgerKey(t)] = true
+	}
+	for _, s := range statements {
+		matched[bankStatementLedgerKey(s)] = true
+	}
+
+	var cleared []LedgerItem
+	for _, item := range r.CarriedForward {
+		if matched[item.Key()] {
+			cleared = append(cleared, item)
+		}
+	}
+	return cleared
+}
+
+// Inputs lists the load report of the transaction file followed by the bank statement files.
+func (r Result) Inputs() []LoadReport {
+	return append([]LoadReport{r.TransactionInput}, r.BankStatementInputs...)
+}
+
+// RejectedRows collects the rejected rows of every input file.
+func (r Result) RejectedRows() []RejectedRow {
+	var rows []RejectedRow
+	for _, input := range r.Inputs() {
+		rows = append(rows, input.RejectedRows...)
+	}
+	return rows
+}
+
+// BankStatementDiscrepancy holds the unmatched bank statements of one bank.
+type BankStatementDiscrepancy struct {
+	Bank       string
+	Account    string
+	Statements []BankStatement
+}
+
+func (b BankStatementDiscrepancy) BankAccount() BankAccount {
+	return BankAccount{Bank: b.Bank, Account: b.Account}
+}
+
+// Amount is the sum of the unmatched statement amounts.
+func (b BankStatementDiscrepancy) Amount() float64 {
+	var amount float64
+	for _, s := range b.Statements {
+		amount += s.Amount
+	}
+	return amount
+}
+
+// MatchRate is the share of processed items, on both sides, that were matched.
+func (r Result) MatchRate() float64 {
+	if r.Summary.TotalProcessed() == 0 {
+		return 0
+	}
+	matched := r.Summary.MatchedTransactions + r.Summary.MatchedBankStatements
+	return float64(matched) / float64(r.Summary.TotalProcessed())
+}
+
+// DailyCount is the number of matched and unmatched items dated on one day.
+type DailyCount struct {
+	Date      time.Time
+	Matched   int
+	Unmatched int
+}
+
+// DailyTrend counts matched and unmatched items per day from StartDate to
+// EndDate inclusive, in the time zone of StartDate. A match is counted once,
+// on the transaction date.
+func (r Result) DailyTrend() []DailyCount {
+	location := r.StartDate.Location()
+	start := truncateToDay(r.StartDate)
+	end := truncateToDay(r.EndDate.In(location))
+	if end.Before(start) {
+		return nil
+	}
+
+	var days []DailyCount
+	index := map[time.Time]int{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		index[day] = len(days)
+		days = append(days, DailyCount{Date: day})
+	}
+
+	for _, m := range r.Matches {
+		if i, ok := index[truncateToDay(m.Transaction.Time.In(location))]; ok {
+			days[i].Matched++
+		}
+	}
+	for _, t := range r.UnmatchedTransactions {
+		if i, ok := index[truncateToDay(t.Time.In(location))]; ok {
+			days[i].Unmatched++
+		}
+	}
+	for _, group := range r.UnmatchedBankStatements {
+		for _, s := range group.Statements {
+			if i, ok := index[truncateToDay(s.Time.In(location))]; ok {
+				days[i].Unmatched++
+			}
+		}
+	}
+	return days
+}
+
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,114 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestResult_MatchRate(t *testing.T) {
+	t.Run("share of matched items on both sides", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		result := Result{Summary: Summary{
+			TotalTransactions:       2,
+			MatchedTransactions:     1,
+			UnmatchedTransactions:   1,
+			TotalBankStatements:     2,
+			MatchedBankStatements:   1,
+			UnmatchedBankStatements: 1,
+		}}
+
+		g.Expect(result.MatchRate()).Should(Equal(0.5))
+	})
+
+	t.Run("zero when nothing was processed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Result{}.MatchRate()).Should(Equal(0.0))
+	})
+}
+
+func TestResult_DailyTrend(t *testing.T) {
+	day1, _ := time.Parse(time.DateOnly, "2025-08-01")
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	t.Run("counts matched and unmatched items per day in range", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		result := Result{
+			StartDate: day1,
+			EndDate:   day3,
+			Matches: []Match{
+				{Transaction: Transaction{ID: "1", Time: day1.Add(3 * time.Hour)}, BankStatement: BankStatement{ID: "a", Time: day2}},
+			},
+			UnmatchedTransactions: []Transaction{
+				{ID: "2", Time: day3},
+				{ID: "3", Time: day3.AddDate(0, 0, 5)}, // out of range
+			},
+			UnmatchedBankStatements: []BankStatementDiscrepancy{
+				{Bank: "BCA", Statements: []BankStatement{{ID: "b", Time: day1}, {ID: "c", Time: day3}}},
+			},
+		}
+
+		g.Expect(result.DailyTrend()).Should(Equal([]DailyCount{
+			{Date: day1, Matched: 1, Unmatched: 1},
+			{Date: day2, Matched: 0, Unmatched: 0},
+			{Date: day3, Matched: 0, Unmatched: 2},
+		}))
+	})
+
+	t.Run("counts items on their day in the time zone of the start date", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		start := time.Date(2025, 8, 1, 0, 0, 0, 0, jakarta)
+		result := Result{
+			StartDate: start,
+			EndDate:   start.AddDate(0, 0, 1),
+			UnmatchedTransactions: []Transaction{
+				{ID: "1", Time: time.Date(2025, 7, 31, 17, 0, 0, 0, time.UTC)},
+				{ID: "2", Time: time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC)},
+			},
+		}
+
+		g.Expect(result.DailyTrend()).Should(Equal([]DailyCount{
+			{Date: start, Unmatched: 1},
+			{Date: start.AddDate(0, 0, 1), Unmatched: 1},
+		}))
+	})
+
+	t.Run("empty when end date is before start date", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(Result{StartDate: day2, EndDate: day1}.DailyTrend()).Should(BeEmpty())
+	})
+}
+
+func TestBankStatementDiscrepancy_Amount(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	group := BankStatementDiscrepancy{Bank: "BCA", Statements: []BankStatement{{Amount: 100}, {Amount: 50.5}}}
+
+	g.Expect(group.Amount()).Should(Equal(150.5))
+}
+
+func TestResult_RejectedRows(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	result := Result{
+		TransactionInput: LoadReport{Path: "transaction.csv", RejectedRows: []RejectedRow{{Path: "transaction.csv", Line: 2}}},
+		BankStatementInputs: []LoadReport{
+			{Path: "bca.csv"},
+			{Path: "bri.csv", RejectedRows: []RejectedRow{{Path: "bri.csv", Line: 5}}},
+		},
+	}
+
+	g.Expect(result.Inputs()).Should(HaveLen(3))
+	g.Expect(result.RejectedRows()).Should(Equal([]RejectedRow{
+		{Path: "transaction.csv", Line: 2},
+		{Path: "bri.csv", Line: 5},
+	}))
+}
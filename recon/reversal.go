@@ -0,0 +1,130 @@
+package recon
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReversalConfig controls the pairing of items with their reversal on the
+// same side before matching. The zero value pairs none.
+type ReversalConfig struct {
+	// Window is the longest time from an item to its reversal. Zero
+	// disables pairing.
+	Window time.Duration `yaml:"window"`
+}
+
+// Validate checks that the window is not negative.
+func (r ReversalConfig) Validate() error {
+	if r.Window < 0 {
+		return fmt.Errorf("reversal window must not be negative: %v", r.Window)
+	}
+	return nil
+}
+
+// TransactionReversal is a transaction and the later transaction of the
+// opposite type and the same amount that reverses it.
+type TransactionReversal struct {
+	Original Transaction
+	Reversal Transaction
+}
+
+// BankStatementReversal is a bank line and the later line of the same account
+// with the opposite amount that references it.
+type BankStatementReversal struct {
+	Original BankStatement
+	Reversal BankStatement
+}
+
+// pairReversals pairs each of n items with the earliest unpaired later item
+// within window that reverses it, going through the items by time and then
+// load order. It returns the index pairs, original first.
+func pairReversals(n int, window time.Duration, at func(i int) time.Time, reverses func(i, j int) bool) [][2]int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return at(order[a]).Before(at(order[b]))
+	})
+
+	var pairs [][2]int
+	paired := make([]bool, n)
+	for a, i := range order {
+		if paired[i] {
+			continue
+		}
+		for _, j := range order[a+1:] {
+			if at(j).Sub(at(i)) > window {
+				break
+			}
+			if !paired[j] && reverses(i, j) {
+				paired[i], paired[j] = true, true
+				pairs = append(pairs, [2]int{i, j})
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+func transactionReverses(t, u Transaction) bool {
+	return t.Type != "" && u.Type != "" && t.Type != u.Type &&
+		t.Currency == u.Currency && amountEqual(t.Amount, u.Amount)
+}
+
+// bankStatementReverses requires the lines to reference each other: one
+// carries the ID of the other as reference, or both carry the same reference.
+func bankStatementReverses(s, u BankStatement) bool {
+	if s.BankAccount() != u.BankAccount() || s.Currency != u.Currency || s.Amount == 0 || !amountEqual(s.Amount, -u.Amount) {
+		return false
+	}
+	return (u.Reference != "" && (u.Reference == s.ID || u.Reference == s.Reference)) ||
+		(s.Reference != "" && s.Reference == u.ID)
+}
+
+// reversals are the reversal pairs found in a run.
+type reversals struct {
+	transactions []TransactionReversal
+	statements   []BankStatementReversal
+}
+
+// apply pairs reversals among transactions and among statements, returning
+// the items left to match in load order.
+func (r ReversalConfig) apply(transactions []Transaction, statements []BankStatement) (reversals, []Transaction, []BankStatement) {
+	var found reversals
+	if r.Window <= 0 {
+		return found, transactions, statements
+	}
+
+	pairedTransactions := make([]bool, len(transactions))
+	for _, pair := range pairReversals(len(transactions), r.Window,
+		func(i int) time.Time { return transactions[i].Time },
+		func(i, j int) bool { return transactionReverses(transactions[i], transactions[j]) },
+	) {
+		found.transactions = append(found.transactions, TransactionReversal{Original: transactions[pair[0]], Reversal: transactions[pair[1]]})
+		pairedTransactions[pair[0]], pairedTransactions[pair[1]] = true, true
+	}
+	pairedStatements := make([]bool, len(statements))
+	for _, pair := range pairReversals(len(statements), r.Window,
+		func(i int) time.Time { return statements[i].Time },
+		func(i, j int) bool { return bankStatementReverses(statements[i], statements[j]) },
+	) {
+		found.statements = append(found.statements, BankStatementReversal{Original: statements[pair[0]], Reversal: statements[pair[1]]})
+		pairedStatements[pair[0]], pairedStatements[pair[1]] = true, true
+	}
+
+	var remainingTransactions []Transaction
+	for i, t := range transactions {
+		if !pairedTransactions[i] {
+			remainingTransactions = append(remainingTransactions, t)
+		}
+	}
+	var remainingStatements []BankStatement
+	for i, s := range statements {
+		if !pairedStatements[i] {
+			remainingStatements = append(remainingStatements, s)
+		}
+	}
+	return found, remainingTransactions, remainingStatements
+}
@@ -0,0 +1,67 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReversalsStorage lists the items netted out by their reversal on the same
+// side before matching.
+type ReversalsStorage struct {
+	destinationFileNamePath string
+	destinationSheetName    string
+	excelWriterFactory      ExcelWriterFactory
+}
+
+func NewReversalsStorage(destinationFileNamePath string, destinationSheetName string, excelWriterFactory ExcelWriterFactory) ReversalsStorage {
+	return ReversalsStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		destinationSheetName:    destinationSheetName,
+		excelWriterFactory:      excelWriterFactory,
+	}
+}
+
+func (r ReversalsStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := r.excelWriterFactory.New(r.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	index, err := f.GetSheetIndex(r.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet index: %w", err)
+	}
+
+	if index == -1 {
+		_, err = f.NewSheet(r.destinationSheetName)
+		if err != nil {
+			return fmt.Errorf("failed to create sheet: %w", err)
+		}
+	}
+
+	rows := [][]any{{"Side", "Original ID", "Original Amount", "Original Time", "Reversal ID", "Reversal Amount", "Reversal Time"}}
+	for _, reversal := range result.TransactionReversals {
+		o, v := reversal.Original, reversal.Reversal
+		rows = append(rows, []any{agingSideTransactions, o.ID, o.Amount, o.Time.Format(time.RFC3339), v.ID, v.Amount, v.Time.Format(time.RFC3339)})
+	}
+	for _, reversal := range result.BankStatementReversals {
+		o, v := reversal.Original, reversal.Reversal
+		rows = append(rows, []any{o.BankAccount().String(), o.ID, o.Amount, o.Time.Format(time.RFC3339), v.ID, v.Amount, v.Time.Format(time.RFC3339)})
+	}
+
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			f.SetCellValue(r.destinationSheetName, cell, v)
+		}
+	}
+
+	err = f.SaveAs(r.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("save as error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReversalsStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.xlsx"
+	destinationSheetName := "Reversals"
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+
+	result := Result{
+		TransactionReversals: []TransactionReversal{{
+			Original: Transaction{ID: "1", Amount: 100, Type: Debit, Time: day},
+			Reversal: Transaction{ID: "2", Amount: 100, Type: Credit, Time: day.Add(time.Hour)},
+		}},
+		BankStatementReversals: []BankStatementReversal{{
+			Original: BankStatement{Bank: "bca", Account: "111", ID: "a", Amount: 50, Time: day},
+			Reversal: BankStatement{Bank: "bca", Account: "111", ID: "b", Amount: -50, Time: day.Add(2 * time.Hour), Reference: "a"},
+		}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		reversalsStorage := NewReversalsStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		cells := map[string]any{
+			"A1": "Side", "B1": "Original ID", "C1": "Original Amount", "D1": "Original Time", "E1": "Reversal ID", "F1": "Reversal Amount", "G1": "Reversal Time",
+			"A2": "transactions", "B2": "1", "C2": 100.0, "D2": "2025-08-01T00:00:00Z", "E2": "2", "F2": 100.0, "G2": "2025-08-01T01:00:00Z",
+			"A3": "bca 111", "B3": "a", "C3": 50.0, "D3": "2025-08-01T00:00:00Z", "E3": "b", "F3": -50.0, "G3": "2025-08-01T02:00:00Z",
+		}
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(4, nil)
+		for cell, v := range cells {
+			mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := reversalsStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		reversalsStorage := NewReversalsStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, errors.New("open file error"))
+
+		err := reversalsStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("save as error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		mockExcelWriter := NewMockExcelWriter(ctrl)
+		mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+		reversalsStorage := NewReversalsStorage(destinationFileNamePath, destinationSheetName, mockExcelWriterFactory)
+
+		mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(mockExcelWriter, nil)
+		mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
+
+		err := reversalsStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
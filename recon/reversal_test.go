@@ -0,0 +1,83 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestReversalConfig_Apply(t *testing.T) {
+	day, _ := time.Parse(time.DateOnly, "2025-08-01")
+	config := ReversalConfig{Window: 48 * time.Hour}
+
+	t.Run("pairs nothing without a window", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100, Type: Debit, Time: day},
+			{ID: "2", Amount: 100, Type: Credit, Time: day},
+		}
+
+		found, remaining, _ := ReversalConfig{}.apply(transactions, nil)
+
+		g.Expect(found).Should(Equal(reversals{}))
+		g.Expect(remaining).Should(Equal(transactions))
+	})
+
+	t.Run("pairs transactions of opposite type within the window", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		transactions := []Transaction{
+			{ID: "1", Amount: 100, Type: Debit, Time: day},
+			{ID: "2", Amount: 100, Type: Debit, Time: day.Add(time.Hour)},
+			{ID: "3", Amount: 50, Type: Credit, Time: day.Add(2 * time.Hour)},
+			{ID: "4", Amount: 100, Type: Credit, Time: day.Add(3 * time.Hour)},
+			{ID: "5", Amount: 100, Type: Credit, Time: day.Add(72 * time.Hour)},
+		}
+
+		found, remaining, _ := config.apply(transactions, nil)
+
+		g.Expect(found.transactions).Should(Equal([]TransactionReversal{
+			{Original: transactions[0], Reversal: transactions[3]},
+		}))
+		g.Expect(remaining).Should(Equal([]Transaction{transactions[1], transactions[2], transactions[4]}))
+	})
+
+	t.Run("pairs the earlier item as original whatever the load order", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		transactions := []Transaction{
+			{ID: "refund", Amount: 100, Type: Credit, Time: day.Add(time.Hour)},
+			{ID: "charge", Amount: 100, Type: Debit, Time: day},
+		}
+
+		found, remaining, _ := config.apply(transactions, nil)
+
+		g.Expect(found.transactions).Should(Equal([]TransactionReversal{
+			{Original: transactions[1], Reversal: transactions[0]},
+		}))
+		g.Expect(remaining).Should(BeEmpty())
+	})
+
+	t.Run("pairs bank lines of opposite sign referencing each other", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		statements := []BankStatement{
+			{Bank: "bca", ID: "a", Amount: 100, Time: day},
+			{Bank: "bca", ID: "b", Amount: -100, Time: day.Add(time.Hour)},
+			{Bank: "bca", ID: "c", Amount: -100, Time: day.Add(2 * time.Hour), Reference: "a"},
+			{Bank: "bca", ID: "d", Amount: 70, Time: day, Reference: "R9"},
+			{Bank: "bri", ID: "e", Amount: -70, Time: day, Reference: "R9"},
+			{Bank: "bca", ID: "f", Amount: -70, Time: day.Add(time.Hour), Reference: "R9"},
+		}
+
+		found, _, remaining := config.apply(nil, statements)
+
+		g.Expect(found.statements).Should(Equal([]BankStatementReversal{
+			{Original: statements[0], Reversal: statements[2]},
+			{Original: statements[3], Reversal: statements[5]},
+		}))
+		g.Expect(remaining).Should(Equal([]BankStatement{statements[1], statements[4]}))
+	})
+}
@@ -0,0 +1,99 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RunInfoStorage writes the provenance of a run, enough to reproduce the
+// report: tool version, arguments, period, checksums and row counts of the
+// inputs and of the files configuring the run, and the matching
+// configuration.
+type RunInfoStorage struct {
+	destinationFileNamePath string
+	destinationSheetName    string
+	excelWriterFactory      ExcelWriterFactory
+}
+
+func NewRunInfoStorage(destinationFileNamePath string, destinationSheetName string, excelWriterFactory ExcelWriterFactory) RunInfoStorage {
+	return RunInfoStorage{
+		destinationFileNamePath: destinationFileNamePath,
+		destinationSheetName:    destinationSheetName,
+		excelWriterFactory:      excelWriterFactory,
+	}
+}
+
+func (s RunInfoStorage) StoreReport(ctx context.Context, result Result) error {
+	f, err := s.excelWriterFactory.New(s.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	index, err := f.GetSheetIndex(s.destinationSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet index: %w", err)
+	}
+
+	if index == -1 {
+		_, err = f.NewSheet(s.destinationSheetName)
+		if err != nil {
+			return fmt.Errorf("failed to create sheet: %w", err)
+		}
+	}
+
+	rows := [][]any{
+		{"Tool Version", Version},
+		{"Run At", result.RunAt.Format(time.RFC3339)},
+		{"Arguments", strings.Join(result.Options.RunArguments, " ")},
+		{"Start Date", result.StartDate.Format(time.DateOnly)},
+		{"End Date", result.EndDate.Format(time.DateOnly)},
+		{"Reporting Currency", result.Options.ReportingCurrency},
+		{"FX Tolerance", result.Options.Match.FXTolerance},
+		{"Timezone", result.StartDate.Location().String()},
+		{"Bank Cutoffs", formatCutoffs(result.Cutoffs)},
+		{"Settlement Days", result.Options.Match.SettlementDays},
+		{"Calendar", result.Calendar.describe()},
+		{"Duplicate Keys", runInfoDuplicateKeys(result.Options.Duplicates.Keys)},
+		{"Duplicate Policy", string(result.Options.Duplicates.policy())},
+		{"Reversal Window", result.Options.Reversals.Window.String()},
+		{"Load Workers", result.Options.LoadWorkers},
+		{},
+		{"Kind", "Path", "SHA-256", "Rows Read", "Rows Filtered", "Rows Rejected", "Rows Loaded", "Load Seconds"},
+	}
+	rows = append(rows, runInfoInputRow("transactions", result.TransactionInput))
+	for _, input := range result.BankStatementInputs {
+		rows = append(rows, runInfoInputRow("bank statements", input))
+	}
+	for _, input := range result.ConfigInputs {
+		rows = append(rows, runInfoInputRow(strings.ReplaceAll(input.Kind, "_", " "), input.LoadReport))
+	}
+
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			f.SetCellValue(s.destinationSheetName, cell, v)
+		}
+	}
+
+	err = f.SaveAs(s.destinationFileNamePath)
+	if err != nil {
+		return fmt.Errorf("save as error: %w", err)
+	}
+	return nil
+}
+
+func runInfoDuplicateKeys(keys []DuplicateKey) string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = string(key)
+	}
+	return strings.Join(names, ",")
+}
+
+func runInfoInputRow(kind string, report LoadReport) []any {
+	return []any{kind, report.Path, report.SHA256, report.RowsRead, report.RowsFiltered, report.RowsRejected(), report.RowsLoaded(), report.Duration.Seconds()}
+}
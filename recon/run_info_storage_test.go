@@ -0,0 +1,244 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+type RunInfoStorageSuite struct {
+	mockExcelWriter        *MockExcelWriter
+	mockExcelWriterFactory *MockExcelWriterFactory
+	runInfoStorage         RunInfoStorage
+}
+
+func runInfoStorageSuite(ctrl *gomock.Controller) RunInfoStorageSuite {
+	mockExcelWriter := NewMockExcelWriter(ctrl)
+	mockExcelWriterFactory := NewMockExcelWriterFactory(ctrl)
+
+	return RunInfoStorageSuite{
+		mockExcelWriter:        mockExcelWriter,
+		mockExcelWriterFactory: mockExcelWriterFactory,
+		runInfoStorage:         NewRunInfoStorage("test.xlsx", "Run Info", mockExcelWriterFactory),
+	}
+}
+
+func TestRunInfoStorage_StoreReport(t *testing.T) {
+	destinationFileNamePath := "test.xlsx"
+	destinationSheetName := "Run Info"
+	startDate, _ := time.Parse(time.DateOnly, "2025-08-01")
+	endDate, _ := time.Parse(time.DateOnly, "2025-08-31")
+
+	result := Result{
+		RunAt: time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC),
+		Options: Options{
+			RunArguments: []string{"-start-date=2025-08-01", "-end-date=2025-08-31"},
+		},
+		StartDate:        startDate,
+		EndDate:          endDate,
+		TransactionInput: LoadReport{Path: "transaction.csv", SHA256: "abc", RowsRead: 10, RowsFiltered: 2, Duration: 1500 * time.Millisecond},
+		BankStatementInputs: []LoadReport{
+			{Path: "bca.csv", SHA256: "def", RowsRead: 5, RejectedRows: []RejectedRow{{Line: 3}}},
+		},
+		ConfigInputs: []ConfigInput{
+			{Kind: ConfigInputDeclaredBalances, LoadReport: LoadReport{Path: "balances.csv", SHA256: "ghi", RowsRead: 2}},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := runInfoStorageSuite(ctrl)
+
+		cells := map[string]any{
+			"A1": "Tool Version", "B1": Version,
+			"A2": "Run At", "B2": "2025-09-01T08:00:00Z",
+			"A3": "Arguments", "B3": "-start-date=2025-08-01 -end-date=2025-08-31",
+			"A4": "Start Date", "B4": "2025-08-01",
+			"A5": "End Date", "B5": "2025-08-31",
+			"A6": "Reporting Currency", "B6": "",
+			"A7": "FX Tolerance", "B7": 0.0,
+			"A8": "Timezone", "B8": "UTC",
+			"A9": "Bank Cutoffs", "B9": "",
+			"A10": "Settlement Days", "B10": 0,
+			"A11": "Calendar", "B11": "calendar days",
+			"A12": "Duplicate Keys", "B12": "",
+			"A13": "Duplicate Policy", "B13": "flag",
+			"A14": "Reversal Window", "B14": "0s",
+			"A15": "Load Workers", "B15": 0,
+			"A17": "Kind", "B17": "Path", "C17": "SHA-256", "D17": "Rows Read", "E17": "Rows Filtered", "F17": "Rows Rejected", "G17": "Rows Loaded", "H17": "Load Seconds",
+			"A18": "transactions", "B18": "transaction.csv", "C18": "abc", "D18": 10, "E18": 2, "F18": 0, "G18": 8, "H18": 1.5,
+			"A19": "bank statements", "B19": "bca.csv", "C19": "def", "D19": 5, "E19": 0, "F19": 1, "G19": 4, "H19": 0.0,
+			"A20": "declared balances", "B20": "balances.csv", "C20": "ghi", "D20": 2, "E20": 0, "F20": 0, "G20": 2, "H20": 0.0,
+		}
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(2, nil)
+		for cell, v := range cells {
+			suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := suite.runInfoStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("records the reporting currency and FX tolerance", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		fxResult := result
+		fxResult.Options.ReportingCurrency = "IDR"
+		fxResult.Options.Match.FXTolerance = 0.02
+
+		settings := storedRunInfoSettings(t, fxResult)
+
+		g.Expect(settings).Should(HaveKeyWithValue("Reporting Currency", "IDR"))
+		g.Expect(settings).Should(HaveKeyWithValue("FX Tolerance", 0.02))
+	})
+
+	t.Run("records the timezone and the bank cutoffs", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		jakarta, _ := time.LoadLocation("Asia/Jakarta")
+		cutoffResult := result
+		cutoffResult.StartDate = time.Date(2025, 8, 1, 0, 0, 0, 0, jakarta)
+		cutoffResult.EndDate = time.Date(2025, 8, 31, 0, 0, 0, 0, jakarta)
+		cutoffResult.Cutoffs = map[string]time.Duration{"bri": 21*time.Hour + 30*time.Minute, "bca": 22 * time.Hour}
+
+		settings := storedRunInfoSettings(t, cutoffResult)
+
+		g.Expect(settings).Should(HaveKeyWithValue("Timezone", "Asia/Jakarta"))
+		g.Expect(settings).Should(HaveKeyWithValue("Bank Cutoffs", "bca=22:00,bri=21:30"))
+	})
+
+	t.Run("records the settlement days and the calendar", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		settlementResult := result
+		settlementResult.Options.Match.SettlementDays = 2
+		settlementResult.Calendar = NewCalendar([]Holiday{
+			{Date: time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), Name: "Independence Day"},
+			{Date: time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), Name: "Bank holiday", Bank: "bca"},
+		})
+
+		settings := storedRunInfoSettings(t, settlementResult)
+
+		g.Expect(settings).Should(HaveKeyWithValue("Settlement Days", 2))
+		g.Expect(settings).Should(HaveKeyWithValue("Calendar", "business days, 2 holidays"))
+	})
+
+	t.Run("records the duplicate keys and policy", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		duplicateResult := result
+		duplicateResult.Options.Duplicates = DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID, DuplicateKeyReference}, Policy: DuplicateKeepFirst}
+
+		settings := storedRunInfoSettings(t, duplicateResult)
+
+		g.Expect(settings).Should(HaveKeyWithValue("Duplicate Keys", "id,reference"))
+		g.Expect(settings).Should(HaveKeyWithValue("Duplicate Policy", "keep-first"))
+	})
+
+	t.Run("records the reversal window", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		reversalResult := result
+		reversalResult.Options.Reversals = ReversalConfig{Window: 48 * time.Hour}
+
+		settings := storedRunInfoSettings(t, reversalResult)
+
+		g.Expect(settings).Should(HaveKeyWithValue("Reversal Window", "48h0m0s"))
+	})
+
+	t.Run("records the load workers", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		workersResult := result
+		workersResult.Options.LoadWorkers = 4
+
+		settings := storedRunInfoSettings(t, workersResult)
+
+		g.Expect(settings).Should(HaveKeyWithValue("Load Workers", 4))
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := runInfoStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, errors.New("open file error"))
+
+		err := suite.runInfoStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("create sheet error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := runInfoStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(-1, nil)
+		suite.mockExcelWriter.EXPECT().NewSheet(destinationSheetName).Return(0, errors.New("new sheet error"))
+
+		err := suite.runInfoStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+
+	t.Run("save as error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := runInfoStorageSuite(ctrl)
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
+
+		err := suite.runInfoStorage.StoreReport(context.Background(), result)
+
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+// storedRunInfoSettings stores result and returns the values written next to
+// each label of the first column.
+func storedRunInfoSettings(t *testing.T, result Result) map[string]any {
+	ctrl := gomock.NewController(t)
+	suite := runInfoStorageSuite(ctrl)
+
+	cells := map[string]any{}
+	suite.mockExcelWriterFactory.EXPECT().New("test.xlsx").Return(suite.mockExcelWriter, nil)
+	suite.mockExcelWriter.EXPECT().GetSheetIndex("Run Info").Return(1, nil)
+	suite.mockExcelWriter.EXPECT().SetCellValue("Run Info", gomock.Any(), gomock.Any()).DoAndReturn(func(_, axis string, value interface{}) error {
+		cells[axis] = value
+		return nil
+	}).AnyTimes()
+	suite.mockExcelWriter.EXPECT().SaveAs("test.xlsx").Return(nil)
+
+	err := suite.runInfoStorage.StoreReport(context.Background(), result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := map[string]any{}
+	for axis, value := range cells {
+		label, ok := value.(string)
+		if !ok || !strings.HasPrefix(axis, "A") {
+			continue
+		}
+		settings[label] = cells["B"+axis[1:]]
+	}
+	return settings
+}
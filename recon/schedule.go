@@ -0,0 +1,284 @@
+package recon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const scheduleHistoryName = "history.jsonl"
+
+// Schedule is a recon run repeated on a cron schedule over a rolling range
+// of days, e.g. "yesterday" every morning.
+type Schedule struct {
+	Name string `yaml:"name"`
+	// Cron is when the run starts, in the time zone of the config.
+	Cron string `yaml:"cron"`
+	// Range is the days reconciled, as ParseDateRange reads it relative to
+	// the time of the run.
+	Range              string   `yaml:"range"`
+	TransactionPath    string   `yaml:"transaction_path"`
+	BankStatementPaths []string `yaml:"bank_statement_paths"`
+	// Options tune the runs like the flags of a single run. The run
+	// arguments are set to the name and the range of the schedule.
+	Options Options `yaml:"options"`
+}
+
+// ScheduleConfig lists the schedules a Scheduler runs.
+type ScheduleConfig struct {
+	// Timezone is the time zone of the schedules and the days of the runs,
+	// UTC when empty.
+	Timezone string `yaml:"timezone"`
+	// HolidayPaths are holiday files that, with weekends, tell business days
+	// in ranges.
+	HolidayPaths []string `yaml:"holiday_paths"`
+	// OutputDir gets the history of the runs and one directory per schedule
+	// holding a timestamped directory of reports per run.
+	OutputDir string     `yaml:"output_dir"`
+	Schedules []Schedule `yaml:"schedules"`
+	// Notify tells about the runs of every schedule, with the workbook and
+	// the JSON report of a run attached to mails.
+	Notify NotifyConfig `yaml:"notify"`
+}
+
+// LoadScheduleConfig reads a schedule config from a YAML file opened with
+// readerFactory.
+func LoadScheduleConfig(ctx context.Context, readerFactory ReaderFactory, path string) (ScheduleConfig, error) {
+	file, err := readerFactory.Open(ctx, path)
+	if err != nil {
+		return ScheduleConfig{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return ScheduleConfig{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	var config ScheduleConfig
+	err = yaml.Unmarshal(content, &config)
+	if err != nil {
+		return ScheduleConfig{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	return config, nil
+}
+
+// ScheduledRun is one run of a schedule, as kept in the history.
+type ScheduledRun struct {
+	Schedule    string    `json:"schedule"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	StartDate   string    `json:"start_date"`
+	EndDate     string    `json:"end_date"`
+	OutputDir   string    `json:"output_dir,omitempty"`
+	Status      JobStatus `json:"status"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type scheduleRunner func(ctx context.Context, schedule Schedule, outputDir string, startDate, endDate time.Time) error
+
+// Scheduler runs schedules when they are due and keeps the history of their
+// runs. Runs missed while the scheduler was not running are not made up.
+type Scheduler struct {
+	config   ScheduleConfig
+	location *time.Location
+	calendar Calendar
+	crons    []Cron
+	logger   *log.Logger
+
+	run scheduleRunner
+	now func() time.Time
+}
+
+// NewScheduler checks the schedules of config. calendar tells the business
+// days of ranges such as "last 7 business days".
+func NewScheduler(config ScheduleConfig, calendar Calendar, logger *log.Logger) (*Scheduler, error) {
+	s := &Scheduler{config: config, location: time.UTC, calendar: calendar, logger: logger, now: time.Now}
+	s.run = s.runRecon
+	if config.Timezone != "" {
+		location, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+		s.location = location
+	}
+	if len(config.Schedules) == 0 {
+		return nil, fmt.Errorf("no schedules")
+	}
+
+	names := map[string]bool{}
+	for i, schedule := range config.Schedules {
+		if schedule.Name == "" || names[schedule.Name] {
+			return nil, fmt.Errorf("schedule %d needs a unique name", i+1)
+		}
+		names[schedule.Name] = true
+		cron, err := ParseCron(schedule.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", schedule.Name, err)
+		}
+		if cron.Next(s.now().In(s.location)).IsZero() {
+			return nil, fmt.Errorf("schedule %s never runs", schedule.Name)
+		}
+		_, _, err = ParseDateRange(schedule.Range, s.now(), calendar)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", schedule.Name, err)
+		}
+		if schedule.TransactionPath == "" || len(schedule.BankStatementPaths) == 0 {
+			return nil, fmt.Errorf("schedule %s needs a transaction path and bank statement paths", schedule.Name)
+		}
+		err = schedule.Options.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", schedule.Name, err)
+		}
+		s.crons = append(s.crons, cron)
+	}
+
+	err := os.MkdirAll(config.OutputDir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create output dir error: %w", err)
+	}
+	return s, nil
+}
+
+// Run runs the schedules when they are due until ctx is done. A run still
+// going when the next one is due delays it; due times passed meanwhile are
+// skipped.
+func (s *Scheduler) Run(ctx context.Context) error {
+	next := make([]time.Time, len(s.crons))
+	for i, cron := range s.crons {
+		next[i] = cron.Next(s.now().In(s.location))
+		s.logger.Printf("Schedule %s runs next at %s", s.config.Schedules[i].Name, next[i].Format(time.RFC3339))
+	}
+
+	for {
+		due := 0
+		for i := range next {
+			if next[i].Before(next[due]) {
+				due = i
+			}
+		}
+		timer := time.NewTimer(next[due].Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		at := next[due]
+		for i := range next {
+			if !next[i].After(at) {
+				s.runSchedule(ctx, i, next[i])
+			}
+		}
+		now := s.now().In(s.location)
+		for i, cron := range s.crons {
+			if !next[i].After(now) {
+				next[i] = cron.Next(now)
+			}
+		}
+	}
+}
+
+// runSchedule runs schedule i due at scheduledAt and adds the run to the
+// history.
+func (s *Scheduler) runSchedule(ctx context.Context, i int, scheduledAt time.Time) ScheduledRun {
+	schedule := s.config.Schedules[i]
+	run := ScheduledRun{Schedule: schedule.Name, ScheduledAt: scheduledAt, StartedAt: s.now()}
+
+	startDate, endDate, err := ParseDateRange(schedule.Range, scheduledAt.In(s.location), s.calendar)
+	if err == nil {
+		run.StartDate, run.EndDate = startDate.Format(time.DateOnly), endDate.Format(time.DateOnly)
+		run.OutputDir = filepath.Join(s.config.OutputDir, schedule.Name, scheduledAt.In(s.location).Format(watchRunDirLayout))
+		s.logger.Printf("Schedule %s reconciling %s to %s", schedule.Name, run.StartDate, run.EndDate)
+		err = os.MkdirAll(run.OutputDir, 0o755)
+	}
+	if err == nil {
+		err = s.run(ctx, schedule, run.OutputDir, startDate, endDate)
+	}
+
+	run.FinishedAt = s.now()
+	run.Status = JobSucceeded
+	if err != nil {
+		run.Status, run.Error = JobFailed, err.Error()
+		s.logger.Printf("Schedule %s failed: %v", schedule.Name, err)
+	} else {
+		s.logger.Printf("Schedule %s written to %s", schedule.Name, run.OutputDir)
+	}
+
+	err = s.appendHistory(run)
+	if err != nil {
+		s.logger.Printf("Schedule %s history: %v", schedule.Name, err)
+	}
+	return run
+}
+
+// runRecon runs the recon of schedule with its options and the calendar of
+// the scheduler, writing the workbook and the JSON report into outputDir.
+func (s *Scheduler) runRecon(ctx context.Context, schedule Schedule, outputDir string, startDate, endDate time.Time) error {
+	options := schedule.Options
+	options.RunArguments = scheduleRunArguments(schedule)
+	executor := withDirNotifiers(newDirExecutor(outputDir, nil, options).WithCalendar(s.calendar), outputDir, s.config.Notify, s.logger)
+	return executor.Execute(ctx, schedule.TransactionPath, schedule.BankStatementPaths, startDate, endDate)
+}
+
+// scheduleRunArguments records a scheduled run in Run Info by the name and
+// the range of its schedule.
+func scheduleRunArguments(schedule Schedule) []string {
+	return []string{"schedule=" + schedule.Name, "range=" + schedule.Range}
+}
+
+func (s *Scheduler) appendHistory(run ScheduledRun) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("encode run error: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(s.config.OutputDir, scheduleHistoryName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open history error: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("write history error: %w", err)
+	}
+	return nil
+}
+
+// ReadScheduleHistory returns the runs kept in the history of outputDir,
+// oldest first.
+func ReadScheduleHistory(outputDir string) ([]ScheduledRun, error) {
+	f, err := os.Open(filepath.Join(outputDir, scheduleHistoryName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history error: %w", err)
+	}
+	defer f.Close()
+
+	var runs []ScheduledRun
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var run ScheduledRun
+		err := json.Unmarshal(scanner.Bytes(), &run)
+		if err != nil {
+			return nil, fmt.Errorf("decode history error: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history error: %w", err)
+	}
+	return runs, nil
+}
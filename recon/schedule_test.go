@@ -0,0 +1,219 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLoadScheduleConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	g.Expect(os.WriteFile(path, []byte(`timezone: Asia/Jakarta
+output_dir: data/schedule
+schedules:
+  - name: daily
+    cron: "0 6 * * 1-5"
+    range: last 1 business day
+    transaction_path: transaction.csv
+    bank_statement_paths: [bca.csv, bri.csv]
+    options:
+      reporting_currency: IDR
+      load_workers: 2
+      match: {fx_tolerance: 0.01, settlement_days: 1}
+      aging: {buckets: [1, 7], escalate_after_days: 30}
+      duplicates: {keys: [id], policy: keep-first}
+      reversals: {window: 24h}
+notify:
+  on_failure: true
+  unmatched_count_above: 10
+  slack_webhooks: [https://hooks.slack.com/services/T0/B0/x]
+  email:
+    addr: smtp.example.com:587
+    from: recon@example.com
+    to: [finance@example.com]
+`), 0o644)).Should(Succeed())
+
+	config, err := LoadScheduleConfig(context.Background(), CSVReaderFactory{}, path)
+
+	g.Expect(err).Should(BeNil())
+	g.Expect(config).Should(Equal(ScheduleConfig{
+		Timezone:  "Asia/Jakarta",
+		OutputDir: "data/schedule",
+		Schedules: []Schedule{{
+			Name:               "daily",
+			Cron:               "0 6 * * 1-5",
+			Range:              "last 1 business day",
+			TransactionPath:    "transaction.csv",
+			BankStatementPaths: []string{"bca.csv", "bri.csv"},
+			Options: Options{
+				ReportingCurrency: "IDR",
+				LoadWorkers:       2,
+				Match:             MatchConfig{FXTolerance: 0.01, SettlementDays: 1},
+				Aging:             AgingConfig{Buckets: []int{1, 7}, EscalateAfterDays: 30},
+				Duplicates:        DuplicateConfig{Keys: []DuplicateKey{DuplicateKeyID}, Policy: DuplicateKeepFirst},
+				Reversals:         ReversalConfig{Window: 24 * time.Hour},
+			},
+		}},
+		Notify: NotifyConfig{
+			NotifyRules:   NotifyRules{OnFailure: true, UnmatchedCountAbove: 10},
+			SlackWebhooks: []string{"https://hooks.slack.com/services/T0/B0/x"},
+			Email:         SMTPConfig{Addr: "smtp.example.com:587", From: "recon@example.com", To: []string{"finance@example.com"}},
+		},
+	}))
+}
+
+func TestNewScheduler(t *testing.T) {
+	valid := Schedule{Name: "daily", Cron: "0 6 * * *", Range: "yesterday", TransactionPath: "transaction.csv", BankStatementPaths: []string{"bca.csv"}}
+
+	for name, schedules := range map[string][]Schedule{
+		"no schedules":         nil,
+		"duplicate names":      {valid, valid},
+		"invalid cron":         {{Name: "a", Cron: "0 6 * *", Range: "yesterday", TransactionPath: "t.csv", BankStatementPaths: []string{"b.csv"}}},
+		"cron that never runs": {{Name: "a", Cron: "0 0 30 2 *", Range: "yesterday", TransactionPath: "t.csv", BankStatementPaths: []string{"b.csv"}}},
+		"invalid range":        {{Name: "a", Cron: "0 6 * * *", Range: "next week", TransactionPath: "t.csv", BankStatementPaths: []string{"b.csv"}}},
+		"missing inputs":       {{Name: "a", Cron: "0 6 * * *", Range: "yesterday"}},
+		"invalid options":      {{Name: "a", Cron: "0 6 * * *", Range: "yesterday", TransactionPath: "t.csv", BankStatementPaths: []string{"b.csv"}, Options: Options{LoadWorkers: -1}}},
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			_, err := NewScheduler(ScheduleConfig{OutputDir: t.TempDir(), Schedules: schedules}, Calendar{}, log.New(io.Discard, "", 0))
+			g.Expect(err).ShouldNot(BeNil())
+		})
+	}
+}
+
+func TestScheduler_RunSchedule(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	// a Monday
+	scheduledAt := time.Date(2025, 8, 4, 6, 0, 0, 0, jakarta)
+
+	newScheduler := func(g *WithT, dir string) *Scheduler {
+		scheduler, err := NewScheduler(ScheduleConfig{
+			Timezone:  "Asia/Jakarta",
+			OutputDir: dir,
+			Schedules: []Schedule{
+				{Name: "daily", Cron: "0 6 * * 1-5", Range: "last 1 business day", TransactionPath: "transaction.csv", BankStatementPaths: []string{"bca.csv"}},
+				{Name: "monthly", Cron: "0 7 1 * *", Range: "last month", TransactionPath: "transaction.csv", BankStatementPaths: []string{"bca.csv"}},
+			},
+		}, NewCalendar(nil), log.New(io.Discard, "", 0))
+		g.Expect(err).Should(BeNil())
+		scheduler.now = func() time.Time { return scheduledAt.Add(time.Second) }
+		return scheduler
+	}
+
+	t.Run("runs the range relative to the due time and keeps the history", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		scheduler := newScheduler(g, dir)
+		var ranges [][2]time.Time
+		scheduler.run = func(_ context.Context, schedule Schedule, outputDir string, startDate, endDate time.Time) error {
+			g.Expect(outputDir).Should(BeADirectory())
+			ranges = append(ranges, [2]time.Time{startDate, endDate})
+			if schedule.Name == "monthly" {
+				return errors.New("bank file missing")
+			}
+			return nil
+		}
+
+		daily := scheduler.runSchedule(context.Background(), 0, scheduledAt)
+		monthly := scheduler.runSchedule(context.Background(), 1, scheduledAt.Add(time.Hour))
+
+		// the last business day before Monday is Friday
+		friday := time.Date(2025, 8, 1, 0, 0, 0, 0, jakarta)
+		g.Expect(ranges[0]).Should(Equal([2]time.Time{friday, friday}))
+		g.Expect(ranges[1]).Should(Equal([2]time.Time{time.Date(2025, 7, 1, 0, 0, 0, 0, jakarta), time.Date(2025, 7, 31, 0, 0, 0, 0, jakarta)}))
+		g.Expect(daily.OutputDir).Should(Equal(filepath.Join(dir, "daily", "20250804T060000")))
+
+		history, err := ReadScheduleHistory(dir)
+		g.Expect(err).Should(BeNil())
+		g.Expect(history).Should(HaveLen(2))
+		g.Expect(history[0].Schedule).Should(Equal("daily"))
+		g.Expect(history[0].Status).Should(Equal(JobSucceeded))
+		g.Expect(history[0].StartDate).Should(Equal("2025-08-01"))
+		g.Expect(history[0].ScheduledAt.Equal(scheduledAt)).Should(BeTrue())
+		g.Expect(history[1].Status).Should(Equal(JobFailed))
+		g.Expect(history[1].Error).Should(Equal("bank file missing"))
+		g.Expect(history[1].Error).Should(Equal(monthly.Error))
+	})
+
+	t.Run("writes the reports of a run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		scheduler := newScheduler(g, dir)
+		input := t.TempDir()
+		scheduler.config.Schedules[0].TransactionPath = filepath.Join(input, "transaction.csv")
+		scheduler.config.Schedules[0].BankStatementPaths = []string{filepath.Join(input, "bca.csv")}
+		g.Expect(os.WriteFile(scheduler.config.Schedules[0].TransactionPath, []byte("id,amount,type,time\n1,100,debit,2025-08-01T10:00:00+07:00\n"), 0o644)).Should(Succeed())
+		g.Expect(os.WriteFile(scheduler.config.Schedules[0].BankStatementPaths[0], []byte("id,amount,time\na,100,2025-08-01T12:00:00+07:00\n"), 0o644)).Should(Succeed())
+
+		scheduler.config.Schedules[0].Options = Options{Match: MatchConfig{SettlementDays: 1}}
+		scheduler.calendar = NewCalendar([]Holiday{{Date: time.Date(2025, 8, 17, 0, 0, 0, 0, jakarta), Name: "Independence Day"}})
+
+		run := scheduler.runSchedule(context.Background(), 0, scheduledAt)
+
+		g.Expect(run.Status).Should(Equal(JobSucceeded), run.Error)
+		g.Expect(filepath.Join(run.OutputDir, jobWorkbookName)).Should(BeAnExistingFile())
+		data, err := os.ReadFile(filepath.Join(run.OutputDir, jobJSONReportName))
+		g.Expect(err).Should(BeNil())
+		var report JSONReport
+		g.Expect(json.Unmarshal(data, &report)).Should(Succeed())
+		g.Expect(report.Run.Arguments).Should(Equal([]string{"schedule=daily", "range=last 1 business day"}))
+		g.Expect(report.Run.Match.SettlementDays).Should(Equal(1))
+		g.Expect(report.Run.Calendar).Should(Equal(JSONCalendar{BusinessDays: true, Holidays: 1}))
+	})
+
+	t.Run("notifies about runs with the reports attached", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		server, bodies := newHookStandIn(t, http.StatusOK)
+		scheduler := newScheduler(g, t.TempDir())
+		scheduler.config.Notify = NotifyConfig{NotifyRules: NotifyRules{OnCompletion: true, OnFailure: true}, Webhooks: []string{server.URL}}
+		input := t.TempDir()
+		scheduler.config.Schedules[0].TransactionPath = filepath.Join(input, "transaction.csv")
+		scheduler.config.Schedules[0].BankStatementPaths = []string{filepath.Join(input, "bca.csv")}
+		g.Expect(os.WriteFile(scheduler.config.Schedules[0].TransactionPath, []byte("id,amount,type,time\n1,100,debit,2025-08-01T10:00:00+07:00\n"), 0o644)).Should(Succeed())
+		g.Expect(os.WriteFile(scheduler.config.Schedules[0].BankStatementPaths[0], []byte("id,amount,time\na,100,2025-08-01T10:00:00+07:00\n"), 0o644)).Should(Succeed())
+
+		run := scheduler.runSchedule(context.Background(), 0, scheduledAt)
+
+		g.Expect(run.Status).Should(Equal(JobSucceeded), run.Error)
+		var notification Notification
+		g.Expect(json.Unmarshal(<-bodies, &notification)).Should(Succeed())
+		g.Expect(notification.Status).Should(Equal(JobSucceeded))
+		g.Expect(notification.Period.StartDate).Should(Equal("2025-08-01"))
+		g.Expect(notification.Attachments).Should(Equal([]string{filepath.Join(run.OutputDir, jobWorkbookName), filepath.Join(run.OutputDir, jobJSONReportName)}))
+	})
+
+	t.Run("notifies about failed runs without attachments", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		server, bodies := newHookStandIn(t, http.StatusOK)
+		scheduler := newScheduler(g, t.TempDir())
+		scheduler.config.Notify = NotifyConfig{NotifyRules: NotifyRules{OnFailure: true}, Webhooks: []string{server.URL}}
+
+		run := scheduler.runSchedule(context.Background(), 0, scheduledAt)
+
+		g.Expect(run.Status).Should(Equal(JobFailed))
+		var notification Notification
+		g.Expect(json.Unmarshal(<-bodies, &notification)).Should(Succeed())
+		g.Expect(notification.Status).Should(Equal(JobFailed))
+		g.Expect(notification.Attachments).Should(BeEmpty())
+	})
+
+	t.Run("has no history before the first run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		history, err := ReadScheduleHistory(t.TempDir())
+		g.Expect(err).Should(BeNil())
+		g.Expect(history).Should(BeEmpty())
+	})
+}
@@ -0,0 +1,300 @@
+package recon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxUploadBytes bounds the size of the files uploaded to start a job.
+const maxUploadBytes = 256 << 20
+
+// Server runs recons over HTTP as jobs of a JobManager:
+//
+//	POST   /jobs                    upload files and queue a job
+//	GET    /jobs                    list the jobs
+//	GET    /jobs/{id}               poll the job status
+//	POST   /jobs/{id}/cancel        cancel a queued or running job
+//	GET    /jobs/{id}/result.xlsx   download the workbook
+//	GET    /jobs/{id}/result.json   download the JSON report
+//	DELETE /jobs/{id}               remove a finished job and its files
+type Server struct {
+	jobs *JobManager
+	mux  *http.ServeMux
+}
+
+func NewServer(jobs *JobManager) *Server {
+	s := &Server{
+		jobs: jobs,
+		mux:  http.NewServeMux(),
+	}
+	s.mux.HandleFunc("POST /jobs", s.createJob)
+	s.mux.HandleFunc("GET /jobs", s.listJobs)
+	s.mux.HandleFunc("GET /jobs/{id}", s.getJob)
+	s.mux.HandleFunc("POST /jobs/{id}/cancel", s.cancelJob)
+	s.mux.HandleFunc("GET /jobs/{id}/result.xlsx", s.download(jobWorkbookName, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"))
+	s.mux.HandleFunc("GET /jobs/{id}/result.json", s.download(jobJSONReportName, "application/json"))
+	s.mux.HandleFunc("DELETE /jobs/{id}", s.deleteJob)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// parseJobSpec reads the run from form fields named like the command line
+// flags: start-date and end-date are required, timezone, business-days,
+// fx-tolerance, reporting-currency, settlement-days, duplicate-keys,
+// duplicate-policy, reversal-window and load-workers are optional.
+func parseJobSpec(form *multipart.Form) (JobSpec, error) {
+	value := func(name string) string {
+		if values := form.Value[name]; len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+
+	spec := JobSpec{
+		StartDate: value("start-date"),
+		EndDate:   value("end-date"),
+		Timezone:  value("timezone"),
+	}
+	_, _, err := spec.Period()
+	if err != nil {
+		return spec, err
+	}
+
+	float := func(name string, target *float64) {
+		if v := value(name); v != "" && err == nil {
+			*target, err = strconv.ParseFloat(v, 64)
+			if err != nil {
+				err = fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	options := &spec.Options
+	float("fx-tolerance", &options.Match.FXTolerance)
+	if v := value("settlement-days"); v != "" && err == nil {
+		options.Match.SettlementDays, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("invalid settlement-days: %w", err)
+		}
+	}
+	if v := value("load-workers"); v != "" && err == nil {
+		options.LoadWorkers, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("invalid load-workers: %w", err)
+		}
+	}
+	if v := value("business-days"); v != "" && err == nil {
+		spec.BusinessDays, err = strconv.ParseBool(v)
+		if err != nil {
+			err = fmt.Errorf("invalid business-days: %w", err)
+		}
+	}
+	if v := value("reversal-window"); v != "" && err == nil {
+		options.Reversals.Window, err = time.ParseDuration(v)
+		if err != nil {
+			err = fmt.Errorf("invalid reversal-window: %w", err)
+		}
+	}
+	if err != nil {
+		return spec, err
+	}
+	options.ReportingCurrency = strings.ToUpper(value("reporting-currency"))
+	for _, key := range strings.Split(value("duplicate-keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			options.Duplicates.Keys = append(options.Duplicates.Keys, DuplicateKey(key))
+		}
+	}
+	options.Duplicates.Policy = DuplicatePolicy(value("duplicate-policy"))
+
+	err = options.Validate()
+	if err != nil {
+		return spec, fmt.Errorf("invalid options: %w", err)
+	}
+	return spec, nil
+}
+
+func (s *Server) createJob(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	err := r.ParseMultipartForm(32 << 20)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid multipart form: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	spec, err := parseJobSpec(r.MultipartForm)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	transactionFiles := r.MultipartForm.File["transactions"]
+	if len(transactionFiles) != 1 {
+		writeError(w, http.StatusBadRequest, errors.New("expected one transactions file"))
+		return
+	}
+	statementFiles := r.MultipartForm.File["bank_statements"]
+	if len(statementFiles) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("expected at least one bank_statements file"))
+		return
+	}
+	fxRatesFiles := r.MultipartForm.File["fx_rates"]
+	if len(fxRatesFiles) > 1 {
+		writeError(w, http.StatusBadRequest, errors.New("expected at most one fx_rates file"))
+		return
+	}
+
+	// files keep their names, which tell the bank of statement files
+	uploads := map[string]*multipart.FileHeader{}
+	add := func(header *multipart.FileHeader) (string, error) {
+		name := filepath.Base(filepath.Clean("/" + header.Filename))
+		if name == "/" || name == "." {
+			return "", fmt.Errorf("invalid file name %q", header.Filename)
+		}
+		if _, ok := uploads[name]; ok {
+			return "", fmt.Errorf("file %q uploaded twice", name)
+		}
+		uploads[name] = header
+		return name, nil
+	}
+	spec.TransactionFile, err = add(transactionFiles[0])
+	for _, header := range statementFiles {
+		if err == nil {
+			var name string
+			name, err = add(header)
+			spec.BankStatementFiles = append(spec.BankStatementFiles, name)
+		}
+	}
+	for _, header := range fxRatesFiles {
+		if err == nil {
+			spec.FXRatesFile, err = add(header)
+		}
+	}
+	for _, header := range r.MultipartForm.File["holidays"] {
+		if err == nil {
+			var name string
+			name, err = add(header)
+			spec.HolidayFiles = append(spec.HolidayFiles, name)
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := s.jobs.Submit(spec, func(dir string) error {
+		for name, header := range uploads {
+			err := saveUpload(filepath.Join(dir, name), header)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrQueueFull) {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// saveUpload copies an uploaded file to path.
+func saveUpload(path string, header *multipart.FileHeader) error {
+	src, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("open upload error: %w", err)
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create file error: %w", err)
+	}
+	defer dst.Close()
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		return fmt.Errorf("save upload error: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.jobs.List())
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) cancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) download(name, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		path, err := s.jobs.Output(id, name)
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+"-"+name))
+		http.ServeFile(w, r, path)
+	}
+}
+
+func (s *Server) deleteJob(w http.ResponseWriter, r *http.Request) {
+	err := s.jobs.Delete(r.PathValue("id"))
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeJobError answers 404 for unknown jobs and 409 for jobs in the wrong
+// state.
+func writeJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrJobFinished), errors.Is(err, ErrJobActive), errors.Is(err, ErrJobNotSucceeded):
+		writeError(w, http.StatusConflict, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
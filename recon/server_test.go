@@ -0,0 +1,208 @@
+package recon
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// postJob starts a job with the form fields and files, given as field name to
+// file name to content.
+func postJob(g *WithT, url string, fields map[string]string, files map[string]map[string]string) *http.Response {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		g.Expect(writer.WriteField(name, value)).Should(Succeed())
+	}
+	for field, named := range files {
+		for name, content := range named {
+			part, err := writer.CreateFormFile(field, name)
+			g.Expect(err).Should(BeNil())
+			_, err = part.Write([]byte(content))
+			g.Expect(err).Should(BeNil())
+		}
+	}
+	g.Expect(writer.Close()).Should(Succeed())
+
+	resp, err := http.Post(url+"/jobs", writer.FormDataContentType(), &body)
+	g.Expect(err).Should(BeNil())
+	return resp
+}
+
+func decodeJob(g *WithT, resp *http.Response) Job {
+	defer resp.Body.Close()
+	var job Job
+	g.Expect(json.NewDecoder(resp.Body).Decode(&job)).Should(Succeed())
+	return job
+}
+
+// awaitJob polls the job until it finished.
+func awaitJob(g *WithT, url, id string) Job {
+	var job Job
+	g.Eventually(func() bool {
+		resp, err := http.Get(url + "/jobs/" + id)
+		g.Expect(err).Should(BeNil())
+		job = decodeJob(g, resp)
+		return job.Status.Finished()
+	}).Should(BeTrue())
+	return job
+}
+
+func newTestServer(g *WithT, dir string) *httptest.Server {
+	jobs, err := NewJobManager(dir, 2, log.New(io.Discard, "", 0))
+	g.Expect(err).Should(BeNil())
+	return httptest.NewServer(NewServer(jobs))
+}
+
+func TestServer(t *testing.T) {
+	dates := map[string]string{"start-date": "2025-08-01", "end-date": "2025-08-01"}
+	files := map[string]map[string]string{
+		"transactions": {"transaction.csv": "id,amount,type,time\n1,100,debit,2025-08-01T10:00:00Z\n2,50,credit,2025-08-01T11:00:00Z\n"},
+		"bank_statements": {
+			"bca.csv": "id,amount,time\na,100,2025-08-01T12:00:00Z\n",
+			"bri.csv": "id,amount,time\nb,70,2025-08-01T12:00:00Z\n",
+		},
+	}
+
+	t.Run("runs a job and serves its reports", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		ts := newTestServer(g, dir)
+		defer ts.Close()
+
+		resp := postJob(g, ts.URL, dates, files)
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusAccepted))
+		job := decodeJob(g, resp)
+		g.Expect(job.ID).ShouldNot(BeEmpty())
+		g.Expect(job.StartDate).Should(Equal("2025-08-01"))
+
+		job = awaitJob(g, ts.URL, job.ID)
+		g.Expect(job.Status).Should(Equal(JobSucceeded))
+		g.Expect(job.FinishedAt).ShouldNot(BeNil())
+
+		resp, err := http.Get(ts.URL + "/jobs/" + job.ID + "/result.json")
+		g.Expect(err).Should(BeNil())
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+		var report JSONReport
+		g.Expect(json.NewDecoder(resp.Body).Decode(&report)).Should(Succeed())
+		resp.Body.Close()
+		g.Expect(report.Summary.Transactions.Count).Should(Equal(2))
+		g.Expect(report.Matches).Should(HaveLen(1))
+		g.Expect(report.UnmatchedBankStatements).Should(HaveLen(1))
+		g.Expect(report.UnmatchedBankStatements[0].Bank).Should(Equal("bri"))
+
+		resp, err = http.Get(ts.URL + "/jobs/" + job.ID + "/result.xlsx")
+		g.Expect(err).Should(BeNil())
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+		g.Expect(resp.Header.Get("Content-Disposition")).Should(ContainSubstring(job.ID + "-recon.xlsx"))
+		resp.Body.Close()
+
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/jobs/"+job.ID, nil)
+		resp, err = http.DefaultClient.Do(req)
+		g.Expect(err).Should(BeNil())
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusNoContent))
+		g.Expect(filepath.Join(dir, job.ID)).ShouldNot(BeADirectory())
+	})
+
+	t.Run("runs a job with FX rates and holidays", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ts := newTestServer(g, t.TempDir())
+		defer ts.Close()
+
+		fields := map[string]string{"start-date": "2025-08-01", "end-date": "2025-08-04", "reporting-currency": "IDR", "settlement-days": "1"}
+		withConfig := map[string]map[string]string{
+			// settled on Monday, the next business day after Friday
+			"transactions":    {"transaction.csv": "id,amount,type,time,currency\n1,10,debit,2025-08-01T10:00:00Z,USD\n"},
+			"bank_statements": {"bca.csv": "id,amount,time,currency\na,10,2025-08-04T09:00:00Z,USD\n"},
+			"fx_rates":        {"rates.csv": "date,pair,rate\n2025-08-01,USD/IDR,16000\n"},
+			"holidays":        {"holidays.csv": "date,name\n2025-08-17,Independence Day\n"},
+		}
+		job := decodeJob(g, postJob(g, ts.URL, fields, withConfig))
+
+		job = awaitJob(g, ts.URL, job.ID)
+		g.Expect(job.Status).Should(Equal(JobSucceeded), job.Error)
+		resp, err := http.Get(ts.URL + "/jobs/" + job.ID + "/result.json")
+		g.Expect(err).Should(BeNil())
+		var report JSONReport
+		g.Expect(json.NewDecoder(resp.Body).Decode(&report)).Should(Succeed())
+		resp.Body.Close()
+		g.Expect(report.Matches).Should(HaveLen(1))
+		g.Expect(report.Summary.Transactions.Amount).Should(Equal(160000.0))
+		g.Expect(report.Run.Calendar).Should(Equal(JSONCalendar{BusinessDays: true, Holidays: 1}))
+		var kinds []string
+		for _, input := range report.Inputs {
+			kinds = append(kinds, input.Kind)
+		}
+		g.Expect(kinds).Should(ContainElements(ConfigInputFXRates, ConfigInputHolidays))
+	})
+
+	t.Run("reports a failed run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ts := newTestServer(g, t.TempDir())
+		defer ts.Close()
+
+		fields := map[string]string{"start-date": "2025-08-01", "end-date": "2025-08-01", "duplicate-keys": "id", "duplicate-policy": "reject"}
+		failing := map[string]map[string]string{
+			"transactions":    {"transaction.csv": "id,amount,type,time\n1,100,debit,2025-08-01T10:00:00Z\n1,100,debit,2025-08-01T10:00:00Z\n"},
+			"bank_statements": files["bank_statements"],
+		}
+		job := decodeJob(g, postJob(g, ts.URL, fields, failing))
+
+		job = awaitJob(g, ts.URL, job.ID)
+		g.Expect(job.Status).Should(Equal(JobFailed))
+		g.Expect(job.Error).Should(ContainSubstring("duplicate"))
+
+		resp, err := http.Get(ts.URL + "/jobs/" + job.ID + "/result.json")
+		g.Expect(err).Should(BeNil())
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusConflict))
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		ts := newTestServer(g, dir)
+		defer ts.Close()
+
+		resp := postJob(g, ts.URL, map[string]string{"start-date": "2025-08-01"}, files)
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		resp = postJob(g, ts.URL, map[string]string{"start-date": "2025-08-01", "end-date": "2025-08-01", "fx-tolerance": "-1"}, files)
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		resp = postJob(g, ts.URL, dates, map[string]map[string]string{"transactions": files["transactions"]})
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		resp = postJob(g, ts.URL, dates, map[string]map[string]string{
+			"transactions":    files["transactions"],
+			"bank_statements": {"transaction.csv": "id,amount,time\n"},
+		})
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		resp = postJob(g, ts.URL, dates, map[string]map[string]string{
+			"transactions":    files["transactions"],
+			"bank_statements": files["bank_statements"],
+			"fx_rates":        {"rates.csv": "date,pair,rate\n", "more-rates.csv": "date,pair,rate\n"},
+		})
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		resp = postJob(g, ts.URL, map[string]string{"start-date": "2025-08-01", "end-date": "2025-08-01", "business-days": "maybe"}, files)
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		entries, err := os.ReadDir(dir)
+		g.Expect(err).Should(BeNil())
+		g.Expect(entries).Should(BeEmpty())
+
+		resp, err = http.Get(ts.URL + "/jobs/unknown")
+		g.Expect(err).Should(BeNil())
+		g.Expect(resp.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+}
@@ -0,0 +1,345 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status of an item stored by SQLResultStorage.
+const (
+	sqlItemMatched       = "matched"
+	sqlItemManualMatched = "manual_match"
+	sqlItemUnmatched     = "unmatched"
+	sqlItemExcluded      = "excluded"
+	sqlItemReversed      = "reversed"
+	sqlItemDuplicate     = "duplicate"
+)
+
+// sqlResultMigrations create the tables of SQLResultStorage. Each migration
+// is a list of statements, applied in order once per database; a migration is
+// never changed once released, later changes go in a new one. Statements must
+// be safe to run again, see Migrate.
+var sqlResultMigrations = [][]string{
+	{
+		`CREATE TABLE IF NOT EXISTS recon_runs (
+			id VARCHAR(32) NOT NULL PRIMARY KEY,
+			run_at TIMESTAMP NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			timezone VARCHAR(64) NOT NULL,
+			reporting_currency VARCHAR(3) NOT NULL,
+			arguments TEXT NOT NULL,
+			CONSTRAINT recon_runs_period UNIQUE (start_date, end_date, id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS recon_summaries (
+			run_id VARCHAR(32) NOT NULL PRIMARY KEY REFERENCES recon_runs (id),
+			total_transactions INTEGER NOT NULL,
+			total_amount_transactions DOUBLE PRECISION NOT NULL,
+			matched_transactions INTEGER NOT NULL,
+			matched_amount_transactions DOUBLE PRECISION NOT NULL,
+			unmatched_transactions INTEGER NOT NULL,
+			unmatched_amount_transactions DOUBLE PRECISION NOT NULL,
+			total_bank_statements INTEGER NOT NULL,
+			total_amount_bank_statements DOUBLE PRECISION NOT NULL,
+			matched_bank_statements INTEGER NOT NULL,
+			matched_amount_bank_statements DOUBLE PRECISION NOT NULL,
+			unmatched_bank_statements INTEGER NOT NULL,
+			unmatched_amount_bank_statements DOUBLE PRECISION NOT NULL,
+			matched_amount_difference DOUBLE PRECISION NOT NULL,
+			fx_difference DOUBLE PRECISION NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS recon_account_summaries (
+			run_id VARCHAR(32) NOT NULL REFERENCES recon_runs (id),
+			bank VARCHAR(64) NOT NULL,
+			account VARCHAR(64) NOT NULL,
+			total_bank_statements INTEGER NOT NULL,
+			total_amount_bank_statements DOUBLE PRECISION NOT NULL,
+			matched_bank_statements INTEGER NOT NULL,
+			matched_amount_bank_statements DOUBLE PRECISION NOT NULL,
+			unmatched_bank_statements INTEGER NOT NULL,
+			unmatched_amount_bank_statements DOUBLE PRECISION NOT NULL,
+			PRIMARY KEY (run_id, bank, account)
+		)`,
+		`CREATE TABLE IF NOT EXISTS recon_transactions (
+			run_id VARCHAR(32) NOT NULL REFERENCES recon_runs (id),
+			seq INTEGER NOT NULL,
+			id VARCHAR(255) NOT NULL,
+			amount DOUBLE PRECISION NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			type VARCHAR(16) NOT NULL,
+			booked_at TIMESTAMP NOT NULL,
+			status VARCHAR(16) NOT NULL,
+			PRIMARY KEY (run_id, seq)
+		)`,
+		`CREATE TABLE IF NOT EXISTS recon_bank_statements (
+			run_id VARCHAR(32) NOT NULL REFERENCES recon_runs (id),
+			seq INTEGER NOT NULL,
+			bank VARCHAR(64) NOT NULL,
+			account VARCHAR(64) NOT NULL,
+			id VARCHAR(255) NOT NULL,
+			amount DOUBLE PRECISION NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			booked_at TIMESTAMP NOT NULL,
+			reference VARCHAR(255) NOT NULL,
+			status VARCHAR(16) NOT NULL,
+			PRIMARY KEY (run_id, seq)
+		)`,
+		`CREATE TABLE IF NOT EXISTS recon_matches (
+			run_id VARCHAR(32) NOT NULL REFERENCES recon_runs (id),
+			transaction_seq INTEGER NOT NULL,
+			bank_statement_seq INTEGER NOT NULL,
+			fx_difference DOUBLE PRECISION NOT NULL,
+			PRIMARY KEY (run_id, transaction_seq)
+		)`,
+	},
+}
+
+// SQLResultStorage stores every run in SQL tables through database/sql: the
+// run, its summary per side and per account, and its items with the matches
+// between them. Besides the matched, manually matched and unmatched items,
+// these are the excluded items, the items netted out by a reversal and the
+// duplicates left out of the run.
+type SQLResultStorage struct {
+	db *sql.DB
+	// numbered is set for drivers taking $1, $2... as parameters instead of ?.
+	numbered bool
+}
+
+// NewSQLResultStorage stores runs in db, opened with driver.
+func NewSQLResultStorage(db *sql.DB, driver string) SQLResultStorage {
+	return SQLResultStorage{
+		db:       db,
+		numbered: driver == "postgres" || driver == "pgx",
+	}
+}
+
+// Migrate creates or updates the tables, applying the migrations the
+// database does not have yet. Each migration runs in a transaction, but MySQL
+// commits DDL statements implicitly, so a failed migration may leave some of
+// its tables behind without being recorded: statements are written to be run
+// again, e.g. CREATE TABLE IF NOT EXISTS with indexes declared in the table,
+// and the next Migrate picks up where the failed one stopped.
+func (s SQLResultStorage) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS recon_schema_migrations (
+		version INTEGER NOT NULL PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("create migrations table error: %w", err)
+	}
+
+	var version sql.NullInt64
+	err = s.db.QueryRowContext(ctx, "SELECT MAX(version) FROM recon_schema_migrations").Scan(&version)
+	if err != nil {
+		return fmt.Errorf("get schema version error: %w", err)
+	}
+
+	for i := int(version.Int64); i < len(sqlResultMigrations); i++ {
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			for _, statement := range sqlResultMigrations[i] {
+				_, err := tx.ExecContext(ctx, statement)
+				if err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, s.bind("INSERT INTO recon_schema_migrations (version, applied_at) VALUES (?, ?)"), i+1, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d error: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// StoreReport stores result as a new run, all of it or nothing. The run is
+// identified by Result.RunID, like in the ledger.
+func (s SQLResultStorage) StoreReport(ctx context.Context, result Result) error {
+	runID := result.RunID()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		exec := func(query string, args ...any) error {
+			_, err := tx.ExecContext(ctx, s.bind(query), args...)
+			return err
+		}
+
+		err := exec("INSERT INTO recon_runs (id, run_at, start_date, end_date, timezone, reporting_currency, arguments) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			runID,
+			result.RunAt.UTC(),
+			result.StartDate.Format(time.DateOnly),
+			result.EndDate.Format(time.DateOnly),
+			result.StartDate.Location().String(),
+			result.Summary.ReportingCurrency,
+			strings.Join(result.Options.RunArguments, " "),
+		)
+		if err != nil {
+			return fmt.Errorf("insert run error: %w", err)
+		}
+
+		total := result.Summary
+		err = exec("INSERT INTO recon_summaries (run_id, total_transactions, total_amount_transactions, matched_transactions, matched_amount_transactions, unmatched_transactions, unmatched_amount_transactions, total_bank_statements, total_amount_bank_statements, matched_bank_statements, matched_amount_bank_statements, unmatched_bank_statements, unmatched_amount_bank_statements, matched_amount_difference, fx_difference) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			runID,
+			total.TotalTransactions, total.TotalAmountTransactions,
+			total.MatchedTransactions, total.MatchedAmountTransactions,
+			total.UnmatchedTransactions, total.UnmatchedAmountTransactions,
+			total.TotalBankStatements, total.TotalAmountBankStatements,
+			total.MatchedBankStatements, total.MatchedAmountBankStatements,
+			total.UnmatchedBankStatements, total.UnmatchedAmountBankStatements,
+			total.MatchedAmountDifference, total.FXDifference,
+		)
+		if err != nil {
+			return fmt.Errorf("insert summary error: %w", err)
+		}
+
+		for _, account := range total.Accounts {
+			err = exec("INSERT INTO recon_account_summaries (run_id, bank, account, total_bank_statements, total_amount_bank_statements, matched_bank_statements, matched_amount_bank_statements, unmatched_bank_statements, unmatched_amount_bank_statements) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				runID, account.Bank, account.Account,
+				account.TotalBankStatements, account.TotalAmountBankStatements,
+				account.MatchedBankStatements, account.MatchedAmountBankStatements,
+				account.UnmatchedBankStatements, account.UnmatchedAmountBankStatements,
+			)
+			if err != nil {
+				return fmt.Errorf("insert account summary error: %w", err)
+			}
+		}
+
+		var transactionSeq, statementSeq int
+		insertTransaction := func(t Transaction, status string) (int, error) {
+			transactionSeq++
+			err := exec("INSERT INTO recon_transactions (run_id, seq, id, amount, currency, type, booked_at, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				runID, transactionSeq, t.ID, t.Amount, t.Currency, string(t.Type), t.Time.UTC(), status)
+			if err != nil {
+				return 0, fmt.Errorf("insert transaction %s error: %w", t.ID, err)
+			}
+			return transactionSeq, nil
+		}
+		insertStatement := func(b BankStatement, status string) (int, error) {
+			statementSeq++
+			err := exec("INSERT INTO recon_bank_statements (run_id, seq, bank, account, id, amount, currency, booked_at, reference, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				runID, statementSeq, b.Bank, b.Account, b.ID, b.Amount, b.Currency, b.Time.UTC(), b.Reference, status)
+			if err != nil {
+				return 0, fmt.Errorf("insert bank statement %s/%s error: %w", b.BankAccount(), b.ID, err)
+			}
+			return statementSeq, nil
+		}
+
+		for _, match := range result.Matches {
+			transaction, err := insertTransaction(match.Transaction, sqlItemMatched)
+			if err != nil {
+				return err
+			}
+			statement, err := insertStatement(match.BankStatement, sqlItemMatched)
+			if err != nil {
+				return err
+			}
+			err = exec("INSERT INTO recon_matches (run_id, transaction_seq, bank_statement_seq, fx_difference) VALUES (?, ?, ?, ?)",
+				runID, transaction, statement, match.FXDifference)
+			if err != nil {
+				return fmt.Errorf("insert match error: %w", err)
+			}
+		}
+		for _, manual := range result.ManualMatches {
+			for _, t := range manual.Transactions {
+				if _, err := insertTransaction(t, sqlItemManualMatched); err != nil {
+					return err
+				}
+			}
+			for _, b := range manual.BankStatements {
+				if _, err := insertStatement(b, sqlItemManualMatched); err != nil {
+					return err
+				}
+			}
+		}
+		for _, t := range result.UnmatchedTransactions {
+			if _, err := insertTransaction(t, sqlItemUnmatched); err != nil {
+				return err
+			}
+		}
+		for _, group := range result.UnmatchedBankStatements {
+			for _, b := range group.Statements {
+				if _, err := insertStatement(b, sqlItemUnmatched); err != nil {
+					return err
+				}
+			}
+		}
+		for _, exclusion := range result.Exclusions {
+			for _, t := range exclusion.Transactions {
+				if _, err := insertTransaction(t, sqlItemExcluded); err != nil {
+					return err
+				}
+			}
+			for _, b := range exclusion.BankStatements {
+				if _, err := insertStatement(b, sqlItemExcluded); err != nil {
+					return err
+				}
+			}
+		}
+		for _, reversal := range result.TransactionReversals {
+			for _, t := range []Transaction{reversal.Original, reversal.Reversal} {
+				if _, err := insertTransaction(t, sqlItemReversed); err != nil {
+					return err
+				}
+			}
+		}
+		for _, reversal := range result.BankStatementReversals {
+			for _, b := range []BankStatement{reversal.Original, reversal.Reversal} {
+				if _, err := insertStatement(b, sqlItemReversed); err != nil {
+					return err
+				}
+			}
+		}
+		// flagged duplicates took part in the run and are stored as such
+		if result.Options.Duplicates.policy() == DuplicateKeepFirst {
+			for _, dup := range result.DuplicateTransactions {
+				if _, err := insertTransaction(dup.Transaction, sqlItemDuplicate); err != nil {
+					return err
+				}
+			}
+			for _, dup := range result.DuplicateBankStatements {
+				if _, err := insertStatement(dup.BankStatement, sqlItemDuplicate); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("store run error: %w", err)
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committed when fn succeeds.
+func (s SQLResultStorage) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// bind writes the ? parameters of query the way the driver takes them.
+func (s SQLResultStorage) bind(query string) string {
+	if !s.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
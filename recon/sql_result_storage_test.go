@@ -0,0 +1,211 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func countRows(g *WithT, db *sql.DB, query string, args ...any) int {
+	var n int
+	g.Expect(db.QueryRow(query, args...).Scan(&n)).Should(Succeed())
+	return n
+}
+
+func TestSQLResultStorage_Migrate(t *testing.T) {
+	t.Run("should apply every migration once", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+		storage := NewSQLResultStorage(db, "sqlite3")
+
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_schema_migrations")).Should(Equal(len(sqlResultMigrations)))
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_runs")).Should(Equal(0))
+	})
+
+	t.Run("should rerun a migration whose tables were committed without it", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+		storage := NewSQLResultStorage(db, "sqlite3")
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+		// as MySQL leaves it when a migration fails after its DDL
+		_, err := db.Exec("DELETE FROM recon_schema_migrations")
+		g.Expect(err).Should(BeNil())
+
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_schema_migrations")).Should(Equal(len(sqlResultMigrations)))
+	})
+
+	t.Run("should return error when a migration fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+		migrations := sqlResultMigrations
+		defer func() { sqlResultMigrations = migrations }()
+		sqlResultMigrations = append(migrations[:len(migrations):len(migrations)], []string{"CREATE TABLE recon_broken ("})
+
+		err := NewSQLResultStorage(db, "sqlite3").Migrate(context.Background())
+		g.Expect(err).Should(MatchError(ContainSubstring(fmt.Sprintf("migration %d error", len(sqlResultMigrations)))))
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_schema_migrations")).Should(Equal(len(migrations)))
+	})
+}
+
+func TestSQLResultStorage_StoreReport(t *testing.T) {
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{ID: "1", Amount: 100, Type: Debit, Time: day.Add(time.Hour)},
+		{ID: "2", Amount: 50, Type: Debit, Time: day.Add(2 * time.Hour)},
+		{ID: "3", Amount: 20, Type: Credit, Time: day.Add(3 * time.Hour)},
+	}
+	statements := []BankStatement{
+		{Bank: "BCA", ID: "a", Amount: 100, Time: day.Add(4 * time.Hour)},
+		{Bank: "BCA", ID: "b", Amount: 45, Time: day.Add(5 * time.Hour)},
+		{Bank: "BRI", Account: "01", ID: "c", Amount: 70, Time: day.Add(6 * time.Hour), Reference: "ref"},
+	}
+	result := Result{
+		RunAt:     day.Add(30 * time.Hour),
+		StartDate: day,
+		EndDate:   day,
+		Summary: Summary{
+			TotalTransactions:       3,
+			TotalAmountTransactions: 170,
+			Accounts: []AccountSummary{
+				{Bank: "BCA", TotalBankStatements: 2, TotalAmountBankStatements: 145},
+				{Bank: "BRI", Account: "01", TotalBankStatements: 1, TotalAmountBankStatements: 70},
+			},
+		},
+		Matches:                 []Match{{Transaction: transactions[0], BankStatement: statements[0]}},
+		ManualMatches:           []AppliedOverride{{Transactions: []Transaction{transactions[1]}, BankStatements: []BankStatement{statements[1]}}},
+		UnmatchedTransactions:   []Transaction{transactions[2]},
+		UnmatchedBankStatements: []BankStatementDiscrepancy{{Bank: "BRI", Account: "01", Statements: []BankStatement{statements[2]}}},
+	}
+
+	t.Run("should store the run with its summary and items", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+		storage := NewSQLResultStorage(db, "sqlite3")
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+
+		rerun := result
+		rerun.RunAt = result.RunAt.Add(time.Minute)
+		g.Expect(storage.StoreReport(context.Background(), result)).Should(Succeed())
+		g.Expect(storage.StoreReport(context.Background(), rerun)).Should(Succeed())
+
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_runs WHERE start_date = '2025-08-01'")).Should(Equal(2))
+		// runs are identified like in the ledger
+		runID := result.RunID()
+		var total float64
+		g.Expect(db.QueryRow("SELECT s.total_amount_transactions FROM recon_runs r JOIN recon_summaries s ON s.run_id = r.id WHERE r.id = ?", runID).Scan(&total)).Should(Succeed())
+		g.Expect(total).Should(Equal(170.0))
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_account_summaries WHERE run_id = ?", runID)).Should(Equal(2))
+
+		statuses := map[string]string{}
+		rows, err := db.Query("SELECT id, status FROM recon_transactions WHERE run_id = ? UNION ALL SELECT id, status FROM recon_bank_statements WHERE run_id = ?", runID, runID)
+		g.Expect(err).Should(BeNil())
+		for rows.Next() {
+			var id, status string
+			g.Expect(rows.Scan(&id, &status)).Should(Succeed())
+			statuses[id] = status
+		}
+		rows.Close()
+		g.Expect(statuses).Should(Equal(map[string]string{
+			"1": "matched", "a": "matched",
+			"2": "manual_match", "b": "manual_match",
+			"3": "unmatched", "c": "unmatched",
+		}))
+
+		var transactionID, statementID string
+		g.Expect(db.QueryRow(`SELECT t.id, b.id FROM recon_matches m
+			JOIN recon_transactions t ON t.run_id = m.run_id AND t.seq = m.transaction_seq
+			JOIN recon_bank_statements b ON b.run_id = m.run_id AND b.seq = m.bank_statement_seq
+			WHERE m.run_id = ?`, runID).Scan(&transactionID, &statementID)).Should(Succeed())
+		g.Expect([]string{transactionID, statementID}).Should(Equal([]string{"1", "a"}))
+	})
+
+	t.Run("should store excluded, reversed and left out duplicate items", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+		storage := NewSQLResultStorage(db, "sqlite3")
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+
+		reversed := Transaction{ID: "4", Amount: 30, Type: Debit, Time: day.Add(7 * time.Hour)}
+		reversal := Transaction{ID: "5", Amount: 30, Type: Credit, Time: day.Add(8 * time.Hour)}
+		result := Result{
+			RunAt:     day.Add(30 * time.Hour),
+			StartDate: day,
+			EndDate:   day,
+			Options:   Options{Duplicates: DuplicateConfig{Policy: DuplicateKeepFirst}},
+			Exclusions: []AppliedOverride{{
+				Override:       Override{Action: OverrideExclude},
+				Transactions:   []Transaction{transactions[2]},
+				BankStatements: []BankStatement{statements[2]},
+			}},
+			TransactionReversals: []TransactionReversal{{Original: reversed, Reversal: reversal}},
+			BankStatementReversals: []BankStatementReversal{{
+				Original: statements[1],
+				Reversal: BankStatement{Bank: "BCA", ID: "d", Amount: -45, Time: day.Add(9 * time.Hour)},
+			}},
+			DuplicateTransactions:   []DuplicateTransaction{{Key: DuplicateKeyID, Transaction: transactions[0], Original: transactions[0]}},
+			DuplicateBankStatements: []DuplicateBankStatement{{Key: DuplicateKeyID, BankStatement: statements[0], Original: statements[0]}},
+		}
+
+		g.Expect(storage.StoreReport(context.Background(), result)).Should(Succeed())
+
+		statuses := map[string]string{}
+		rows, err := db.Query("SELECT id, status FROM recon_transactions UNION ALL SELECT id, status FROM recon_bank_statements")
+		g.Expect(err).Should(BeNil())
+		for rows.Next() {
+			var id, status string
+			g.Expect(rows.Scan(&id, &status)).Should(Succeed())
+			statuses[id] = status
+		}
+		rows.Close()
+		g.Expect(statuses).Should(Equal(map[string]string{
+			"1": "duplicate", "a": "duplicate",
+			"3": "excluded", "c": "excluded",
+			"4": "reversed", "5": "reversed", "b": "reversed", "d": "reversed",
+		}))
+	})
+
+	t.Run("should store nothing of a run that fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+		storage := NewSQLResultStorage(db, "sqlite3")
+		g.Expect(storage.Migrate(context.Background())).Should(Succeed())
+		g.Expect(storage.StoreReport(context.Background(), result)).Should(Succeed())
+
+		err := storage.StoreReport(context.Background(), result)
+
+		g.Expect(err).Should(MatchError(ContainSubstring("insert run error")))
+		g.Expect(countRows(g, db, "SELECT COUNT(*) FROM recon_transactions")).Should(Equal(3))
+	})
+
+	t.Run("should return error when the tables are missing", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+
+		err := NewSQLResultStorage(db, "sqlite3").StoreReport(context.Background(), result)
+		g.Expect(err).ShouldNot(BeNil())
+	})
+}
+
+func TestSQLResultStorage_Bind(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	query := "INSERT INTO t (a, b) VALUES (?, ?)"
+	g.Expect(NewSQLResultStorage(nil, "mysql").bind(query)).Should(Equal(query))
+	g.Expect(NewSQLResultStorage(nil, "postgres").bind(query)).Should(Equal("INSERT INTO t (a, b) VALUES ($1, $2)"))
+}
@@ -0,0 +1,237 @@
+package recon
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLTransactionColumns names the columns of the query result holding each
+// field of a transaction. Empty names default to id, amount, type, time and
+// currency. The currency column is optional.
+type SQLTransactionColumns struct {
+	ID       string
+	Amount   string
+	Type     string
+	Time     string
+	Currency string
+}
+
+func (c SQLTransactionColumns) withDefaults() SQLTransactionColumns {
+	c.ID = cmp.Or(c.ID, "id")
+	c.Amount = cmp.Or(c.Amount, "amount")
+	c.Type = cmp.Or(c.Type, "type")
+	c.Time = cmp.Or(c.Time, "time")
+	c.Currency = cmp.Or(c.Currency, "currency")
+	return c
+}
+
+// sqlTimeLayouts are the layouts time columns returned as text are read with,
+// RFC 3339 first, then how SQLite and MySQL write timestamps.
+var sqlTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+// sqlPeriodLayout is how the start and the end of the period are passed to
+// the query: local times without a zone, like the text times are read.
+const sqlPeriodLayout = "2006-01-02 15:04:05"
+
+// SQLTransactionStorage reads transactions with a query through database/sql
+// and stores the unmatched ones through another TransactionStorageProvider,
+// e.g. the workbook.
+type SQLTransactionStorage struct {
+	db      *sql.DB
+	query   string
+	columns SQLTransactionColumns
+
+	destination TransactionStorageProvider
+}
+
+// NewSQLTransactionStorage reads transactions from db with query. The query
+// takes the start and the end of the period as its two parameters, the end
+// excluded, e.g.
+//
+//	SELECT id, amount, type, created_at AS time FROM transactions
+//	WHERE created_at >= $1 AND created_at < $2
+//
+// Both are text, YYYY-MM-DD HH:MM:SS in the time zone of the period, so they
+// compare with columns holding local times whatever time zone the driver
+// converts time values to. Columns holding instants, e.g. timestamptz, have
+// to be compared with the parameters read in that time zone.
+//
+// Unmatched transactions are stored through destination.
+func NewSQLTransactionStorage(db *sql.DB, query string, destination TransactionStorageProvider) SQLTransactionStorage {
+	return SQLTransactionStorage{
+		db:          db,
+		query:       query,
+		columns:     SQLTransactionColumns{}.withDefaults(),
+		destination: destination,
+	}
+}
+
+// WithColumns returns a copy of the storage that reads the fields of a
+// transaction from columns.
+func (s SQLTransactionStorage) WithColumns(columns SQLTransactionColumns) SQLTransactionStorage {
+	s.columns = columns.withDefaults()
+	return s
+}
+
+func (s SQLTransactionStorage) StoreTransactions(ctx context.Context, transactions []Transaction) error {
+	return s.destination.StoreTransactions(ctx, transactions)
+}
+
+// SQLTransactionSource names transactions read with query through driver in
+// the load report, Run Info and the JSON report, e.g.
+// "postgres: SELECT id, amount, type, time FROM transactions". The data source
+// name is left out as it may hold a password.
+func SQLTransactionSource(driver string, query string) string {
+	return driver + ": " + strings.Join(strings.Fields(query), " ")
+}
+
+// GetTransactions runs the query for the period from startDate to endDate.
+// source only names the input in the load report, see SQLTransactionSource.
+// Text times without a zone are read in the time zone of startDate.
+func (s SQLTransactionStorage) GetTransactions(ctx context.Context, source string, startDate time.Time, endDate time.Time) ([]Transaction, LoadReport, error) {
+	report := LoadReport{Path: source}
+	period := NewPeriod(startDate, endDate, 0)
+
+	rows, err := s.db.QueryContext(ctx, s.query, period.Start.Format(sqlPeriodLayout), period.End.Format(sqlPeriodLayout))
+	if err != nil {
+		return nil, report, fmt.Errorf("query transactions error: %w", err)
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, report, fmt.Errorf("query columns error: %w", err)
+	}
+	index := func(name string) int {
+		return slices.IndexFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+	}
+	idColumn, amountColumn, typeColumn, timeColumn := index(s.columns.ID), index(s.columns.Amount), index(s.columns.Type), index(s.columns.Time)
+	currencyColumn := index(s.columns.Currency)
+	for _, name := range []string{s.columns.ID, s.columns.Amount, s.columns.Type, s.columns.Time} {
+		if index(name) == -1 {
+			return nil, report, fmt.Errorf("query returns no %q column", name)
+		}
+	}
+
+	checksum := sha256.New()
+	values := make([]any, len(names))
+	pointers := make([]any, len(names))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var transactions []Transaction
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, report, err
+		}
+		err := rows.Scan(pointers...)
+		if err != nil {
+			return nil, report, fmt.Errorf("scan transaction error: %w", err)
+		}
+		report.RowsRead++
+
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = sqlText(v)
+		}
+		fmt.Fprintln(checksum, strings.Join(row, "\x1f"))
+
+		if values[idColumn] == nil || values[amountColumn] == nil || values[timeColumn] == nil {
+			report.reject(report.RowsRead, row, "missing values")
+			continue
+		}
+
+		amount, err := sqlFloat(values[amountColumn])
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid amount in row: %v", row)
+		}
+
+		t, err := sqlTime(values[timeColumn], startDate.Location())
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid time format in row: %v", row)
+		}
+
+		if !period.Contains(t) {
+			report.RowsFiltered++
+			continue
+		}
+
+		tx := Transaction{
+			ID:     row[idColumn],
+			Amount: amount,
+			Type:   parseTransactionType(row[typeColumn]),
+			Time:   t,
+		}
+		if currencyColumn != -1 {
+			tx.Currency = strings.ToUpper(strings.TrimSpace(row[currencyColumn]))
+		}
+		transactions = append(transactions, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, report, fmt.Errorf("read transactions error: %w", err)
+	}
+	report.SHA256 = hex.EncodeToString(checksum.Sum(nil))
+
+	return transactions, report, nil
+}
+
+// sqlText writes a column value the way a CSV file would hold it, NULL as
+// empty.
+func sqlText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sqlFloat(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	default:
+		return strconv.ParseFloat(strings.TrimSpace(sqlText(v)), 64)
+	}
+}
+
+// sqlTime reads a time column, text without a zone in location.
+func sqlTime(v any, location *time.Location) (time.Time, error) {
+	if t, ok := v.(time.Time); ok {
+		return t, nil
+	}
+	text := strings.TrimSpace(sqlText(v))
+	for _, layout := range sqlTimeLayouts {
+		t, err := time.ParseInLocation(layout, text, location)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", text)
+}
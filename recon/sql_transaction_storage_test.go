@@ -0,0 +1,159 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/gomega"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// openSQLite opens an in-memory SQLite database running statements.
+func openSQLite(g *WithT, statements ...string) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	g.Expect(err).Should(BeNil())
+	// every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+	for _, statement := range statements {
+		_, err := db.Exec(statement)
+		g.Expect(err).Should(BeNil())
+	}
+	return db
+}
+
+func TestSQLTransactionStorage_GetTransactions(t *testing.T) {
+	startDate, _ := time.Parse(time.DateOnly, "2025-08-01")
+	endDate, _ := time.Parse(time.DateOnly, "2025-08-02")
+	query := "SELECT id, amount, type, created_at AS time, currency FROM transactions WHERE created_at >= ? AND created_at < ? ORDER BY id"
+
+	t.Run("should read the transactions of the period", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g, "CREATE TABLE transactions (id TEXT, amount REAL, type TEXT, created_at TIMESTAMP, currency TEXT)")
+		defer db.Close()
+		insert := "INSERT INTO transactions VALUES (?, ?, ?, ?, ?)"
+		for _, row := range [][]any{
+			{"1", 100.5, "debit", startDate.Add(10 * time.Hour), "usd"},
+			{"2", 200, "CREDIT", endDate.Add(23 * time.Hour), nil},
+			{"3", 300, "debit", startDate.Add(-time.Hour), nil},
+			{"4", 400, "debit", endDate.AddDate(0, 0, 1), nil},
+		} {
+			_, err := db.Exec(insert, row...)
+			g.Expect(err).Should(BeNil())
+		}
+
+		transactions, report, err := NewSQLTransactionStorage(db, query, nil).GetTransactions(context.Background(), "sqlite3", startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(HaveLen(2))
+		g.Expect(transactions[0].ID).Should(Equal("1"))
+		g.Expect(transactions[0].Amount).Should(Equal(100.5))
+		g.Expect(transactions[0].Type).Should(Equal(Debit))
+		g.Expect(transactions[0].Currency).Should(Equal("USD"))
+		g.Expect(transactions[0].Time.Equal(startDate.Add(10 * time.Hour))).Should(BeTrue())
+		g.Expect(transactions[1].Type).Should(Equal(Credit))
+		g.Expect(transactions[1].Currency).Should(BeEmpty())
+		g.Expect(report.Path).Should(Equal("sqlite3"))
+		g.Expect(report.RowsRead).Should(Equal(2))
+		g.Expect(report.SHA256).ShouldNot(BeEmpty())
+	})
+
+	t.Run("should map columns and read text times in the time zone of the period", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		jakarta, _ := time.LoadLocation("Asia/Jakarta")
+		db := openSQLite(g,
+			"CREATE TABLE ledger (trx_id TEXT, value TEXT, direction TEXT, booked_at TEXT)",
+			"INSERT INTO ledger VALUES ('a', '75', 'debit', '2025-08-01 08:30:00'), ('b', NULL, 'debit', '2025-08-01 09:00:00')",
+		)
+		defer db.Close()
+
+		storage := NewSQLTransactionStorage(db, "SELECT * FROM ledger WHERE ? IS NOT NULL AND ? IS NOT NULL", nil).
+			WithColumns(SQLTransactionColumns{ID: "trx_id", Amount: "value", Type: "direction", Time: "booked_at"})
+		transactions, report, err := storage.GetTransactions(context.Background(), "ledger", startDate.In(jakarta), endDate.In(jakarta))
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(Equal([]Transaction{{ID: "a", Amount: 75, Type: Debit, Time: time.Date(2025, 8, 1, 8, 30, 0, 0, jakarta)}}))
+		g.Expect(report.RejectedRows).Should(Equal([]RejectedRow{{Path: "ledger", Line: 2, Row: []string{"b", "", "debit", "2025-08-01 09:00:00"}, Reason: "missing values"}}))
+	})
+
+	t.Run("should filter the period in the query in the time zone of the period", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		jakarta, _ := time.LoadLocation("Asia/Jakarta")
+		db := openSQLite(g,
+			"CREATE TABLE ledger (id TEXT, amount REAL, type TEXT, booked_at TEXT)",
+			`INSERT INTO ledger VALUES
+				('before', 1, 'debit', '2025-07-31 23:59:59'),
+				('start', 2, 'debit', '2025-08-01 00:00:00'),
+				('last', 3, 'debit', '2025-08-01 23:59:59'),
+				('end', 4, 'debit', '2025-08-02 00:00:00')`,
+		)
+		defer db.Close()
+
+		day := time.Date(2025, 8, 1, 0, 0, 0, 0, jakarta)
+		storage := NewSQLTransactionStorage(db, "SELECT id, amount, type, booked_at AS time FROM ledger WHERE booked_at >= ? AND booked_at < ? ORDER BY booked_at", nil)
+		transactions, report, err := storage.GetTransactions(context.Background(), "ledger", day, day)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(Equal([]Transaction{
+			{ID: "start", Amount: 2, Type: Debit, Time: day},
+			{ID: "last", Amount: 3, Type: Debit, Time: day.Add(24*time.Hour - time.Second)},
+		}))
+		// the query returned no row outside the period
+		g.Expect(report.RowsRead).Should(Equal(2))
+		g.Expect(report.RowsFiltered).Should(BeZero())
+	})
+
+	t.Run("should return error when a column is missing", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g, "CREATE TABLE transactions (id TEXT, amount REAL, type TEXT, created_at TIMESTAMP)")
+		defer db.Close()
+
+		_, _, err := NewSQLTransactionStorage(db, "SELECT * FROM transactions WHERE ? < ?", nil).GetTransactions(context.Background(), "sqlite3", startDate, endDate)
+		g.Expect(err).Should(MatchError(ContainSubstring(`no "time" column`)))
+	})
+
+	t.Run("should return error when the amount is invalid", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g,
+			"CREATE TABLE transactions (id TEXT, amount TEXT, type TEXT, time TEXT)",
+			"INSERT INTO transactions VALUES ('1', 'abc', 'debit', '2025-08-01T10:00:00Z')",
+		)
+		defer db.Close()
+
+		_, _, err := NewSQLTransactionStorage(db, "SELECT * FROM transactions WHERE ? < ?", nil).GetTransactions(context.Background(), "sqlite3", startDate, endDate)
+		g.Expect(err).Should(MatchError(ContainSubstring("invalid amount")))
+	})
+
+	t.Run("should return error when the query fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		db := openSQLite(g)
+		defer db.Close()
+
+		_, _, err := NewSQLTransactionStorage(db, query, nil).GetTransactions(context.Background(), "sqlite3", startDate, endDate)
+		g.Expect(err).Should(MatchError(ContainSubstring("query transactions error")))
+	})
+}
+
+func TestSQLTransactionSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	source := SQLTransactionSource("postgres", "SELECT id, amount, type, time\n\tFROM transactions WHERE time >= $1 AND time < $2")
+	g.Expect(source).Should(Equal("postgres: SELECT id, amount, type, time FROM transactions WHERE time >= $1 AND time < $2"))
+}
+
+func TestSQLTransactionStorage_StoreTransactions(t *testing.T) {
+	t.Run("should store unmatched transactions through the destination", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		destination := NewMockTransactionStorageProvider(ctrl)
+		transactions := []Transaction{{ID: "1", Amount: 100, Type: Debit}}
+		destination.EXPECT().StoreTransactions(gomock.Any(), transactions).Return(nil)
+
+		err := NewSQLTransactionStorage(nil, "", destination).StoreTransactions(context.Background(), transactions)
+		g.Expect(err).Should(BeNil())
+	})
+}
@@ -0,0 +1,166 @@
+package recon
+
+import (
+	"math"
+)
+
+// poolKey queues statements of the same currency and amount.
+type poolKey struct {
+	currency string
+	amount   float64
+}
+
+// statementPool holds the bank statements of a run and hands them out to
+// transactions, queued per currency and amount in load order.
+type statementPool struct {
+	statements []BankStatement
+	matched    []bool
+	pending    map[poolKey][]int
+
+	// fx enables matching across currencies, within fxTolerance relative to
+	// the converted transaction amount.
+	fx          *currencyConverter
+	fxTolerance float64
+
+	// window limits matches to statements settling within it, when set.
+	window *settlementWindow
+}
+
+// settlementWindow is the number of business days after a transaction within
+// which its bank statement may settle.
+type settlementWindow struct {
+	calendar Calendar
+	days     int
+}
+
+// contains reports whether s settles t on the day of t or at most days
+// business days of its bank later.
+func (w settlementWindow) contains(t Transaction, s BankStatement) bool {
+	days := w.calendar.BusinessDays(t.Time, s.Time, s.Bank)
+	return days >= 0 && days <= w.days
+}
+
+func newStatementPool(statements []BankStatement) *statementPool {
+	pending := map[poolKey][]int{}
+	for i, statement := range statements {
+		key := poolKey{currency: statement.Currency, amount: statement.Amount}
+		pending[key] = append(pending[key], i)
+	}
+	return &statementPool{
+		statements: statements,
+		matched:    make([]bool, len(statements)),
+		pending:    pending,
+	}
+}
+
+// withFX lets the pool match transactions with statements in another
+// currency when no statement in the transaction currency fits.
+func (p *statementPool) withFX(converter currencyConverter, tolerance float64) *statementPool {
+	p.fx = &converter
+	p.fxTolerance = tolerance
+	return p
+}
+
+// withWindow lets the pool match transactions only with statements settling
+// at most days business days of calendar after them.
+func (p *statementPool) withWindow(calendar Calendar, days int) *statementPool {
+	p.window = &settlementWindow{calendar: calendar, days: days}
+	return p
+}
+
+// take marks the best statement for the transaction as matched and returns it.
+func (p *statementPool) take(t Transaction) (BankStatement, bool) {
+	key, position, ok := p.candidate(t)
+	if !ok {
+		key, position, ok = p.fxCandidate(t)
+	}
+	if !ok {
+		return BankStatement{}, false
+	}
+
+	queue := p.pending[key]
+	index := queue[position]
+	if len(queue) == 1 {
+		delete(p.pending, key)
+	} else {
+		p.pending[key] = append(queue[:position:position], queue[position+1:]...)
+	}
+	p.matched[index] = true
+	return p.statements[index], true
+}
+
+// first finds the position of the earliest statement queued under key that
+// may settle t.
+func (p *statementPool) first(key poolKey, t Transaction) (int, bool) {
+	queue := p.pending[key]
+	if p.window == nil {
+		return 0, len(queue) > 0
+	}
+	for position, index := range queue {
+		if p.window.contains(t, p.statements[index]) {
+			return position, true
+		}
+	}
+	return 0, false
+}
+
+// candidate picks the earliest loaded statement of the transaction currency
+// and amount.
+func (p *statementPool) candidate(t Transaction) (poolKey, int, bool) {
+	key := poolKey{currency: t.Currency, amount: t.Amount}
+	position, ok := p.first(key, t)
+	return key, position, ok
+}
+
+// fxCandidate picks the pending amount in another currency closest to the
+// converted transaction amount, relative to it.
+func (p *statementPool) fxCandidate(t Transaction) (poolKey, int, bool) {
+	if p.fx == nil {
+		return poolKey{}, 0, false
+	}
+
+	return p.closest(t, func(key poolKey) (float64, bool) {
+		if p.fx.currency(key.currency) == p.fx.currency(t.Currency) {
+			return 0, false
+		}
+		converted, err := p.fx.convert(t.Amount, t.Currency, key.currency, t.Time)
+		if err != nil || converted == 0 {
+			return 0, false
+		}
+		diff := math.Abs(key.amount-converted) / math.Abs(converted)
+		return diff, diff <= p.fxTolerance
+	})
+}
+
+// closest picks the pending key with the smallest distance that has a
+// statement for t, preferring the earliest loaded statement on ties.
+func (p *statementPool) closest(t Transaction, distance func(poolKey) (float64, bool)) (poolKey, int, bool) {
+	var best poolKey
+	bestPosition, bestIndex, bestDiff, found := 0, 0, 0.0, false
+	for key, queue := range p.pending {
+		diff, ok := distance(key)
+		if !ok || (found && diff > bestDiff) {
+			continue
+		}
+		position, ok := p.first(key, t)
+		if !ok {
+			continue
+		}
+		index := queue[position]
+		if !found || diff < bestDiff || index < bestIndex {
+			best, bestPosition, bestIndex, bestDiff, found = key, position, index, diff, true
+		}
+	}
+	return best, bestPosition, found
+}
+
+// unmatched returns the statements never taken, in load order.
+func (p *statementPool) unmatched() []BankStatement {
+	var statements []BankStatement
+	for i, statement := range p.statements {
+		if !p.matched[i] {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
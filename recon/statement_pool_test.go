@@ -0,0 +1,106 @@
+package recon
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStatementPool_Take(t *testing.T) {
+	statements := []BankStatement{
+		{ID: "1", Amount: 100},
+		{ID: "2", Amount: 100},
+		{ID: "3", Amount: 201},
+		{ID: "4", Amount: 199},
+	}
+
+	t.Run("takes exact amounts in load order", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		pool := newStatementPool(statements)
+
+		first, ok := pool.take(Transaction{Amount: 100})
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(first.ID).Should(Equal("1"))
+
+		second, ok := pool.take(Transaction{Amount: 100})
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(second.ID).Should(Equal("2"))
+
+		_, ok = pool.take(Transaction{Amount: 100})
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("does not match different amounts", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		pool := newStatementPool(statements)
+
+		_, ok := pool.take(Transaction{Amount: 200})
+		g.Expect(ok).Should(BeFalse())
+		g.Expect(pool.unmatched()).Should(Equal(statements))
+	})
+
+	t.Run("returns unmatched statements in load order", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		pool := newStatementPool(statements)
+		pool.take(Transaction{Amount: 201})
+		pool.take(Transaction{Amount: 100})
+
+		g.Expect(pool.unmatched()).Should(Equal([]BankStatement{statements[1], statements[3]}))
+	})
+
+	t.Run("takes statements in another currency within FX tolerance", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		converter := currencyConverter{
+			rates:     NewFXRates([]FXRate{{Date: at, Base: "USD", Quote: "IDR", Rate: 16000}}),
+			reporting: "IDR",
+		}
+		fxStatements := []BankStatement{
+			{ID: "a", Amount: 159000, Currency: "IDR"},
+			{ID: "b", Amount: 160100, Currency: "IDR"},
+			{ID: "c", Amount: 10, Currency: "USD"},
+		}
+		pool := newStatementPool(fxStatements).withFX(converter, 0.01)
+
+		statement, ok := pool.take(Transaction{Amount: 10, Currency: "USD", Time: at})
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(statement.ID).Should(Equal("c"))
+
+		statement, ok = pool.take(Transaction{Amount: 10, Currency: "USD", Time: at})
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(statement.ID).Should(Equal("b"))
+
+		_, ok = pool.take(Transaction{Amount: 20, Currency: "USD", Time: at})
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("takes statements settling within the window", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		// 2025-03-28 is a Friday, 2025-03-31 and 2025-04-01 are holidays
+		friday := time.Date(2025, 3, 28, 10, 0, 0, 0, time.UTC)
+		calendar := NewCalendar([]Holiday{
+			{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+			{Date: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		})
+		windowStatements := []BankStatement{
+			{ID: "early", Amount: 100, Time: friday.AddDate(0, 0, -1)},
+			{ID: "late", Amount: 100, Time: friday.AddDate(0, 0, 6)},
+			{ID: "wednesday", Amount: 100, Time: friday.AddDate(0, 0, 5)},
+		}
+		pool := newStatementPool(windowStatements).withWindow(calendar, 1)
+
+		statement, ok := pool.take(Transaction{Amount: 100, Time: friday})
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(statement.ID).Should(Equal("wednesday"))
+
+		_, ok = pool.take(Transaction{Amount: 100, Time: friday})
+		g.Expect(ok).Should(BeFalse())
+		g.Expect(pool.unmatched()).Should(Equal(windowStatements[:2]))
+	})
+}
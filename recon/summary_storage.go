@@ -1,17 +1,133 @@
 package recon
 
 import (
+	"context"
 	"fmt"
+	"math"
 
 	"github.com/xuri/excelize/v2"
 )
 
+// amountEpsilon is the tolerance used when comparing float amounts that are
+// expected to be equal, e.g. both sides of the reconciliation equation.
+const amountEpsilon = 0.005
+
 type Summary struct {
-	TotalAmountTransactions   float64
-	TotalAmountBankStatements float64
-	TotalMatched              int
-	TotalUnmatched            int
-	TotalProcessed            int
+	TotalTransactions           int
+	TotalAmountTransactions     float64
+	MatchedTransactions         int
+	MatchedAmountTransactions   float64
+	UnmatchedTransactions       int
+	UnmatchedAmountTransactions float64
+
+	TotalBankStatements           int
+	TotalAmountBankStatements     float64
+	MatchedBankStatements         int
+	MatchedAmountBankStatements   float64
+	UnmatchedBankStatements       int
+	UnmatchedAmountBankStatements float64
+
+	// MatchedAmountDifference is the sum of transaction amount minus bank
+	// statement amount over every matched pair, less FXDifference.
+	MatchedAmountDifference float64
+	// FXDifference is the part of the matched pairs' difference that comes
+	// from exchange rates.
+	FXDifference float64
+	// ReportingCurrency is the currency of the amounts, empty when they were
+	// added up without conversion.
+	ReportingCurrency string
+
+	// Accounts breaks the bank statement side down per bank account, sorted
+	// by bank and account.
+	Accounts []AccountSummary
+
+	// BalanceChecks verify the bank statement lines against the opening and
+	// closing balance of each bank that has them.
+	BalanceChecks []BalanceCheck
+
+	// Unaccounted describes, per side and currency, the items that went into
+	// the run but are neither matched, unmatched, excluded, reversed nor left
+	// out as duplicates. Reports show them as warnings.
+	Unaccounted []string
+}
+
+// AccountSummary counts the bank statements of one bank account.
+type AccountSummary struct {
+	Bank    string
+	Account string
+
+	TotalBankStatements           int
+	TotalAmountBankStatements     float64
+	MatchedBankStatements         int
+	MatchedAmountBankStatements   float64
+	UnmatchedBankStatements       int
+	UnmatchedAmountBankStatements float64
+}
+
+func (a AccountSummary) BankAccount() BankAccount {
+	return BankAccount{Bank: a.Bank, Account: a.Account}
+}
+
+// BalanceBreaks counts the banks whose lines do not add up to the closing balance.
+func (s Summary) BalanceBreaks() int {
+	var breaks int
+	for _, check := range s.BalanceChecks {
+		if check.Broken() {
+			breaks++
+		}
+	}
+	return breaks
+}
+
+// TotalProcessed is the number of transactions and bank statements taken into the recon.
+func (s Summary) TotalProcessed() int {
+	return s.TotalTransactions + s.TotalBankStatements
+}
+
+// AmountDiscrepancy is the gross difference between the transaction side and the bank statement side.
+func (s Summary) AmountDiscrepancy() float64 {
+	return s.TotalAmountTransactions - s.TotalAmountBankStatements
+}
+
+// ExplainedDiscrepancy is the discrepancy explained by unmatched items and by
+// differences inside matched pairs.
+func (s Summary) ExplainedDiscrepancy() float64 {
+	return s.UnmatchedAmountTransactions - s.UnmatchedAmountBankStatements + s.MatchedAmountDifference + s.FXDifference
+}
+
+// Balanced reports whether the reconciliation equation holds:
+//
+//	transactions - bank statements = unmatched transactions - unmatched bank statements + matched difference + fx difference
+//
+// whether the matched and unmatched parts of each side add up to its total,
+// and whether every item that went into the run is accounted for. Amounts
+// are compared as totals, with a tolerance relative to their size.
+func (s Summary) Balanced() bool {
+	return s.MatchedTransactions+s.UnmatchedTransactions == s.TotalTransactions &&
+		s.MatchedBankStatements+s.UnmatchedBankStatements == s.TotalBankStatements &&
+		totalsEqual(s.MatchedAmountTransactions+s.UnmatchedAmountTransactions, s.TotalAmountTransactions) &&
+		totalsEqual(s.MatchedAmountBankStatements+s.UnmatchedAmountBankStatements, s.TotalAmountBankStatements) &&
+		totalsEqual(s.MatchedAmountTransactions, s.MatchedAmountBankStatements+s.MatchedAmountDifference+s.FXDifference) &&
+		totalsEqual(s.TotalAmountTransactions, s.TotalAmountBankStatements+s.ExplainedDiscrepancy()) &&
+		len(s.Unaccounted) == 0
+}
+
+func balanceSource(check BalanceCheck) string {
+	if check.Declared {
+		return "declared"
+	}
+	return "running balance"
+}
+
+func balanceStatus(check BalanceCheck) string {
+	if check.Broken() {
+		return "break"
+	}
+	return "ok"
+}
+
+func amountEqual(a, b float64) bool {
+	return math.Abs(a-b) < amountEpsilon
 }
 
 type SummaryStorage struct {
@@ -28,7 +144,7 @@ func NewSummaryStorage(destinationFileNamePath string, destinationSheetName stri
 	}
 }
 
-func (s SummaryStorage) StoreSummary(total Summary) error {
+func (s SummaryStorage) StoreSummary(ctx context.Context, total Summary) error {
 	f, err := s.excelWriterFactory.New(s.destinationFileNamePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -46,14 +162,38 @@ func (s SummaryStorage) StoreSummary(total Summary) error {
 		}
 	}
 
-	// key-value pairs
+	// metric, count, amount
 	rows := [][]any{
-		{"Total Amount Transactions", total.TotalAmountTransactions},
-		{"Total Amount Bank Statements", total.TotalAmountBankStatements},
-		{"Total Matched", total.TotalMatched},
-		{"Total Unmatched", total.TotalUnmatched},
-		{"Total Processed", total.TotalProcessed},
-		{"Total Amount Dicrepancy", total.TotalAmountTransactions - total.TotalAmountBankStatements},
+		{"Metric", "Count", "Amount"},
+		{"Transactions", total.TotalTransactions, total.TotalAmountTransactions},
+		{"Matched Transactions", total.MatchedTransactions, total.MatchedAmountTransactions},
+		{"Unmatched Transactions", total.UnmatchedTransactions, total.UnmatchedAmountTransactions},
+		{"Bank Statements", total.TotalBankStatements, total.TotalAmountBankStatements},
+		{"Matched Bank Statements", total.MatchedBankStatements, total.MatchedAmountBankStatements},
+		{"Unmatched Bank Statements", total.UnmatchedBankStatements, total.UnmatchedAmountBankStatements},
+		{"Total Processed", total.TotalProcessed()},
+		{"Matched Amount Difference", "", total.MatchedAmountDifference},
+		{"FX Difference", "", total.FXDifference},
+		{"Total Amount Discrepancy", "", total.AmountDiscrepancy()},
+		{"Explained Discrepancy", "", total.ExplainedDiscrepancy()},
+		{"Balanced", total.Balanced()},
+		{"Balance Breaks", total.BalanceBreaks()},
+		{"Reporting Currency", total.ReportingCurrency},
+	}
+	for _, warning := range total.Unaccounted {
+		rows = append(rows, []any{"Warning", warning})
+	}
+	if len(total.Accounts) > 0 {
+		rows = append(rows, []any{}, []any{"Account", "Bank Statements", "Amount", "Matched", "Matched Amount", "Unmatched", "Unmatched Amount"})
+		for _, a := range total.Accounts {
+			rows = append(rows, []any{a.BankAccount().String(), a.TotalBankStatements, a.TotalAmountBankStatements, a.MatchedBankStatements, a.MatchedAmountBankStatements, a.UnmatchedBankStatements, a.UnmatchedAmountBankStatements})
+		}
+	}
+	if len(total.BalanceChecks) > 0 {
+		rows = append(rows, []any{}, []any{"Account", "Opening", "Lines", "Closing", "Difference", "Gaps", "Source", "Status"})
+		for _, check := range total.BalanceChecks {
+			rows = append(rows, []any{check.BankAccount().String(), check.Opening, check.Lines, check.Closing, check.Difference(), len(check.Gaps), balanceSource(check), balanceStatus(check)})
+		}
 	}
 
 	for i, row := range rows {
@@ -1,10 +1,12 @@
 package recon
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/xuri/excelize/v2"
 	"go.uber.org/mock/gomock"
 )
 
@@ -25,6 +27,123 @@ func summaryStorageSuite(ctrl *gomock.Controller) SummaryStorageSuite {
 	}
 }
 
+func sampleSummary() Summary {
+	return Summary{
+		TotalTransactions:             3,
+		TotalAmountTransactions:       300.0,
+		MatchedTransactions:           2,
+		MatchedAmountTransactions:     200.0,
+		UnmatchedTransactions:         1,
+		UnmatchedAmountTransactions:   100.0,
+		TotalBankStatements:           3,
+		TotalAmountBankStatements:     290.0,
+		MatchedBankStatements:         2,
+		MatchedAmountBankStatements:   200.0,
+		UnmatchedBankStatements:       1,
+		UnmatchedAmountBankStatements: 90.0,
+	}
+}
+
+func expectSummaryRows(mockExcelWriter *MockExcelWriter, sheet string, summary Summary) {
+	rows := [][]any{
+		{"Metric", "Count", "Amount"},
+		{"Transactions", summary.TotalTransactions, summary.TotalAmountTransactions},
+		{"Matched Transactions", summary.MatchedTransactions, summary.MatchedAmountTransactions},
+		{"Unmatched Transactions", summary.UnmatchedTransactions, summary.UnmatchedAmountTransactions},
+		{"Bank Statements", summary.TotalBankStatements, summary.TotalAmountBankStatements},
+		{"Matched Bank Statements", summary.MatchedBankStatements, summary.MatchedAmountBankStatements},
+		{"Unmatched Bank Statements", summary.UnmatchedBankStatements, summary.UnmatchedAmountBankStatements},
+		{"Total Processed", summary.TotalProcessed()},
+		{"Matched Amount Difference", "", summary.MatchedAmountDifference},
+		{"FX Difference", "", summary.FXDifference},
+		{"Total Amount Discrepancy", "", summary.AmountDiscrepancy()},
+		{"Explained Discrepancy", "", summary.ExplainedDiscrepancy()},
+		{"Balanced", summary.Balanced()},
+		{"Balance Breaks", summary.BalanceBreaks()},
+		{"Reporting Currency", summary.ReportingCurrency},
+	}
+	for i, row := range rows {
+		for j, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			mockExcelWriter.EXPECT().SetCellValue(sheet, cell, v).Return(nil)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	t.Run("metrics of a balanced summary", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+
+		g.Expect(summary.TotalProcessed()).Should(Equal(6))
+		g.Expect(summary.AmountDiscrepancy()).Should(Equal(10.0))
+		g.Expect(summary.ExplainedDiscrepancy()).Should(Equal(10.0))
+		g.Expect(summary.Balanced()).Should(BeTrue())
+	})
+
+	t.Run("matched amount difference is part of the explained discrepancy", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+		summary.MatchedAmountBankStatements = 195.0
+		summary.TotalAmountBankStatements = 285.0
+		summary.MatchedAmountDifference = 5.0
+
+		g.Expect(summary.AmountDiscrepancy()).Should(Equal(15.0))
+		g.Expect(summary.ExplainedDiscrepancy()).Should(Equal(15.0))
+		g.Expect(summary.Balanced()).Should(BeTrue())
+	})
+
+	t.Run("not balanced when counts do not add up", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+		summary.MatchedTransactions = 3
+
+		g.Expect(summary.Balanced()).Should(BeFalse())
+	})
+
+	t.Run("not balanced when amounts do not add up", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+		summary.UnmatchedAmountBankStatements = 80.0
+
+		g.Expect(summary.Balanced()).Should(BeFalse())
+	})
+
+	t.Run("not balanced when items are unaccounted for", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+		summary.Unaccounted = []string{"transactions in IDR: 3 loaded (300.00), 2 accounted for (200.00)"}
+
+		g.Expect(summary.Balanced()).Should(BeFalse())
+	})
+
+	t.Run("balanced with float drift in large totals", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+		summary.TotalAmountTransactions += 12_345_678_901_234.0
+		summary.MatchedAmountTransactions += 12_345_678_901_234.02
+		summary.TotalAmountBankStatements += 12_345_678_901_234.0
+		summary.MatchedAmountBankStatements += 12_345_678_901_234.0
+
+		g.Expect(summary.Balanced()).Should(BeTrue())
+	})
+
+	t.Run("not balanced when matched difference is wrong", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		summary := sampleSummary()
+		summary.MatchedAmountDifference = 1.0
+
+		g.Expect(summary.Balanced()).Should(BeFalse())
+	})
+}
+
 func TestSummaryStorage_StoreSummary(t *testing.T) {
 	destinationFileNamePath := "test.xlsx"
 	destinationSheetName := "Summary"
@@ -36,53 +155,117 @@ func TestSummaryStorage_StoreSummary(t *testing.T) {
 		g := NewGomegaWithT(t)
 		suite := summaryStorageSuite(ctrl)
 
-		summary := Summary{
-			TotalAmountTransactions:   100.0,
-			TotalAmountBankStatements: 90.0,
-			TotalMatched:              50,
-			TotalUnmatched:            50,
-			TotalProcessed:            100,
+		summary := sampleSummary()
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		expectSummaryRows(suite.mockExcelWriter, destinationSheetName, summary)
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("success with balance checks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := summaryStorageSuite(ctrl)
+
+		summary := sampleSummary()
+		summary.BalanceChecks = []BalanceCheck{
+			{Bank: "bca", Opening: 1000, Lines: 200, Closing: 1200},
+			{Bank: "bri", Opening: 500, Lines: 90, Closing: 600, Declared: true, Gaps: []BalanceGap{{Line: 3, ID: "2", Expected: 550, Actual: 560}}},
+		}
+
+		cells := map[string]any{
+			"A17": "Account", "B17": "Opening", "C17": "Lines", "D17": "Closing", "E17": "Difference", "F17": "Gaps", "G17": "Source", "H17": "Status",
+			"A18": "bca", "B18": 1000.0, "C18": 200.0, "D18": 1200.0, "E18": 0.0, "F18": 0, "G18": "running balance", "H18": "ok",
+			"A19": "bri", "B19": 500.0, "C19": 90.0, "D19": 600.0, "E19": 10.0, "F19": 1, "G19": "declared", "H19": "break",
 		}
 
 		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
 		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A1", "Total Amount Transactions").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B1", summary.TotalAmountTransactions).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A2", "Total Amount Bank Statements").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B2", summary.TotalAmountBankStatements).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A3", "Total Matched").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B3", summary.TotalMatched).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A4", "Total Unmatched").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B4", summary.TotalUnmatched).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A5", "Total Processed").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B5", summary.TotalProcessed).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A6", "Total Amount Dicrepancy").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B6", summary.TotalAmountTransactions-summary.TotalAmountBankStatements).Return(nil)
+		expectSummaryRows(suite.mockExcelWriter, destinationSheetName, summary)
+		for cell, v := range cells {
+			suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
+		}
 		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
 
-		err := suite.summaryStorage.StoreSummary(summary)
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
 
 		g.Expect(err).Should(BeNil())
+		g.Expect(summary.BalanceBreaks()).Should(Equal(1))
 	})
 
-	t.Run("excelize open file error", func(t *testing.T) {
+	t.Run("success with warnings", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		g := NewGomegaWithT(t)
 		suite := summaryStorageSuite(ctrl)
 
-		summary := Summary{
-			TotalAmountTransactions:   100.0,
-			TotalAmountBankStatements: 90.0,
-			TotalMatched:              50,
-			TotalUnmatched:            50,
-			TotalProcessed:            100,
+		summary := sampleSummary()
+		summary.Unaccounted = []string{"transactions in IDR: 3 loaded (300.00), 2 accounted for (200.00)"}
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		expectSummaryRows(suite.mockExcelWriter, destinationSheetName, summary)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A16", "Warning").Return(nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B16", summary.Unaccounted[0]).Return(nil)
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("success with accounts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := summaryStorageSuite(ctrl)
+
+		summary := sampleSummary()
+		summary.Accounts = []AccountSummary{
+			{Bank: "bca", Account: "111", TotalBankStatements: 2, TotalAmountBankStatements: 190, MatchedBankStatements: 1, MatchedAmountBankStatements: 100, UnmatchedBankStatements: 1, UnmatchedAmountBankStatements: 90},
+			{Bank: "bri", TotalBankStatements: 1, TotalAmountBankStatements: 100, MatchedBankStatements: 1, MatchedAmountBankStatements: 100},
+		}
+
+		cells := map[string]any{
+			"A17": "Account", "B17": "Bank Statements", "C17": "Amount", "D17": "Matched", "E17": "Matched Amount", "F17": "Unmatched", "G17": "Unmatched Amount",
+			"A18": "bca 111", "B18": 2, "C18": 190.0, "D18": 1, "E18": 100.0, "F18": 1, "G18": 90.0,
+			"A19": "bri", "B19": 1, "C19": 100.0, "D19": 1, "E19": 100.0, "F19": 0, "G19": 0.0,
+		}
+
+		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
+		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
+		expectSummaryRows(suite.mockExcelWriter, destinationSheetName, summary)
+		for cell, v := range cells {
+			suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, cell, v).Return(nil)
 		}
+		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
+
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
+
+		g.Expect(err).Should(BeNil())
+	})
+
+	t.Run("excelize open file error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g := NewGomegaWithT(t)
+		suite := summaryStorageSuite(ctrl)
+
+		summary := sampleSummary()
 
 		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, errors.New("open file error"))
 
-		err := suite.summaryStorage.StoreSummary(summary)
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -94,18 +277,12 @@ func TestSummaryStorage_StoreSummary(t *testing.T) {
 		g := NewGomegaWithT(t)
 		suite := summaryStorageSuite(ctrl)
 
-		summary := Summary{
-			TotalAmountTransactions:   100.0,
-			TotalAmountBankStatements: 90.0,
-			TotalMatched:              50,
-			TotalUnmatched:            50,
-			TotalProcessed:            100,
-		}
+		summary := sampleSummary()
 
 		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
 		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(0, errors.New("get sheet index error"))
 
-		err := suite.summaryStorage.StoreSummary(summary)
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -117,31 +294,14 @@ func TestSummaryStorage_StoreSummary(t *testing.T) {
 		g := NewGomegaWithT(t)
 		suite := summaryStorageSuite(ctrl)
 
-		summary := Summary{
-			TotalAmountTransactions:   100.0,
-			TotalAmountBankStatements: 90.0,
-			TotalMatched:              50,
-			TotalUnmatched:            50,
-			TotalProcessed:            100,
-		}
+		summary := sampleSummary()
 
 		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(suite.mockExcelWriter, nil)
 		suite.mockExcelWriter.EXPECT().GetSheetIndex(destinationSheetName).Return(1, nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A1", "Total Amount Transactions").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B1", summary.TotalAmountTransactions).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A2", "Total Amount Bank Statements").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B2", summary.TotalAmountBankStatements).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A3", "Total Matched").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B3", summary.TotalMatched).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A4", "Total Unmatched").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B4", summary.TotalUnmatched).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A5", "Total Processed").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B5", summary.TotalProcessed).Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A6", "Total Amount Dicrepancy").Return(nil)
-		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B6", summary.TotalAmountTransactions-summary.TotalAmountBankStatements).Return(nil)
+		expectSummaryRows(suite.mockExcelWriter, destinationSheetName, summary)
 		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(errors.New("save as error"))
 
-		err := suite.summaryStorage.StoreSummary(summary)
+		err := suite.summaryStorage.StoreSummary(context.Background(), summary)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -1,8 +1,10 @@
 package recon
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/xuri/excelize/v2"
@@ -15,11 +17,19 @@ const (
 	Credit TransactionType = "credit"
 )
 
+// parseTransactionType reads a type column the same way for every input,
+// so " DEBIT" and "debit" are the same type.
+func parseTransactionType(s string) TransactionType {
+	return TransactionType(strings.ToLower(strings.TrimSpace(s)))
+}
+
 type Transaction struct {
 	ID     string
 	Amount float64
-	Type   TransactionType
-	Time   time.Time
+	// Currency is the ISO 4217 code of Amount, empty when unspecified.
+	Currency string `json:",omitempty"`
+	Type     TransactionType
+	Time     time.Time
 }
 
 type TransactionStorage struct {
@@ -39,7 +49,7 @@ func NewTransactionStorage(destinationFileNamePath string, destinationSheetName
 	}
 }
 
-func (t TransactionStorage) StoreTransactions(transactions []Transaction) error {
+func (t TransactionStorage) StoreTransactions(ctx context.Context, transactions []Transaction) error {
 	f, err := t.excelWriterFactory.New(t.destinationFileNamePath)
 	if err != nil {
 		return fmt.Errorf("failed to create excel file: %w", err)
@@ -58,7 +68,7 @@ func (t TransactionStorage) StoreTransactions(transactions []Transaction) error
 	}
 
 	// header
-	headers := []string{"Id", "Amount", "Type", "Time"}
+	headers := []string{"Id", "Amount", "Type", "Time", "Currency"}
 	for i, h := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		f.SetCellValue(t.destinationSheetName, cell, h)
@@ -71,6 +81,7 @@ func (t TransactionStorage) StoreTransactions(transactions []Transaction) error
 			tx.Amount,
 			string(tx.Type),
 			tx.Time.Format(time.RFC3339),
+			tx.Currency,
 		}
 		for col, v := range values {
 			cell, _ := excelize.CoordinatesToCellName(col+1, row+2)
@@ -85,47 +96,68 @@ func (t TransactionStorage) StoreTransactions(transactions []Transaction) error
 	return nil
 }
 
-func (t TransactionStorage) GetTransactions(filename string, startDate time.Time, endDate time.Time) ([]Transaction, error) {
-	reader, err := t.readerFactory.NewReader(filename)
+func (t TransactionStorage) GetTransactions(ctx context.Context, filename string, startDate time.Time, endDate time.Time) ([]Transaction, LoadReport, error) {
+	report := LoadReport{Path: filename}
+
+	reader, err := t.readerFactory.NewReader(ctx, filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, report, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer reader.Close()
 
 	records, err := reader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, report, fmt.Errorf("failed to read file: %w", err)
 	}
+	report.SHA256 = reader.Checksum()
 
 	if len(records) < 2 {
-		return nil, fmt.Errorf("no data rows found")
+		return nil, report, fmt.Errorf("no data rows found")
 	}
+	report.RowsRead = len(records) - 1
+
+	// an optional currency column holds the currency of the amount
+	currencyColumn := columnIndex(records[0], "currency")
+
+	period := NewPeriod(startDate, endDate, 0)
 
 	var transactions []Transaction
 	// Skip header (records[0])
-	for _, row := range records[1:] {
+	for i, row := range records[1:] {
+		if err := ctx.Err(); err != nil {
+			return nil, report, err
+		}
+		if len(row) < max(4, currencyColumn+1) {
+			report.reject(i+2, row, "missing columns")
+			continue
+		}
+
 		amount, err := strconv.ParseFloat(row[1], 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid amount in row: %v", row)
+			return nil, report, fmt.Errorf("invalid amount in row: %v", row)
 		}
 
 		t, err := time.Parse(time.RFC3339, row[3])
 		if err != nil {
-			return nil, fmt.Errorf("invalid time format in row: %v", row)
+			return nil, report, fmt.Errorf("invalid time format in row: %v", row)
 		}
 
-		if t.Before(startDate) || t.After(endDate.Add(24*time.Hour)) {
+		if !period.Contains(t) {
+			report.RowsFiltered++
 			continue
 		}
 
 		tx := Transaction{
 			ID:     row[0],
 			Amount: amount,
-			Type:   TransactionType(row[2]),
+			Type:   parseTransactionType(row[2]),
 			Time:   t,
 		}
+		if currencyColumn != -1 {
+			tx.Currency = strings.ToUpper(strings.TrimSpace(row[currencyColumn]))
+		}
 		transactions = append(transactions, tx)
 	}
 
-	return transactions, nil
+	return transactions, report, nil
 }
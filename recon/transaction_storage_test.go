@@ -1,6 +1,7 @@
 package recon
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -65,20 +66,23 @@ func TestTransactionStorage_StoreTransactions(t *testing.T) {
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B1", "Amount").Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "C1", "Type").Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "D1", "Time").Return(nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "E1", "Currency").Return(nil)
 
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A2", transactions[0].ID).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B2", transactions[0].Amount).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "C2", string(transactions[0].Type)).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "D2", transactions[0].Time.Format(time.RFC3339)).Return(nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "E2", transactions[0].Currency).Return(nil)
 
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A3", transactions[1].ID).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B3", transactions[1].Amount).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "C3", string(transactions[1].Type)).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "D3", transactions[1].Time.Format(time.RFC3339)).Return(nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "E3", transactions[1].Currency).Return(nil)
 
 		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(nil)
 
-		err := suite.transactionStorage.StoreTransactions(transactions)
+		err := suite.transactionStorage.StoreTransactions(context.Background(), transactions)
 
 		g.Expect(err).Should(BeNil())
 	})
@@ -95,7 +99,7 @@ func TestTransactionStorage_StoreTransactions(t *testing.T) {
 
 		suite.mockExcelWriterFactory.EXPECT().New(destinationFileNamePath).Return(nil, fmt.Errorf("new error"))
 
-		err := suite.transactionStorage.StoreTransactions(transactions)
+		err := suite.transactionStorage.StoreTransactions(context.Background(), transactions)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -124,15 +128,17 @@ func TestTransactionStorage_StoreTransactions(t *testing.T) {
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B1", "Amount").Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "C1", "Type").Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "D1", "Time").Return(nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "E1", "Currency").Return(nil)
 
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "A2", transactions[0].ID).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "B2", transactions[0].Amount).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "C2", string(transactions[0].Type)).Return(nil)
 		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "D2", transactions[0].Time.Format(time.RFC3339)).Return(nil)
+		suite.mockExcelWriter.EXPECT().SetCellValue(destinationSheetName, "E2", transactions[0].Currency).Return(nil)
 
 		suite.mockExcelWriter.EXPECT().SaveAs(destinationFileNamePath).Return(fmt.Errorf("save error"))
 
-		err := suite.transactionStorage.StoreTransactions(transactions)
+		err := suite.transactionStorage.StoreTransactions(context.Background(), transactions)
 
 		g.Expect(err).ShouldNot(BeNil())
 	})
@@ -159,11 +165,12 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 			{"2", "200.0", "debit", endDate.Format(time.RFC3339)},
 		}
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(suite.mockReader, nil)
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
 		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
 		suite.mockReader.EXPECT().Close().Return(nil)
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).Should(BeNil())
 		g.Expect(transactions).Should(HaveLen(2))
@@ -175,6 +182,31 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 		g.Expect(transactions[1].Type).Should(Equal(TransactionType("debit")))
 	})
 
+	t.Run("should stop parsing when the context is canceled", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getTransactionStorageSuite(ctrl)
+
+		mockRecords := [][]string{
+			{"Id", "Amount", "Type", "Time"},
+			{"1", "100.0", "credit", startDate.Format(time.RFC3339)},
+		}
+
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
+		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
+		suite.mockReader.EXPECT().Close().Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := suite.transactionStorage.GetTransactions(ctx, filename, startDate, endDate)
+
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+
 	t.Run("should return error when readerFactory.NewReader returns error", func(t *testing.T) {
 		g := NewGomegaWithT(t)
 
@@ -183,9 +215,9 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 
 		suite := getTransactionStorageSuite(ctrl)
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(nil, fmt.Errorf("new reader error"))
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(nil, fmt.Errorf("new reader error"))
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(transactions).Should(BeNil())
@@ -199,11 +231,11 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 
 		suite := getTransactionStorageSuite(ctrl)
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(suite.mockReader, nil)
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
 		suite.mockReader.EXPECT().ReadAll().Return(nil, fmt.Errorf("read all error"))
 		suite.mockReader.EXPECT().Close().Return(nil)
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(transactions).Should(BeNil())
@@ -221,11 +253,12 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 			{"Id", "Amount", "Type", "Time"},
 		}
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(suite.mockReader, nil)
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
 		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
 		suite.mockReader.EXPECT().Close().Return(nil)
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(transactions).Should(BeNil())
@@ -244,11 +277,12 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 			{"1", "invalid", "credit", startDate.Format(time.RFC3339)},
 		}
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(suite.mockReader, nil)
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
 		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
 		suite.mockReader.EXPECT().Close().Return(nil)
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(transactions).Should(BeNil())
@@ -267,11 +301,12 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 			{"1", "100.0", "credit", "invalid"},
 		}
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(suite.mockReader, nil)
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
 		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
 		suite.mockReader.EXPECT().Close().Return(nil)
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).ShouldNot(BeNil())
 		g.Expect(transactions).Should(BeNil())
@@ -294,14 +329,140 @@ func TestTransactionStorage_GetTransactions(t *testing.T) {
 			{"2", "200.0", "debit", endDate.Format(time.RFC3339)},
 		}
 
-		suite.mockReaderFactory.EXPECT().NewReader(filename).Return(suite.mockReader, nil)
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
 		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
 		suite.mockReader.EXPECT().Close().Return(nil)
 
-		transactions, err := suite.transactionStorage.GetTransactions(filename, startDate, endDate)
+		transactions, report, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
 
 		g.Expect(err).Should(BeNil())
 		g.Expect(transactions).Should(HaveLen(1))
 		g.Expect(transactions[0].ID).Should(Equal("2"))
+		g.Expect(report.RowsRead).Should(Equal(2))
+		g.Expect(report.RowsFiltered).Should(Equal(1))
+		g.Expect(report.RowsLoaded()).Should(Equal(1))
+	})
+
+	t.Run("should filter on half-open business days in the time zone of the dates", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getTransactionStorageSuite(ctrl)
+
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		start := time.Date(2025, 8, 28, 0, 0, 0, 0, jakarta)
+		end := time.Date(2025, 8, 29, 0, 0, 0, 0, jakarta)
+
+		mockRecords := [][]string{
+			{"Id", "Amount", "Type", "Time"},
+			{"1", "100.0", "credit", "2025-08-27T16:59:59Z"},
+			{"2", "100.0", "credit", "2025-08-27T17:00:00Z"},
+			{"3", "100.0", "credit", "2025-08-29T23:59:59+07:00"},
+			{"4", "100.0", "credit", "2025-08-30T00:00:00+07:00"},
+		}
+
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
+		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
+		suite.mockReader.EXPECT().Close().Return(nil)
+
+		transactions, report, err := suite.transactionStorage.GetTransactions(context.Background(), filename, start, end)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(HaveLen(2))
+		g.Expect(transactions[0].ID).Should(Equal("2"))
+		g.Expect(transactions[1].ID).Should(Equal("3"))
+		g.Expect(report.RowsFiltered).Should(Equal(2))
+	})
+
+	t.Run("should reject rows with missing columns", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getTransactionStorageSuite(ctrl)
+
+		mockRecords := [][]string{
+			{"Id", "Amount", "Type", "Time"},
+			{"1", "100.0"},
+			{"2", "200.0", "debit", endDate.Format(time.RFC3339)},
+		}
+
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
+		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
+		suite.mockReader.EXPECT().Close().Return(nil)
+
+		transactions, report, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(HaveLen(1))
+		g.Expect(report.Path).Should(Equal(filename))
+		g.Expect(report.SHA256).Should(Equal("checksum"))
+		g.Expect(report.RowsRead).Should(Equal(2))
+		g.Expect(report.RowsRejected()).Should(Equal(1))
+		g.Expect(report.RowsLoaded()).Should(Equal(1))
+		g.Expect(report.RejectedRows).Should(Equal([]RejectedRow{
+			{Path: filename, Line: 2, Row: []string{"1", "100.0"}, Reason: "missing columns"},
+		}))
+	})
+
+	t.Run("should read currencies from the currency column", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getTransactionStorageSuite(ctrl)
+
+		mockRecords := [][]string{
+			{"Id", "Amount", "Type", "Time", "Currency"},
+			{"1", "10.0", "credit", startDate.Format(time.RFC3339), " usd "},
+			{"2", "200.0", "debit", endDate.Format(time.RFC3339), ""},
+		}
+
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
+		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
+		suite.mockReader.EXPECT().Close().Return(nil)
+
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(Equal([]Transaction{
+			{ID: "1", Amount: 10.0, Currency: "USD", Type: Credit, Time: startDate},
+			{ID: "2", Amount: 200.0, Type: Debit, Time: endDate},
+		}))
+	})
+	t.Run("should read types like the database storage does", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		suite := getTransactionStorageSuite(ctrl)
+
+		mockRecords := [][]string{
+			{"Id", "Amount", "Type", "Time"},
+			{"1", "10.0", " Credit", startDate.Format(time.RFC3339)},
+			{"2", "200.0", "DEBIT", endDate.Format(time.RFC3339)},
+		}
+
+		suite.mockReaderFactory.EXPECT().NewReader(gomock.Any(), filename).Return(suite.mockReader, nil)
+		suite.mockReader.EXPECT().ReadAll().Return(mockRecords, nil)
+		suite.mockReader.EXPECT().Checksum().Return("checksum")
+		suite.mockReader.EXPECT().Close().Return(nil)
+
+		transactions, _, err := suite.transactionStorage.GetTransactions(context.Background(), filename, startDate, endDate)
+
+		g.Expect(err).Should(BeNil())
+		g.Expect(transactions).Should(Equal([]Transaction{
+			{ID: "1", Amount: 10.0, Type: Credit, Time: startDate},
+			{ID: "2", Amount: 200.0, Type: Debit, Time: endDate},
+		}))
 	})
 }
@@ -0,0 +1,5 @@
+package recon
+
+// Version of the recon tool, set at build time with
+// -ldflags "-X recon/recon.Version=v1.2.3".
+var Version = "dev"
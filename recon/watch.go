@@ -0,0 +1,464 @@
+package recon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	watchStateName    = "watch.json"
+	watchRunDirLayout = "20060102T150405"
+	// watchMaxRetryDelay caps the delay between retries of a failed run.
+	watchMaxRetryDelay = time.Hour
+)
+
+// errNoChangedItems is returned for changed files holding the same items as
+// in the last successful run.
+var errNoChangedItems = errors.New("no new or changed items")
+
+// WatchConfig says what a Watcher watches and how it reconciles.
+type WatchConfig struct {
+	// TransactionPath is the transaction file, watched like the directories.
+	TransactionPath string
+	// BankStatementDirs hold the CSV bank statement files, one directory per
+	// bank named after it, e.g. inbox/bca.
+	BankStatementDirs []string
+	// OutputDir gets one directory of reports per run, named after the time
+	// of the run, and the state of the watcher.
+	OutputDir string
+	// Debounce is how long the inputs must be left unchanged before a run.
+	Debounce time.Duration
+	// RetryDelay is how long after a failed run it is tried again, doubled
+	// with every further failure up to an hour. Zero retries at once.
+	RetryDelay time.Duration
+	// Location is the time zone the days of a run are in, UTC when nil.
+	Location *time.Location
+	Options  Options
+	// Notify tells about runs, with the workbook and the JSON report of a
+	// run attached to mails.
+	Notify NotifyConfig
+}
+
+// WatchRun is a recon run started by new or changed input files.
+type WatchRun struct {
+	At                 time.Time
+	OutputDir          string
+	TransactionPath    string
+	BankStatementPaths []string
+	// FileAccounts names the bank of each statement file after its directory.
+	FileAccounts map[string]BankAccount
+	// StartDate and EndDate span the days with items of Changed added,
+	// changed or removed since the last successful run.
+	StartDate time.Time
+	EndDate   time.Time
+	Changed   []string
+
+	// days digests the items of every day of the changed files by path.
+	days map[string]map[string]string
+}
+
+type watchRunner func(ctx context.Context, run WatchRun) error
+
+// watchState is persisted in the output directory so processed files are
+// skipped after a restart.
+type watchState struct {
+	// Processed holds the files that went into a successful run by checksum.
+	Processed map[string]processedFile `json:"processed"`
+	// Days digests the items of every day of a file as of its last
+	// successful run by path, so a run covers only the days that changed.
+	Days map[string]map[string]string `json:"days,omitempty"`
+}
+
+type processedFile struct {
+	Path        string    `json:"path"`
+	ProcessedAt time.Time `json:"processed_at"`
+	Output      string    `json:"output"`
+}
+
+// watchedFile is what the watcher last saw of a file.
+type watchedFile struct {
+	size      int64
+	modTime   time.Time
+	changedAt time.Time
+	pending   bool
+}
+
+// Watcher reconciles whenever input files are added or changed. Files are
+// polled; once every changed file has been left alone for the debounce time
+// the recon runs for the days whose items in the changed files were added,
+// changed or removed since the last successful run, so an append to a
+// cumulative file does not reconcile its whole history again. Files whose
+// content went into a successful run before are skipped.
+type Watcher struct {
+	config WatchConfig
+	logger *log.Logger
+	state  watchState
+	files  map[string]*watchedFile
+	// failures counts the failed runs since the last successful one or
+	// change, delaying the next run until retryAt.
+	failures int
+	retryAt  time.Time
+
+	run watchRunner
+	now func() time.Time
+}
+
+// NewWatcher watches the inputs of config, logging every run to logger.
+func NewWatcher(config WatchConfig, logger *log.Logger) (*Watcher, error) {
+	w := &Watcher{config: config, logger: logger, files: map[string]*watchedFile{}, now: time.Now}
+	w.run = w.runRecon
+	if w.config.Location == nil {
+		w.config.Location = time.UTC
+	}
+
+	err := config.Options.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	err = os.MkdirAll(config.OutputDir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create output dir error: %w", err)
+	}
+
+	w.state = watchState{Processed: map[string]processedFile{}}
+	data, err := os.ReadFile(filepath.Join(config.OutputDir, watchStateName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read watch state error: %w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &w.state)
+		if err != nil {
+			return nil, fmt.Errorf("decode watch state error: %w", err)
+		}
+	}
+	if w.state.Days == nil {
+		w.state.Days = map[string]map[string]string{}
+	}
+	return w, nil
+}
+
+// Watch polls the inputs every interval until ctx is done. Failed runs are
+// logged and retried with a growing delay until one succeeds, at once when
+// their files change.
+func (w *Watcher) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := w.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			w.logger.Printf("Recon failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll looks at the inputs once and runs the recon when every changed file
+// has settled.
+func (w *Watcher) poll(ctx context.Context) error {
+	now := w.now()
+	paths, err := w.inputs()
+	if err != nil {
+		return err
+	}
+
+	for path := range w.files {
+		if !slices.Contains(paths, path) {
+			delete(w.files, path)
+		}
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("stat %s error: %w", path, err)
+		}
+		seen, ok := w.files[path]
+		if !ok || seen.size != info.Size() || !seen.modTime.Equal(info.ModTime()) {
+			w.files[path] = &watchedFile{size: info.Size(), modTime: info.ModTime(), changedAt: now, pending: true}
+			w.failures, w.retryAt = 0, time.Time{}
+		}
+	}
+
+	var pending []string
+	for _, path := range paths {
+		if !w.files[path].pending {
+			continue
+		}
+		if now.Sub(w.files[path].changedAt) < w.config.Debounce {
+			return nil
+		}
+		pending = append(pending, path)
+	}
+	if len(pending) > 0 && now.Before(w.retryAt) {
+		return nil
+	}
+
+	changed := map[string]string{}
+	var changedPaths []string
+	for _, path := range pending {
+		checksum, err := fileChecksum(path)
+		if err != nil {
+			return err
+		}
+		if processed, ok := w.state.Processed[checksum]; ok {
+			w.files[path].pending = false
+			w.logger.Printf("Skipping %s, processed with %s in %s", path, processed.Path, processed.Output)
+			continue
+		}
+		changed[path] = checksum
+		changedPaths = append(changedPaths, path)
+	}
+	if len(changedPaths) == 0 {
+		return nil
+	}
+
+	run, err := w.newRun(ctx, now, paths, changedPaths)
+	if errors.Is(err, errNoChangedItems) {
+		w.logger.Printf("Skipping %s, %v", strings.Join(changedPaths, ", "), err)
+		return w.processed(run, changed)
+	}
+	if err != nil {
+		return w.retry(now, err)
+	}
+	w.logger.Printf("Reconciling %s to %s for %s", run.StartDate.Format(time.DateOnly), run.EndDate.Format(time.DateOnly), strings.Join(changedPaths, ", "))
+	err = w.run(ctx, run)
+	if err != nil {
+		os.RemoveAll(run.OutputDir)
+		return w.retry(now, err)
+	}
+	w.logger.Printf("Recon written to %s", run.OutputDir)
+	return w.processed(run, changed)
+}
+
+// processed records the changed files of run, by path to their checksum, as
+// processed and persists the state.
+func (w *Watcher) processed(run WatchRun, changed map[string]string) error {
+	w.failures, w.retryAt = 0, time.Time{}
+	for path, checksum := range changed {
+		w.files[path].pending = false
+		w.state.Processed[checksum] = processedFile{Path: path, ProcessedAt: run.At, Output: run.OutputDir}
+	}
+	for path, days := range run.days {
+		w.state.Days[path] = days
+	}
+	return w.persist()
+}
+
+// retry keeps the changed files pending after a failed run and delays the
+// next run, returning err.
+func (w *Watcher) retry(now time.Time, err error) error {
+	delay := w.config.RetryDelay
+	for range w.failures {
+		if delay >= watchMaxRetryDelay/2 {
+			delay = watchMaxRetryDelay
+			break
+		}
+		delay *= 2
+	}
+	w.failures++
+	w.retryAt = now.Add(delay)
+	w.logger.Printf("Retrying in %s", delay)
+	return err
+}
+
+// inputs lists the transaction file, if there is one, and the CSV files of
+// the bank statement directories.
+func (w *Watcher) inputs() ([]string, error) {
+	var paths []string
+	_, err := os.Stat(w.config.TransactionPath)
+	if err == nil {
+		paths = append(paths, w.config.TransactionPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("stat %s error: %w", w.config.TransactionPath, err)
+	}
+
+	for _, dir := range w.config.BankStatementDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("read dir %s error: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(entry.Name()), ".csv") {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return paths, nil
+}
+
+// newRun sets up a run over every input for the days with new, changed or
+// removed items in changed, in a new output directory. It returns
+// errNoChangedItems, without the directory, when no day changed.
+func (w *Watcher) newRun(ctx context.Context, at time.Time, paths []string, changed []string) (WatchRun, error) {
+	run := WatchRun{At: at, TransactionPath: w.config.TransactionPath, FileAccounts: map[string]BankAccount{}, Changed: changed}
+	for _, dir := range w.config.BankStatementDirs {
+		for _, path := range paths {
+			if filepath.Dir(path) == filepath.Clean(dir) {
+				run.BankStatementPaths = append(run.BankStatementPaths, path)
+				run.FileAccounts[path] = BankAccount{Bank: filepath.Base(dir)}
+			}
+		}
+	}
+	if !slices.Contains(paths, w.config.TransactionPath) {
+		return run, fmt.Errorf("transaction file %s not found", w.config.TransactionPath)
+	}
+
+	err := w.span(ctx, &run)
+	if err != nil {
+		return run, err
+	}
+
+	name := at.In(w.config.Location).Format(watchRunDirLayout)
+	for n := 2; ; n++ {
+		run.OutputDir = filepath.Join(w.config.OutputDir, name)
+		err = os.Mkdir(run.OutputDir, 0o755)
+		if !errors.Is(err, fs.ErrExist) {
+			break
+		}
+		name = at.In(w.config.Location).Format(watchRunDirLayout) + "-" + strconv.Itoa(n)
+	}
+	if err != nil {
+		return run, fmt.Errorf("create run dir error: %w", err)
+	}
+	return run, nil
+}
+
+// span sets the days of run to the earliest and the latest day whose items in
+// the changed files differ from the last successful run.
+func (w *Watcher) span(ctx context.Context, run *WatchRun) error {
+	// every item is in this period
+	first := time.Date(1, 1, 2, 0, 0, 0, 0, w.config.Location)
+	last := time.Date(9999, 12, 30, 0, 0, 0, 0, w.config.Location)
+	csvReaderFactory := CSVReaderFactory{}
+	transactions := NewTransactionStorage("", "", nil, csvReaderFactory)
+	statements := NewBankStatementStorage("", nil, csvReaderFactory).WithFileAccounts(run.FileAccounts)
+
+	run.days = map[string]map[string]string{}
+	var changedDays []string
+	for _, path := range run.Changed {
+		byDay := map[string][]string{}
+		if path == run.TransactionPath {
+			loaded, _, err := transactions.GetTransactions(ctx, path, first, last)
+			if err != nil {
+				return fmt.Errorf("read %s error: %w", path, err)
+			}
+			for _, t := range loaded {
+				t.Time = t.Time.In(w.config.Location)
+				day := t.Time.Format(time.DateOnly)
+				byDay[day] = append(byDay[day], fmt.Sprintf("%+v", t))
+			}
+		} else {
+			loaded, _, err := statements.GetBankStatements(ctx, path, first, last)
+			if err != nil {
+				return fmt.Errorf("read %s error: %w", path, err)
+			}
+			for _, s := range loaded {
+				s.Time = s.Time.In(w.config.Location)
+				day := s.Time.Format(time.DateOnly)
+				byDay[day] = append(byDay[day], fmt.Sprintf("%+v", s))
+			}
+		}
+
+		days := dayDigests(byDay)
+		previous := w.state.Days[path]
+		for day, digest := range days {
+			if previous[day] != digest {
+				changedDays = append(changedDays, day)
+			}
+		}
+		for day := range previous {
+			if _, ok := days[day]; !ok {
+				changedDays = append(changedDays, day)
+			}
+		}
+		run.days[path] = days
+	}
+	if len(changedDays) == 0 {
+		return errNoChangedItems
+	}
+
+	// days sort like their dates
+	var err error
+	run.StartDate, err = time.ParseInLocation(time.DateOnly, slices.Min(changedDays), w.config.Location)
+	if err != nil {
+		return err
+	}
+	run.EndDate, err = time.ParseInLocation(time.DateOnly, slices.Max(changedDays), w.config.Location)
+	return err
+}
+
+// dayDigests digests the items of every day, sorted so the order of the
+// lines in a file does not matter.
+func dayDigests(byDay map[string][]string) map[string]string {
+	days := map[string]string{}
+	for day, items := range byDay {
+		slices.Sort(items)
+		h := sha256.New()
+		for _, item := range items {
+			io.WriteString(h, item+"\n")
+		}
+		days[day] = hex.EncodeToString(h.Sum(nil))
+	}
+	return days
+}
+
+// runRecon runs the recon writing the workbook and the JSON report into the
+// output directory of run.
+func (w *Watcher) runRecon(ctx context.Context, run WatchRun) error {
+	executor := withDirNotifiers(newDirExecutor(run.OutputDir, run.FileAccounts, w.config.Options), run.OutputDir, w.config.Notify, w.logger)
+	return executor.Execute(ctx, run.TransactionPath, run.BankStatementPaths, run.StartDate, run.EndDate)
+}
+
+// persist writes the state to a temporary file first so a crash never
+// leaves it half written.
+func (w *Watcher) persist() error {
+	data, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode watch state error: %w", err)
+	}
+	path := filepath.Join(w.config.OutputDir, watchStateName)
+	err = os.WriteFile(path+".tmp", data, 0o644)
+	if err != nil {
+		return fmt.Errorf("write watch state error: %w", err)
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return fmt.Errorf("write watch state error: %w", err)
+	}
+	return nil
+}
+
+// fileChecksum is the SHA-256 of the content of path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s error: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("read %s error: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,258 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type watchSuite struct {
+	dir      string
+	bca      string
+	watcher  *Watcher
+	runs     []WatchRun
+	runError error
+	clock    time.Time
+}
+
+func getWatchSuite(g *WithT, dir string) *watchSuite {
+	suite := &watchSuite{dir: dir, bca: filepath.Join(dir, "inbox", "bca"), clock: time.Date(2025, 8, 2, 9, 0, 0, 0, time.UTC)}
+	g.Expect(os.MkdirAll(suite.bca, 0o755)).Should(Succeed())
+
+	watcher, err := NewWatcher(WatchConfig{
+		TransactionPath:   filepath.Join(dir, "transaction.csv"),
+		BankStatementDirs: []string{suite.bca},
+		OutputDir:         filepath.Join(dir, "out"),
+		Debounce:          time.Minute,
+	}, log.New(io.Discard, "", 0))
+	g.Expect(err).Should(BeNil())
+	watcher.now = func() time.Time { return suite.clock }
+	watcher.run = func(_ context.Context, run WatchRun) error {
+		suite.runs = append(suite.runs, run)
+		return suite.runError
+	}
+	suite.watcher = watcher
+	return suite
+}
+
+func (s *watchSuite) write(g *WithT, path, content string) {
+	g.Expect(os.WriteFile(path, []byte(content), 0o644)).Should(Succeed())
+	// tell writes within the same clock tick apart
+	s.clock = s.clock.Add(time.Second)
+	os.Chtimes(path, s.clock, s.clock)
+}
+
+func TestWatcher(t *testing.T) {
+	transactions := "id,amount,type,time\n1,100,debit,2025-08-01T10:00:00Z\n"
+	statements := "id,amount,time\na,100,2025-07-31T12:00:00Z\nb,70,2025-08-02T12:00:00Z\n"
+
+	t.Run("runs once the changed files settle, for the days of their items", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		suite := getWatchSuite(g, t.TempDir())
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		suite.write(g, filepath.Join(suite.bca, "export-1.csv"), statements)
+
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(BeEmpty())
+
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(1))
+		run := suite.runs[0]
+		g.Expect(run.StartDate).Should(Equal(time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)))
+		g.Expect(run.EndDate).Should(Equal(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)))
+		g.Expect(run.BankStatementPaths).Should(Equal([]string{filepath.Join(suite.bca, "export-1.csv")}))
+		g.Expect(run.FileAccounts[run.BankStatementPaths[0]]).Should(Equal(BankAccount{Bank: "bca"}))
+		g.Expect(run.OutputDir).Should(Equal(filepath.Join(suite.dir, "out", "20250802T090102")))
+		g.Expect(run.OutputDir).Should(BeADirectory())
+
+		// nothing changed
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(1))
+	})
+
+	t.Run("waits while files keep changing", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		suite := getWatchSuite(g, t.TempDir())
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+
+		suite.clock = suite.clock.Add(50 * time.Second)
+		suite.write(g, filepath.Join(suite.bca, "export-1.csv"), statements)
+		suite.clock = suite.clock.Add(20 * time.Second)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(BeEmpty())
+
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(1))
+		g.Expect(suite.runs[0].Changed).Should(HaveLen(2))
+	})
+
+	t.Run("skips files already processed, also after a restart", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		suite := getWatchSuite(g, dir)
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		suite.write(g, filepath.Join(suite.bca, "export-1.csv"), statements)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(1))
+
+		restarted := getWatchSuite(g, dir)
+		restarted.clock = suite.clock.Add(time.Hour)
+		// a copy of a processed export and a new one
+		restarted.write(g, filepath.Join(restarted.bca, "export-1-copy.csv"), statements)
+		restarted.write(g, filepath.Join(restarted.bca, "export-2.csv"), "id,amount,time\nc,30,2025-08-03T08:00:00Z\n")
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		restarted.clock = restarted.clock.Add(time.Minute)
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+
+		g.Expect(restarted.runs).Should(HaveLen(1))
+		run := restarted.runs[0]
+		g.Expect(run.Changed).Should(Equal([]string{filepath.Join(restarted.bca, "export-2.csv")}))
+		g.Expect(run.BankStatementPaths).Should(HaveLen(3))
+		g.Expect(run.StartDate).Should(Equal(time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)))
+		g.Expect(run.EndDate).Should(Equal(run.StartDate))
+	})
+
+	t.Run("runs for the days with new or changed items only, also after a restart", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dir := t.TempDir()
+		suite := getWatchSuite(g, dir)
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		suite.write(g, filepath.Join(suite.bca, "export-1.csv"), statements)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(1))
+
+		// an append to the cumulative transaction file
+		restarted := getWatchSuite(g, dir)
+		restarted.clock = suite.clock.Add(time.Hour)
+		restarted.write(g, restarted.watcher.config.TransactionPath, transactions+"2,50,debit,2025-08-05T11:00:00Z\n")
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		restarted.clock = restarted.clock.Add(time.Minute)
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(restarted.runs).Should(HaveLen(1))
+		g.Expect(restarted.runs[0].StartDate).Should(Equal(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)))
+		g.Expect(restarted.runs[0].EndDate).Should(Equal(restarted.runs[0].StartDate))
+
+		// a changed and a removed item
+		restarted.write(g, filepath.Join(restarted.bca, "export-1.csv"), "id,amount,time\na,90,2025-07-31T12:00:00Z\n")
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		restarted.clock = restarted.clock.Add(time.Minute)
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(restarted.runs).Should(HaveLen(2))
+		g.Expect(restarted.runs[1].StartDate).Should(Equal(time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)))
+		g.Expect(restarted.runs[1].EndDate).Should(Equal(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)))
+
+		// the same items in another order
+		restarted.write(g, restarted.watcher.config.TransactionPath, "id,amount,type,time\n2,50,debit,2025-08-05T11:00:00Z\n1,100,debit,2025-08-01T10:00:00Z\n")
+		restarted.clock = restarted.clock.Add(time.Minute)
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(restarted.runs).Should(HaveLen(2))
+		restarted.clock = restarted.clock.Add(time.Minute)
+		g.Expect(restarted.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(restarted.runs).Should(HaveLen(2))
+	})
+
+	t.Run("retries failed runs with a growing delay until one succeeds", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		suite := getWatchSuite(g, t.TempDir())
+		suite.watcher.config.RetryDelay = 5 * time.Minute
+		suite.runError = errors.New("boom")
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+
+		g.Expect(suite.watcher.poll(context.Background())).Should(MatchError("boom"))
+		g.Expect(suite.runs[0].OutputDir).ShouldNot(BeADirectory())
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(1))
+
+		suite.clock = suite.clock.Add(4 * time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(MatchError("boom"))
+		g.Expect(suite.runs).Should(HaveLen(2))
+
+		// the delay doubles
+		suite.runError = nil
+		suite.clock = suite.clock.Add(9 * time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(2))
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(3))
+
+		suite.clock = suite.clock.Add(time.Hour)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(3))
+	})
+
+	t.Run("retries failed files at once when they change", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		suite := getWatchSuite(g, t.TempDir())
+		suite.watcher.config.RetryDelay = time.Hour
+		suite.runError = errors.New("boom")
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(MatchError("boom"))
+
+		suite.runError = nil
+		suite.write(g, suite.watcher.config.TransactionPath, transactions+"2,50,debit,2025-08-01T11:00:00Z\n")
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		g.Expect(suite.runs).Should(HaveLen(2))
+	})
+
+	t.Run("writes the reports of a run", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		suite := getWatchSuite(g, t.TempDir())
+		suite.watcher.run = suite.watcher.runRecon
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		suite.write(g, filepath.Join(suite.bca, "export-1.csv"), statements)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+
+		output := filepath.Join(suite.dir, "out", "20250802T090102")
+		g.Expect(filepath.Join(output, jobWorkbookName)).Should(BeAnExistingFile())
+		g.Expect(filepath.Join(output, jobJSONReportName)).Should(BeAnExistingFile())
+		g.Expect(filepath.Join(suite.dir, "out", watchStateName)).Should(BeAnExistingFile())
+	})
+	t.Run("keeps the reports of a run when a notifier fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		suite := getWatchSuite(g, t.TempDir())
+		server, bodies := newHookStandIn(t, http.StatusInternalServerError)
+		suite.watcher.config.Notify = NotifyConfig{NotifyRules: NotifyRules{OnCompletion: true}, Webhooks: []string{server.URL}}
+		suite.watcher.run = suite.watcher.runRecon
+		suite.write(g, suite.watcher.config.TransactionPath, transactions)
+		suite.write(g, filepath.Join(suite.bca, "export-1.csv"), statements)
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+		suite.clock = suite.clock.Add(time.Minute)
+
+		g.Expect(suite.watcher.poll(context.Background())).Should(Succeed())
+
+		g.Expect(bodies).Should(Receive())
+		output := filepath.Join(suite.dir, "out", "20250802T090102")
+		g.Expect(filepath.Join(output, jobWorkbookName)).Should(BeAnExistingFile())
+		g.Expect(filepath.Join(output, jobJSONReportName)).Should(BeAnExistingFile())
+		state, err := os.ReadFile(filepath.Join(suite.dir, "out", watchStateName))
+		g.Expect(err).Should(BeNil())
+		g.Expect(string(state)).Should(ContainSubstring(output))
+	})
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"recon/recon"
+	"syscall"
+	"text/tabwriter"
+)
+
+// schedule runs the schedules of a config until interrupted, or lists the
+// history of their runs.
+func schedule(args []string) {
+	flags := flag.NewFlagSet("schedule", flag.ExitOnError)
+	configPath := flags.String("config", "schedule.yaml", "YAML file listing the schedules")
+	history := flags.Bool("history", false, "list the runs of the schedules instead of running them")
+	flags.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	config, err := recon.LoadScheduleConfig(ctx, recon.CSVReaderFactory{}, *configPath)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if *history {
+		runs, err := recon.ReadScheduleHistory(config.OutputDir)
+		if err != nil {
+			log.Panic(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SCHEDULE\tSCHEDULED AT\tSTART DATE\tEND DATE\tSTATUS\tOUTPUT\tERROR")
+		for _, run := range runs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", run.Schedule, run.ScheduledAt.Format("2006-01-02 15:04"), run.StartDate, run.EndDate, run.Status, run.OutputDir, run.Error)
+		}
+		w.Flush()
+		return
+	}
+
+	var holidays []recon.Holiday
+	for _, path := range config.HolidayPaths {
+		loaded, _, err := recon.NewHolidayStorage(recon.CSVReaderFactory{}).GetHolidays(ctx, path)
+		if err != nil {
+			log.Panic(err)
+		}
+		holidays = append(holidays, loaded...)
+	}
+
+	scheduler, err := recon.NewScheduler(config, recon.NewCalendar(holidays), log.Default())
+	if err != nil {
+		log.Panic(err)
+	}
+	scheduler.Run(ctx)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"recon/recon"
+	"syscall"
+	"time"
+)
+
+// serve runs the HTTP API until interrupted, then waits for running jobs for
+// up to the shutdown timeout.
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	workers := flags.Int("workers", 2, "number of recon jobs running at once")
+	shutdownTimeout := flags.Duration("shutdown-timeout", time.Minute, "time given to running jobs on shutdown before they are canceled and queued for the next start")
+	jobsDir := flags.String("jobs-dir", filepath.Join(os.TempDir(), "recon-jobs"), "directory holding one directory of uploads and reports per job")
+	flags.Parse(args)
+
+	err := os.MkdirAll(*jobsDir, 0o755)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	jobs, err := recon.NewJobManager(*jobsDir, *workers, log.Default())
+	if err != nil {
+		log.Panic(err)
+	}
+	httpServer := &http.Server{Addr: *addr, Handler: recon.NewServer(jobs)}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		httpServer.Shutdown(context.Background())
+	}()
+
+	log.Printf("Serving recon API on %s, jobs in %s", *addr, *jobsDir)
+	err = httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Panic(err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	err = jobs.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Printf("Running jobs canceled: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"recon/recon"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// watch reconciles whenever new or changed input files land, until
+// interrupted.
+func watch(args []string) {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	transactionPath := flags.String("transaction-path", "transaction.csv", "transactions CSV file, watched for changes")
+	bankStatementDirs := flags.String("bank-statement-dirs", "inbox/bca,inbox/bri", "directories of bank statement CSV files, one per bank named after it, comma separated")
+	outDir := flags.String("out-dir", "data/watch", "directory getting one timestamped directory of reports per run")
+	interval := flags.Duration("interval", 10*time.Second, "time between looks at the inputs")
+	debounce := flags.Duration("debounce", 30*time.Second, "time the inputs must be left unchanged before a run")
+	retryDelay := flags.Duration("retry-delay", time.Minute, "time before a failed run is tried again, doubled with every further failure up to an hour")
+	timezone := flags.String("timezone", "UTC", "business time zone the days of a run are in, e.g. Asia/Jakarta")
+	duplicateKeys := flags.String("duplicate-keys", "", "fields identifying repeated items, comma separated (id, amount-time, reference), disabled when empty")
+	duplicatePolicy := flags.String("duplicate-policy", "flag", "what to do with repeated items: reject, keep-first or flag")
+	notifyConfig := notifyFlags(flags)
+	flags.Parse(args)
+
+	location, err := time.LoadLocation(*timezone)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	var dirs []string
+	for _, dir := range strings.Split(*bankStatementDirs, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	var keys []recon.DuplicateKey
+	for _, key := range strings.Split(*duplicateKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, recon.DuplicateKey(key))
+		}
+	}
+
+	watcher, err := recon.NewWatcher(recon.WatchConfig{
+		TransactionPath:   *transactionPath,
+		BankStatementDirs: dirs,
+		OutputDir:         *outDir,
+		Debounce:          *debounce,
+		RetryDelay:        *retryDelay,
+		Location:          location,
+		Notify:            notifyConfig(),
+		Options: recon.Options{
+			RunArguments: os.Args[1:],
+			Duplicates:   recon.DuplicateConfig{Keys: keys, Policy: recon.DuplicatePolicy(*duplicatePolicy)},
+		},
+	}, log.Default())
+	if err != nil {
+		log.Panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Watching %s and %s, reports in %s", *transactionPath, strings.Join(dirs, ", "), *outDir)
+	watcher.Watch(ctx, *interval)
+}